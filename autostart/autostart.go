@@ -0,0 +1,130 @@
+package autostart
+
+import (
+	"errors"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Options describe how the server should be started by the OS service manager
+type Options struct {
+	ExecBinary string
+	Address    common.ServerAddress
+	// empty for the default profile
+	Profile string
+	// the server is started on the first connection to its address rather than on login: supported by systemd only
+	SocketActivation bool
+}
+
+// File is the file to be installed for the OS service manager
+type File struct {
+	Path    string
+	Content string
+}
+
+// Manager generates the autostart files for the OS service manager, and provides the commands to (de)activate them
+type Manager interface {
+	Files(options Options) ([]File, error)
+	// Paths returns all the files that might have been installed for the profile
+	Paths(profile string) []string
+	// the commands expect the paths of the installed files
+	EnableCommands(installed []string) [][]string
+	DisableCommands(installed []string) [][]string
+	StatusCommands(installed []string) [][]string
+}
+
+// NewManager returns the manager of the current OS: systemd on Linux, launchd on MacOS
+func NewManager() (Manager, error) {
+	homeDir := os.Getenv("HOME")
+	if homeDir == "" {
+		return nil, errors.New("can't resolve home directory")
+	}
+
+	switch utils.DetectOsType() {
+	case common.LinuxOS:
+		configDir := os.Getenv("XDG_CONFIG_HOME")
+		if configDir == "" {
+			configDir = filepath.Join(homeDir, ".config")
+		}
+		return systemdManager{unitsDir: filepath.Join(configDir, "systemd", "user")}, nil
+	case common.MacOS:
+		return launchdManager{agentsDir: filepath.Join(homeDir, "Library", "LaunchAgents")}, nil
+	default:
+		return nil, common.ErrAutostartUnsupportedOs
+	}
+}
+
+// Install writes the files rendered by the manager
+func Install(files []File) error {
+	for _, file := range files {
+		err := os.MkdirAll(filepath.Dir(file.Path), 0755)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(file.Path, []byte(file.Content), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InstalledPaths returns the paths of the files installed for the profile, if any
+func InstalledPaths(manager Manager, profile string) []string {
+	installed := make([]string, 0)
+	for _, path := range manager.Paths(profile) {
+		if _, err := os.Stat(path); err == nil {
+			installed = append(installed, path)
+		}
+	}
+	return installed
+}
+
+func Uninstall(installed []string) error {
+	for _, path := range installed {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunCommands runs the commands one by one, and stops on the first failed one
+func RunCommands(commands [][]string) (string, error) {
+	output := strings.Builder{}
+	for _, command := range commands {
+		commandOutput, err := exec.Command(command[0], command[1:]...).CombinedOutput()
+		output.Write(commandOutput)
+		if err != nil {
+			return output.String(), errors.New(strings.Join(command, " ") + ": " + err.Error())
+		}
+	}
+	return output.String(), nil
+}
+
+// serverArgs returns the command line to start the server with
+func serverArgs(options Options) []string {
+	args := []string{
+		options.ExecBinary, "admin", "server", "start",
+		"--" + common.TransportFlag, options.Address.Transport,
+		"--" + common.PortFlag, strconv.Itoa(options.Address.Port),
+	}
+	if options.Profile != "" {
+		args = append(args, "--"+common.ProfileFlag, options.Profile)
+	}
+	return args
+}
+
+// unitName returns the name the files are installed under: each profile gets its own one
+func unitName(profile string) string {
+	if profile == "" {
+		return "remindme"
+	}
+	return "remindme-" + profile
+}
@@ -0,0 +1,341 @@
+package autostart
+
+import (
+	"errors"
+	"n0rdy.foo/remindme/common"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func unixOptions(home string) Options {
+	return Options{
+		ExecBinary: "/usr/local/bin/remindme",
+		Address: common.ServerAddress{
+			Transport:  common.UnixTransport,
+			Port:       15555,
+			SocketPath: filepath.Join(home, ".local", "share", "remindme", "remindme.sock"),
+		},
+	}
+}
+
+func testSystemdManager(t *testing.T) (systemdManager, string) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	return systemdManager{unitsDir: filepath.Join(home, ".config", "systemd", "user")}, home
+}
+
+func testLaunchdManager(t *testing.T) (launchdManager, string) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	return launchdManager{agentsDir: filepath.Join(home, "Library", "LaunchAgents")}, home
+}
+
+func assertContains(t *testing.T, content string, expected ...string) {
+	t.Helper()
+	for _, e := range expected {
+		if !strings.Contains(content, e) {
+			t.Errorf("expected %q in:\n%s", e, content)
+		}
+	}
+}
+
+func assertNotContains(t *testing.T, content string, unexpected ...string) {
+	t.Helper()
+	for _, u := range unexpected {
+		if strings.Contains(content, u) {
+			t.Errorf("unexpected %q in:\n%s", u, content)
+		}
+	}
+}
+
+func install(t *testing.T, manager Manager, options Options) []File {
+	t.Helper()
+	files, err := manager.Files(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Install(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestNewManagerResolvesDirsFromHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	manager, err := NewManager()
+	if errors.Is(err, common.ErrAutostartUnsupportedOs) {
+		t.Skip("autostart is not supported on this OS")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range manager.Paths("") {
+		if !strings.HasPrefix(path, home+string(filepath.Separator)) {
+			t.Errorf("expected %s to be within the home dir %s", path, home)
+		}
+	}
+}
+
+func TestNewManagerFailsWithoutHome(t *testing.T) {
+	t.Setenv("HOME", "")
+
+	_, err := NewManager()
+	if err == nil {
+		t.Fatal("expected an error if the home dir is unknown")
+	}
+}
+
+func TestSystemdFilesWithoutSocketActivation(t *testing.T) {
+	manager, home := testSystemdManager(t)
+
+	files, err := manager.Files(unixOptions(home))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 1 {
+		t.Fatalf("expected the service unit only, got %d files", len(files))
+	}
+	if expected := filepath.Join(home, ".config", "systemd", "user", "remindme.service"); files[0].Path != expected {
+		t.Errorf("expected %s, got %s", expected, files[0].Path)
+	}
+	assertContains(t, files[0].Content,
+		"Description=remindme server\n",
+		"ExecStart=/usr/local/bin/remindme admin server start --transport unix --port 15555\n",
+		"Restart=on-failure",
+		"[Install]\nWantedBy=default.target",
+	)
+	assertNotContains(t, files[0].Content, "Requires=", "After=", "--profile")
+}
+
+func TestSystemdFilesWithSocketActivation(t *testing.T) {
+	manager, home := testSystemdManager(t)
+	options := unixOptions(home)
+	options.SocketActivation = true
+
+	files, err := manager.Files(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("expected the service and socket units, got %d files", len(files))
+	}
+	unitsDir := filepath.Join(home, ".config", "systemd", "user")
+	if expected := filepath.Join(unitsDir, "remindme.service"); files[0].Path != expected {
+		t.Errorf("expected %s, got %s", expected, files[0].Path)
+	}
+	if expected := filepath.Join(unitsDir, "remindme.socket"); files[1].Path != expected {
+		t.Errorf("expected %s, got %s", expected, files[1].Path)
+	}
+
+	// the service is started by the socket, so it's not installed to start on login
+	assertContains(t, files[0].Content, "Requires=remindme.socket\n", "After=remindme.socket\n")
+	assertNotContains(t, files[0].Content, "[Install]", "WantedBy=")
+
+	assertContains(t, files[1].Content,
+		"ListenStream="+options.Address.SocketPath+"\n",
+		"SocketMode=0600\n",
+		"WantedBy=sockets.target",
+	)
+}
+
+func TestSystemdSocketListensOnLoopbackForTcp(t *testing.T) {
+	manager, home := testSystemdManager(t)
+	options := unixOptions(home)
+	options.Address.Transport = common.TcpTransport
+	options.SocketActivation = true
+
+	files, err := manager.Files(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertContains(t, files[0].Content, "--transport tcp --port 15555")
+	assertContains(t, files[1].Content, "ListenStream=127.0.0.1:15555\n")
+}
+
+func TestSystemdFilesPerProfile(t *testing.T) {
+	manager, home := testSystemdManager(t)
+	options := unixOptions(home)
+	options.Profile = "work"
+	options.ExecBinary = "/opt/my apps/remindme"
+	options.SocketActivation = true
+
+	files, err := manager.Files(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Base(files[0].Path) != "remindme-work.service" || filepath.Base(files[1].Path) != "remindme-work.socket" {
+		t.Errorf("expected the profile units, got %s and %s", files[0].Path, files[1].Path)
+	}
+	assertContains(t, files[0].Content,
+		"Description=remindme server (work profile)",
+		`ExecStart="/opt/my apps/remindme" admin server start`,
+		"--profile work\n",
+		"Requires=remindme-work.socket",
+	)
+	assertContains(t, files[1].Content, "Description=remindme server socket (work profile)")
+}
+
+func TestSystemdEnableCommandsPreferSocket(t *testing.T) {
+	manager, _ := testSystemdManager(t)
+
+	service := filepath.Join(manager.unitsDir, "remindme.service")
+	socket := filepath.Join(manager.unitsDir, "remindme.socket")
+
+	commands := manager.EnableCommands([]string{service})
+	if !slices.Equal(commands[len(commands)-1], []string{"systemctl", "--user", "enable", "--now", "remindme.service"}) {
+		t.Errorf("unexpected enable command: %v", commands)
+	}
+	commands = manager.EnableCommands([]string{service, socket})
+	if !slices.Equal(commands[len(commands)-1], []string{"systemctl", "--user", "enable", "--now", "remindme.socket"}) {
+		t.Errorf("unexpected enable command: %v", commands)
+	}
+}
+
+func TestLaunchdFiles(t *testing.T) {
+	manager, home := testLaunchdManager(t)
+
+	files, err := manager.Files(unixOptions(home))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 1 {
+		t.Fatalf("expected the plist only, got %d files", len(files))
+	}
+	if expected := filepath.Join(home, "Library", "LaunchAgents", "foo.n0rdy.remindme.plist"); files[0].Path != expected {
+		t.Errorf("expected %s, got %s", expected, files[0].Path)
+	}
+	assertContains(t, files[0].Content,
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		"<key>Label</key>\n\t<string>foo.n0rdy.remindme</string>",
+		"<string>/usr/local/bin/remindme</string>\n\t\t<string>admin</string>\n\t\t<string>server</string>\n\t\t<string>start</string>",
+		"<string>--transport</string>\n\t\t<string>unix</string>",
+		"<string>--port</string>\n\t\t<string>15555</string>",
+		"<key>RunAtLoad</key>\n\t<true/>",
+		"<key>SuccessfulExit</key>\n\t\t<false/>",
+	)
+	assertNotContains(t, files[0].Content, "--profile")
+}
+
+func TestLaunchdFilesPerProfileEscaped(t *testing.T) {
+	manager, home := testLaunchdManager(t)
+	options := unixOptions(home)
+	options.Profile = "work"
+	options.ExecBinary = "/Applications/R&D <tools>/remindme"
+
+	files, err := manager.Files(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := filepath.Join(home, "Library", "LaunchAgents", "foo.n0rdy.remindme-work.plist"); files[0].Path != expected {
+		t.Errorf("expected %s, got %s", expected, files[0].Path)
+	}
+	assertContains(t, files[0].Content,
+		"<string>foo.n0rdy.remindme-work</string>",
+		"<string>/Applications/R&amp;D &lt;tools&gt;/remindme</string>",
+		"<string>--profile</string>\n\t\t<string>work</string>",
+	)
+	if !slices.Equal(manager.Paths("work"), []string{files[0].Path}) {
+		t.Errorf("expected the paths to match the installed plist, got %v", manager.Paths("work"))
+	}
+	if status := manager.StatusCommands([]string{files[0].Path}); !slices.Equal(status[0], []string{"launchctl", "list", "foo.n0rdy.remindme-work"}) {
+		t.Errorf("unexpected status command: %v", status)
+	}
+}
+
+func TestLaunchdRejectsSocketActivation(t *testing.T) {
+	manager, home := testLaunchdManager(t)
+	options := unixOptions(home)
+	options.SocketActivation = true
+
+	_, err := manager.Files(options)
+	if !errors.Is(err, common.ErrAutostartSocketActivationUnsupported) {
+		t.Errorf("expected socket activation to be rejected, got %v", err)
+	}
+}
+
+func TestInstallAndUninstallAreIdempotent(t *testing.T) {
+	managers := map[string]func(t *testing.T) (Manager, string){
+		"systemd": func(t *testing.T) (Manager, string) { return testSystemdManager(t) },
+		"launchd": func(t *testing.T) (Manager, string) { return testLaunchdManager(t) },
+	}
+	for name, newManager := range managers {
+		t.Run(name, func(t *testing.T) {
+			manager, home := newManager(t)
+			options := unixOptions(home)
+
+			if installed := InstalledPaths(manager, options.Profile); len(installed) != 0 {
+				t.Fatalf("expected nothing installed, got %v", installed)
+			}
+
+			first := install(t, manager, options)
+			second := install(t, manager, options)
+			if !slices.Equal(first, second) {
+				t.Errorf("expected the same files on reinstall, got %v and %v", first, second)
+			}
+			for _, file := range second {
+				content, err := os.ReadFile(file.Path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(content) != file.Content {
+					t.Errorf("unexpected content of %s:\n%s", file.Path, content)
+				}
+			}
+
+			installed := InstalledPaths(manager, options.Profile)
+			if len(installed) != len(first) {
+				t.Fatalf("expected %d installed files, got %v", len(first), installed)
+			}
+
+			err := Uninstall(installed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = Uninstall(installed)
+			if err != nil {
+				t.Fatalf("expected the second uninstall to succeed, got %v", err)
+			}
+			if left := InstalledPaths(manager, options.Profile); len(left) != 0 {
+				t.Errorf("expected nothing installed, got %v", left)
+			}
+		})
+	}
+}
+
+func TestReinstallWithoutSocketActivationLeavesStaleSocketToUninstall(t *testing.T) {
+	manager, home := testSystemdManager(t)
+	options := unixOptions(home)
+	options.SocketActivation = true
+
+	install(t, manager, options)
+	options.SocketActivation = false
+	install(t, manager, options)
+
+	// the socket unit from the previous install is still reported, so that it gets disabled and removed on uninstall
+	installed := InstalledPaths(manager, options.Profile)
+	if len(installed) != 2 {
+		t.Fatalf("expected both units to be reported, got %v", installed)
+	}
+	err := Uninstall(installed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left := InstalledPaths(manager, options.Profile); len(left) != 0 {
+		t.Errorf("expected nothing installed, got %v", left)
+	}
+}
@@ -0,0 +1,92 @@
+package autostart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"n0rdy.foo/remindme/common"
+	"path/filepath"
+	"text/template"
+)
+
+const launchdLabelPrefix = "foo.n0rdy."
+
+var plistTemplate = template.Must(template.New("plist").Funcs(template.FuncMap{"xml": escapeXml}).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{xml .Label}}</string>
+	<key>ProgramArguments</key>
+	<array>
+{{- range .Args}}
+		<string>{{xml .}}</string>
+{{- end}}
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+</dict>
+</plist>
+`))
+
+type launchdManager struct {
+	agentsDir string
+}
+
+type launchdAgent struct {
+	Label string
+	Args  []string
+}
+
+func (lm launchdManager) Files(options Options) ([]File, error) {
+	if options.SocketActivation {
+		return nil, common.ErrAutostartSocketActivationUnsupported
+	}
+
+	agent := launchdAgent{
+		Label: launchdLabel(options.Profile),
+		Args:  serverArgs(options),
+	}
+	plist, err := render(plistTemplate, agent)
+	if err != nil {
+		return nil, err
+	}
+	return []File{{Path: lm.plistPath(options.Profile), Content: plist}}, nil
+}
+
+func (lm launchdManager) Paths(profile string) []string {
+	return []string{lm.plistPath(profile)}
+}
+
+func (lm launchdManager) EnableCommands(installed []string) [][]string {
+	return [][]string{{"launchctl", "load", "-w", installed[0]}}
+}
+
+func (lm launchdManager) DisableCommands(installed []string) [][]string {
+	return [][]string{{"launchctl", "unload", "-w", installed[0]}}
+}
+
+func (lm launchdManager) StatusCommands(installed []string) [][]string {
+	label := filepath.Base(installed[0])
+	label = label[:len(label)-len(filepath.Ext(label))]
+	return [][]string{{"launchctl", "list", label}}
+}
+
+func (lm launchdManager) plistPath(profile string) string {
+	return filepath.Join(lm.agentsDir, launchdLabel(profile)+".plist")
+}
+
+// launchdLabel follows the reverse domain name convention: e.g. foo.n0rdy.remindme-work
+func launchdLabel(profile string) string {
+	return launchdLabelPrefix + unitName(profile)
+}
+
+func escapeXml(value string) (string, error) {
+	buf := bytes.Buffer{}
+	err := xml.EscapeText(&buf, []byte(value))
+	return buf.String(), err
+}
@@ -0,0 +1,137 @@
+package autostart
+
+import (
+	"bytes"
+	"n0rdy.foo/remindme/common"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var serviceUnitTemplate = template.Must(template.New("service").Parse(`[Unit]
+Description=remindme server{{if .Profile}} ({{.Profile}} profile){{end}}
+{{- if .SocketUnit}}
+Requires={{.SocketUnit}}
+After={{.SocketUnit}}
+{{- end}}
+
+[Service]
+Type=simple
+ExecStart={{.ExecStart}}
+Restart=on-failure
+{{- if not .SocketUnit}}
+
+[Install]
+WantedBy=default.target
+{{- end}}
+`))
+
+var socketUnitTemplate = template.Must(template.New("socket").Parse(`[Unit]
+Description=remindme server socket{{if .Profile}} ({{.Profile}} profile){{end}}
+
+[Socket]
+ListenStream={{.ListenStream}}
+SocketMode=0600
+
+[Install]
+WantedBy=sockets.target
+`))
+
+type systemdManager struct {
+	unitsDir string
+}
+
+type systemdUnit struct {
+	Profile      string
+	ExecStart    string
+	SocketUnit   string
+	ListenStream string
+}
+
+func (sm systemdManager) Files(options Options) ([]File, error) {
+	name := unitName(options.Profile)
+	unit := systemdUnit{
+		Profile:   options.Profile,
+		ExecStart: systemdCommandLine(serverArgs(options)),
+	}
+	if options.SocketActivation {
+		unit.SocketUnit = name + ".socket"
+		unit.ListenStream = options.Address.SocketPath
+		if options.Address.Transport == common.TcpTransport {
+			unit.ListenStream = "127.0.0.1:" + strconv.Itoa(options.Address.Port)
+		}
+	}
+
+	files := make([]File, 0, 2)
+	service, err := render(serviceUnitTemplate, unit)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, File{Path: filepath.Join(sm.unitsDir, name+".service"), Content: service})
+
+	if options.SocketActivation {
+		socket, err := render(socketUnitTemplate, unit)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: filepath.Join(sm.unitsDir, name+".socket"), Content: socket})
+	}
+	return files, nil
+}
+
+func (sm systemdManager) Paths(profile string) []string {
+	name := unitName(profile)
+	return []string{
+		filepath.Join(sm.unitsDir, name+".service"),
+		filepath.Join(sm.unitsDir, name+".socket"),
+	}
+}
+
+// EnableCommands enables the socket if it's installed, as the service is started by it then
+func (sm systemdManager) EnableCommands(installed []string) [][]string {
+	unit := filepath.Base(installed[0])
+	for _, path := range installed {
+		if strings.HasSuffix(path, ".socket") {
+			unit = filepath.Base(path)
+		}
+	}
+	return [][]string{
+		{"systemctl", "--user", "daemon-reload"},
+		{"systemctl", "--user", "enable", "--now", unit},
+	}
+}
+
+func (sm systemdManager) DisableCommands(installed []string) [][]string {
+	command := []string{"systemctl", "--user", "disable", "--now"}
+	for _, path := range installed {
+		command = append(command, filepath.Base(path))
+	}
+	return [][]string{command}
+}
+
+func (sm systemdManager) StatusCommands(installed []string) [][]string {
+	command := []string{"systemctl", "--user", "status", "--no-pager", "--lines=0"}
+	for _, path := range installed {
+		command = append(command, filepath.Base(path))
+	}
+	return [][]string{command}
+}
+
+// systemdCommandLine quotes the arguments that contain spaces, e.g. the path to the binary
+func systemdCommandLine(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.ContainsAny(arg, " \t\"\\") {
+			arg = strconv.Quote(arg)
+		}
+		quoted = append(quoted, arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func render(tmpl *template.Template, data any) (string, error) {
+	buf := bytes.Buffer{}
+	err := tmpl.Execute(&buf, data)
+	return buf.String(), err
+}
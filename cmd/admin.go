@@ -9,8 +9,12 @@ var adminCmd = &cobra.Command{
 	Use:   "admin",
 	Short: "Admin commands",
 	Long: `Admin commands. The list of available subcommands:
+- admin autostart install 	- start the app on login via systemd (Linux) or launchd (MacOS)
+- admin autostart uninstall - stop starting the app on login
+- admin autostart status 	- print whether the app is started on login
 - admin logs print 		- print logs to the terminal output
 - admin logs delete 	- delete logs files
+- admin profiles list 	- print the profiles and whether their servers are running
 - admin server 			- start the HTTP server
 
 Use "remindme admin <subcommand> --help" for more information about a given subcommand.
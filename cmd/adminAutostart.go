@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// adminAutostartCmd represents the admin autostart command
+var adminAutostartCmd = &cobra.Command{
+	Use:   "autostart",
+	Short: "Admin autostart commands: start the app on login",
+	Long: `Admin autostart commands: start the app on login.
+
+The list of available subcommands:
+- admin autostart install 		- start the app on login via systemd (Linux) or launchd (MacOS)
+- admin autostart uninstall 	- stop starting the app on login
+- admin autostart status 		- print whether the app is started on login
+
+Each profile is installed separately: use the "--profile" flag to choose the one.`,
+}
+
+func init() {
+	adminCmd.AddCommand(adminAutostartCmd)
+}
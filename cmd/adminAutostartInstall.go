@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/autostart"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"strings"
+)
+
+type AdminAutostartInstallFlags struct {
+	Address          common.ServerAddress
+	SocketActivation bool
+	FilesOnly        bool
+}
+
+// adminAutostartInstallCmd represents the admin autostart install command
+var adminAutostartInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Start the app on login via systemd (Linux) or launchd (MacOS)",
+	Long: `Start the app on login via systemd (Linux) or launchd (MacOS).
+
+On Linux, the command writes the systemd user service to the "~/.config/systemd/user/" directory, and enables it.
+With the "--socket" flag, the systemd socket is written and enabled instead, so that the app is started on the first command rather than on login.
+On MacOS, the command writes the launchd agent to the "~/Library/LaunchAgents/" directory, and loads it.
+
+The app is started with the current binary, profile and the address resolved the same way as for the "start" command:
+use the "--transport" and "--port" flags to change it.
+The previous installation of the profile, if any, is replaced.
+
+With the "--files-only" flag, the files are written, while the commands to enable them are printed rather than run.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("admin autostart install command: called")
+
+		installFlags, err := parseAdminAutostartInstallCmd(cmd)
+		if err != nil {
+			return err
+		}
+
+		manager, err := autostart.NewManager()
+		if err != nil {
+			logger.Error("admin autostart install command: error while resolving autostart manager", err)
+			return err
+		}
+
+		// the files are rendered first, so that the previous installation is kept if the new one can't be made
+		files, err := manager.Files(autostart.Options{
+			ExecBinary:       resolveExecBinary(),
+			Address:          installFlags.Address,
+			Profile:          utils.Profile(),
+			SocketActivation: installFlags.SocketActivation,
+		})
+		if err != nil {
+			logger.Error("admin autostart install command: error while rendering autostart files", err)
+			if errors.Is(err, common.ErrAutostartSocketActivationUnsupported) {
+				return err
+			}
+			return common.ErrAdminAutostartInstallCmdCannotInstall
+		}
+
+		installed := autostart.InstalledPaths(manager, utils.Profile())
+		if len(installed) > 0 {
+			if !installFlags.FilesOnly {
+				// the previous installation might not be enabled, so the errors are ignored
+				output, err := autostart.RunCommands(manager.DisableCommands(installed))
+				if err != nil {
+					logger.Warn("admin autostart install command: error while disabling previous installation: "+output, err)
+				}
+			}
+			err = autostart.Uninstall(installed)
+			if err != nil {
+				logger.Error("admin autostart install command: error while removing previous installation", err)
+				return common.ErrAdminAutostartUninstallCmdCannotUninstall
+			}
+		}
+
+		err = autostart.Install(files)
+		if err != nil {
+			logger.Error("admin autostart install command: error while writing autostart files", err)
+			return common.ErrAdminAutostartInstallCmdCannotInstall
+		}
+
+		paths := make([]string, 0, len(files))
+		for _, file := range files {
+			fmt.Println("Written " + file.Path)
+			paths = append(paths, file.Path)
+		}
+
+		enableCommands := manager.EnableCommands(paths)
+		if installFlags.FilesOnly {
+			fmt.Println("Run the following commands to enable autostart:")
+			for _, command := range enableCommands {
+				fmt.Println("  " + strings.Join(command, " "))
+			}
+			return nil
+		}
+
+		output, err := autostart.RunCommands(enableCommands)
+		fmt.Print(output)
+		if err != nil {
+			logger.Error("admin autostart install command: error while enabling autostart: "+output, err)
+			fmt.Println(err)
+			return common.ErrAdminAutostartInstallCmdCannotEnable
+		}
+
+		fmt.Println("The remindme app is started on login now")
+		return nil
+	},
+}
+
+func init() {
+	adminAutostartCmd.AddCommand(adminAutostartInstallCmd)
+
+	adminAutostartInstallCmd.Flags().IntP(common.PortFlag, "p", common.DefaultHttpServerPort, "Port to start the HTTP server at - implies TCP transport")
+	adminAutostartInstallCmd.Flags().String(common.TransportFlag, "", "Transport to start the HTTP server with: either unix (Unix domain socket) or tcp")
+	adminAutostartInstallCmd.Flags().Bool(common.SocketFlag, false, "Start the app on the first command rather than on login via systemd socket activation - Linux only")
+	adminAutostartInstallCmd.Flags().Bool(common.FilesOnlyFlag, false, "Write the files, and print the commands to enable them rather than running them")
+}
+
+func parseAdminAutostartInstallCmd(cmd *cobra.Command) (*AdminAutostartInstallFlags, error) {
+	flags := cmd.Flags()
+
+	address, err := resolveServerAddress(cmd)
+	if err != nil {
+		logger.Error("admin autostart install command: error while resolving server address", err)
+		return nil, err
+	}
+
+	socketActivation, err := flags.GetBool(common.SocketFlag)
+	if err != nil {
+		logger.Error("admin autostart install command: error while parsing flag: "+common.SocketFlag, err)
+		return nil, err
+	}
+
+	filesOnly, err := flags.GetBool(common.FilesOnlyFlag)
+	if err != nil {
+		logger.Error("admin autostart install command: error while parsing flag: "+common.FilesOnlyFlag, err)
+		return nil, err
+	}
+
+	return &AdminAutostartInstallFlags{
+		Address:          address,
+		SocketActivation: socketActivation,
+		FilesOnly:        filesOnly,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/autostart"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+)
+
+// adminAutostartStatusCmd represents the admin autostart status command
+var adminAutostartStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print whether the app is started on login",
+	Long: `Print whether the app is started on login.
+
+The command prints the installed files of the profile, and their status reported by systemd (Linux) or launchd (MacOS).`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("admin autostart status command: called")
+
+		manager, err := autostart.NewManager()
+		if err != nil {
+			logger.Error("admin autostart status command: error while resolving autostart manager", err)
+			return err
+		}
+
+		installed := autostart.InstalledPaths(manager, utils.Profile())
+		if len(installed) == 0 {
+			fmt.Println("Autostart is not installed")
+			return nil
+		}
+
+		fmt.Println("Installed:")
+		for _, path := range installed {
+			fmt.Println("  " + path)
+		}
+
+		// the status commands exit with non-zero code for the inactive units, which is not an error here
+		output, err := autostart.RunCommands(manager.StatusCommands(installed))
+		fmt.Print(output)
+		if err != nil {
+			logger.Warn("admin autostart status command: status command failed", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	adminAutostartCmd.AddCommand(adminAutostartStatusCmd)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/autostart"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+)
+
+// adminAutostartUninstallCmd represents the admin autostart uninstall command
+var adminAutostartUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop starting the app on login",
+	Long: `Stop starting the app on login.
+
+The command disables the systemd units (Linux) or unloads the launchd agent (MacOS) of the profile, and removes their files.
+The running app is stopped by systemd/launchd as well.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("admin autostart uninstall command: called")
+
+		manager, err := autostart.NewManager()
+		if err != nil {
+			logger.Error("admin autostart uninstall command: error while resolving autostart manager", err)
+			return err
+		}
+
+		installed := autostart.InstalledPaths(manager, utils.Profile())
+		if len(installed) == 0 {
+			fmt.Println("Autostart is not installed")
+			return nil
+		}
+
+		output, err := autostart.RunCommands(manager.DisableCommands(installed))
+		fmt.Print(output)
+		if err != nil {
+			// the files are removed anyway, as otherwise there is no way to get rid of the broken installation
+			logger.Warn("admin autostart uninstall command: error while disabling autostart: "+output, err)
+		}
+
+		err = autostart.Uninstall(installed)
+		if err != nil {
+			logger.Error("admin autostart uninstall command: error while removing autostart files", err)
+			return common.ErrAdminAutostartUninstallCmdCannotUninstall
+		}
+
+		for _, path := range installed {
+			fmt.Println("Removed " + path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	adminAutostartCmd.AddCommand(adminAutostartUninstallCmd)
+}
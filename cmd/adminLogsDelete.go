@@ -58,22 +58,28 @@ func init() {
 	adminLogsCmd.AddCommand(adminLogsDeleteCmd)
 }
 
+// deleteLogs deletes the logs file alongside the rotated ones
 func deleteLogs(logsFileName string) error {
-	logsFilePath := utils.GetOsSpecificAppDataDir() + logsFileName
+	logsDir := utils.GetOsSpecificAppDataDir()
 
-	logsFile, err := os.Open(logsFilePath)
+	segments, err := logger.ListSegments(logsDir, logsFileName)
 	if err != nil {
-		logger.Error("logs command: failed to open logs file", err)
+		logger.Error("logs command: failed to list logs files", err)
+		return common.ErrAdminLogsCmdCannotOpenLogsFile
+	}
+	if len(segments) == 0 {
+		logger.Error("logs command: no logs files found: " + logsDir + logsFileName)
 		return common.ErrAdminLogsCmdCannotOpenLogsFile
 	}
-	logsFile.Close()
 
-	logger.Info("admin logs delete command: deleting logs file: " + logsFilePath)
+	for _, segment := range segments {
+		logger.Info("admin logs delete command: deleting logs file: " + segment)
 
-	err = os.Remove(logsFilePath)
-	if err != nil {
-		logger.Error("logs command: failed to delete logs file", err)
-		return common.ErrAdminLogsCmdCannotDeleteLogsFile
+		err = os.Remove(segment)
+		if err != nil {
+			logger.Error("logs command: failed to delete logs file", err)
+			return common.ErrAdminLogsCmdCannotDeleteLogsFile
+		}
 	}
 	return nil
 }
@@ -6,15 +6,40 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"io"
+	"log/slog"
 	"n0rdy.foo/remindme/common"
 	"n0rdy.foo/remindme/logger"
 	"n0rdy.foo/remindme/utils"
 	"os"
+	"regexp"
+	"sort"
+	"time"
+)
+
+const (
+	// the same as `tail -f` prints by default
+	defaultFollowLines   = 10
+	followPollInterval   = 500 * time.Millisecond
+	maxLogsLineSizeBytes = 1024 * 1024
 )
 
 type AdminLogsPrintFlags struct {
 	IsClient bool
 	IsServer bool
+	Follow   bool
+	Since    time.Time
+	Until    time.Time
+	Level    *slog.Level
+	Grep     *regexp.Regexp
+	Lines    int
+	Request  string
+}
+
+// logsLine is a line of either client or server logs, to be merged with the other side's ones
+type logsLine struct {
+	source string
+	time   time.Time
+	line   string
 }
 
 // adminLogsPrintCmd represents the print command
@@ -27,6 +52,22 @@ Accepts either --server or --client flag to specify which logs to print.
 If no flag is provided, prints client logs by default.
 If both flags are provided, the error message is printed.
 
+The logs can be filtered with:
+- --since and --until flags: either as a duration ago (e.g. "30m", "2h") or an absolute time (e.g. "2023-10-19 15:04:05", "2023-10-19" or "15:04" for today).
+- --level flag: prints the records of the provided level or higher (debug, info, warn or error)
+- --grep flag: prints the records that match the provided regular expression
+- --lines flag: prints only the last N records that match the filters
+
+If any of these flags is provided, the rotated logs files (including the compressed ones) are read as well, if they might contain the requested records.
+Otherwise, the current logs file is printed, or the latest rotated one if no records have been written since the rotation.
+
+The --request flag prints both client and server records of the provided request ID merged by time,
+unless either --client or --server flag is provided explicitly. All the logs files, including the rotated ones, are searched.
+The request ID is printed by the commands that failed after sending a request to the remindme app.
+
+The --follow flag keeps printing the new records as they are written, like "tail -f" does, even if the logs file gets rotated.
+If --lines is not provided alongside --follow, the last 10 records are printed first.
+
 If the remindme app didn't manage to find the logs file, nothing is printed.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Info("admin logs print command: called")
@@ -36,6 +77,10 @@ If the remindme app didn't manage to find the logs file, nothing is printed.`,
 			return err
 		}
 
+		if flags.Request != "" {
+			return printRequestLogs(*flags)
+		}
+
 		var logsFileName string
 		if flags.IsServer {
 			logsFileName = common.ServerLogsFileName
@@ -43,56 +88,418 @@ If the remindme app didn't manage to find the logs file, nothing is printed.`,
 			logsFileName = common.ClientLogsFileName
 		}
 
-		return printLogs(logsFileName)
+		return printLogs(logsFileName, *flags)
 	},
 }
 
 func init() {
 	adminLogsCmd.AddCommand(adminLogsPrintCmd)
+
+	adminLogsPrintCmd.Flags().BoolP(common.FollowFlag, "f", false, "Keep printing the new records as they are written")
+	adminLogsPrintCmd.Flags().String(common.SinceFlag, "", "Print the records written since the provided time: e.g. 30m, 2h, 15:04, 2023-10-19 or \"2023-10-19 15:04:05\"")
+	adminLogsPrintCmd.Flags().String(common.UntilFlag, "", "Print the records written until the provided time: e.g. 30m, 2h, 15:04, 2023-10-19 or \"2023-10-19 15:04:05\"")
+	adminLogsPrintCmd.Flags().String(common.LevelFlag, "", "Print the records of the provided level or higher: debug, info, warn or error")
+	adminLogsPrintCmd.Flags().String(common.GrepFlag, "", "Print the records that match the provided regular expression")
+	adminLogsPrintCmd.Flags().IntP(common.LinesFlag, "n", 0, "Print only the last N records that match the filters")
+	adminLogsPrintCmd.Flags().String(common.RequestFlag, "", "Print both client and server records of the provided request ID")
 }
 
-func printLogs(logsFileName string) error {
+func printLogs(logsFileName string, flags AdminLogsPrintFlags) error {
 	logsDir := utils.GetOsSpecificAppDataDir()
+	logsFilePath := logsDir + logsFileName
+
+	segments, err := resolveLogsSegments(logsDir, logsFileName, flags)
+	if err != nil {
+		fmt.Println("logs command: failed to list logs files", err)
+		return common.ErrAdminLogsCmdCannotOpenLogsFile
+	}
+	if len(segments) == 0 && !flags.Follow {
+		return nil
+	}
+
+	fmt.Println("Logs location: " + logsFilePath)
+
+	lines := flags.Lines
+	if lines == 0 && flags.Follow {
+		lines = defaultFollowLines
+	}
+	// keeps only the last N matched lines if requested
+	tail := make([]string, 0, lines)
+	output := func(line string) {
+		if lines == 0 {
+			fmt.Println(line)
+			return
+		}
+		if len(tail) == lines {
+			tail = tail[1:]
+		}
+		tail = append(tail, line)
+	}
+
+	// the current file is kept open to be followed from where its initial read has stopped, so that no records are missed in between
+	var current *os.File
+	if flags.Follow {
+		current, err = os.Open(logsFilePath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Println("logs command: failed to open logs file", err)
+			return common.ErrAdminLogsCmdCannotOpenLogsFile
+		}
+	}
+
+	for _, segment := range segments {
+		if current != nil && segment == logsFilePath {
+			continue
+		}
+		err = readLogsSegment(segment, flags, output)
+		if err != nil {
+			if current != nil {
+				current.Close()
+			}
+			return err
+		}
+	}
+
+	var reader *bufio.Reader
+	partialLine := ""
+	if current != nil {
+		reader = bufio.NewReader(current)
+		partialLine, err = printNewLines(reader, partialLine, flags, output)
+		if err != nil {
+			current.Close()
+			logger.Error("logs command: failed to read logs file", err)
+			return err
+		}
+	}
+	for _, line := range tail {
+		fmt.Println(line)
+	}
 
-	logsFile, err := os.Open(logsDir + logsFileName)
+	if flags.Follow {
+		return followLogs(logsFilePath, current, reader, partialLine, flags)
+	}
+	return nil
+}
+
+// resolveLogsSegments resolves the logs files to read: all of them (including the rotated ones) if the records are filtered
+// or counted, as the matching ones might have been rotated already, skipping the files modified before the requested time if any.
+// Otherwise, the current file only, or the latest rotated one if the current one hasn't been created since the rotation.
+func resolveLogsSegments(logsDir string, logsFileName string, flags AdminLogsPrintFlags) ([]string, error) {
+	segments, err := logger.ListSegments(logsDir, logsFileName)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return segments, nil
+	}
+
+	if !flags.isFiltered() {
+		return segments[len(segments)-1:], nil
+	}
+	if flags.Since.IsZero() {
+		return segments, nil
+	}
+
+	resolved := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		info, err := os.Stat(segment)
+		if err != nil {
+			return nil, err
+		}
+		// the last record of the file can't be written after its modification time
+		if info.ModTime().Before(flags.Since) {
+			continue
+		}
+		resolved = append(resolved, segment)
+	}
+	return resolved, nil
+}
+
+// isFiltered tells whether the records to print might be found beyond the current logs file,
+// the --follow flag counts as well, as it prints the last records first
+func (flags AdminLogsPrintFlags) isFiltered() bool {
+	return !flags.Since.IsZero() || !flags.Until.IsZero() || flags.Level != nil || flags.Grep != nil ||
+		flags.Lines > 0 || flags.Follow || flags.Request != ""
+}
+
+func readLogsSegment(segment string, flags AdminLogsPrintFlags, output func(line string)) error {
+	reader, err := logger.OpenSegment(segment)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
+			// might have been rotated in the meantime
 			return nil
 		}
 		fmt.Println("logs command: failed to open logs file", err)
 		return common.ErrAdminLogsCmdCannotOpenLogsFile
 	}
-	defer logsFile.Close()
+	defer reader.Close()
 
-	fmt.Println("Logs location: " + logsDir + logsFileName)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogsLineSizeBytes)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if flags.matches(line) {
+			output(line)
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		logger.Error("logs command: failed to read logs file", err)
+		return err
+	}
+	return nil
+}
+
+// printRequestLogs prints the records of the request from both client and server logs (unless one of them is requested explicitly),
+// merged by time and prefixed with the side they've been written by
+func printRequestLogs(flags AdminLogsPrintFlags) error {
+	logsDir := utils.GetOsSpecificAppDataDir()
+
+	sources := map[string]string{
+		common.ClientFlag: common.ClientLogsFileName,
+		common.ServerFlag: common.ServerLogsFileName,
+	}
+	if flags.IsClient {
+		delete(sources, common.ServerFlag)
+	}
+	if flags.IsServer {
+		delete(sources, common.ClientFlag)
+	}
+
+	matched := make([]logsLine, 0)
+	for source, logsFileName := range sources {
+		segments, err := resolveLogsSegments(logsDir, logsFileName, flags)
+		if err != nil {
+			fmt.Println("logs command: failed to list logs files", err)
+			return common.ErrAdminLogsCmdCannotOpenLogsFile
+		}
+
+		for _, segment := range segments {
+			err = readLogsSegment(segment, flags, func(line string) {
+				// the line has been parsed successfully already to match the request ID
+				record, _ := logger.ParseRecord(line)
+				matched = append(matched, logsLine{source: source, time: record.Time, line: line})
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
 
-	fileReader := bufio.NewReader(logsFile)
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].time.Equal(matched[j].time) {
+			// the client sends the request before the server receives it
+			return matched[i].source == common.ClientFlag && matched[j].source == common.ServerFlag
+		}
+		return matched[i].time.Before(matched[j].time)
+	})
+	if flags.Lines > 0 && len(matched) > flags.Lines {
+		matched = matched[len(matched)-flags.Lines:]
+	}
+
+	for _, l := range matched {
+		fmt.Printf("[%s] %s\n", l.source, l.line)
+	}
+	return nil
+}
+
+// followLogs prints the new records of the logs file until the command is interrupted.
+// If the file gets rotated, the rest of the old file is printed, and the new file is followed from its beginning.
+// followLogs continues reading the file from where the reader has stopped, or waits for the file to be created if it's nil
+func followLogs(logsFilePath string, file *os.File, reader *bufio.Reader, partialLine string, flags AdminLogsPrintFlags) error {
+	printLine := func(line string) {
+		fmt.Println(line)
+	}
+
+	var err error
 	for {
-		line, _, err := fileReader.ReadLine()
+		if file != nil {
+			partialLine, err = printNewLines(reader, partialLine, flags, printLine)
+			if err != nil {
+				file.Close()
+				logger.Error("logs command: failed to read logs file", err)
+				return err
+			}
+		}
+
+		time.Sleep(followPollInterval)
+
+		currentInfo, err := os.Stat(logsFilePath)
+		if err != nil {
+			// the file has been rotated, but the new one hasn't been created yet
+			continue
+		}
+
+		if file != nil {
+			openedInfo, err := file.Stat()
+			if err == nil && os.SameFile(openedInfo, currentInfo) {
+				if currentInfo.Size() < openedInfo.Size() {
+					// truncated
+					_, _ = file.Seek(0, io.SeekStart)
+					reader.Reset(file)
+				}
+				continue
+			}
+
+			// rotated: the rest of the old file should be printed before switching to the new one
+			partialLine, err = printNewLines(reader, partialLine, flags, printLine)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		file, err = os.Open(logsFilePath)
+		if err != nil {
+			file = nil
+			continue
+		}
+		reader = bufio.NewReader(file)
+	}
+}
+
+// printNewLines outputs the complete matching lines available to read, and returns the incomplete last one to be continued
+func printNewLines(reader *bufio.Reader, partialLine string, flags AdminLogsPrintFlags, output func(line string)) (string, error) {
+	for {
+		chunk, err := reader.ReadString('\n')
+		partialLine += chunk
 		if err != nil {
 			if err == io.EOF {
-				break
+				return partialLine, nil
 			}
-			logger.Error("logs command: failed to read logs file", err)
-			return err
+			return partialLine, err
 		}
 
-		fmt.Println(string(line))
+		line := partialLine[:len(partialLine)-1]
+		partialLine = ""
+		if flags.matches(line) {
+			output(line)
+		}
 	}
-	return nil
+}
+
+func (flags AdminLogsPrintFlags) matches(line string) bool {
+	if flags.Grep != nil && !flags.Grep.MatchString(line) {
+		return false
+	}
+	if flags.Since.IsZero() && flags.Until.IsZero() && flags.Level == nil && flags.Request == "" {
+		return true
+	}
+
+	record, parsed := logger.ParseRecord(line)
+	if !parsed {
+		return false
+	}
+	if !flags.Since.IsZero() && record.Time.Before(flags.Since) {
+		return false
+	}
+	if !flags.Until.IsZero() && record.Time.After(flags.Until) {
+		return false
+	}
+	if flags.Level != nil && record.Level < *flags.Level {
+		return false
+	}
+	if flags.Request != "" && record.Attrs[logger.RequestIdAttr] != flags.Request {
+		return false
+	}
+	return true
 }
 
 func parseAdminLogsPrintCmd(cmd *cobra.Command) (*AdminLogsPrintFlags, error) {
-	isClient := cmd.Flags().Lookup(common.ClientFlag).Changed
-	isServer := cmd.Flags().Lookup(common.ServerFlag).Changed
+	flags := cmd.Flags()
+
+	isClient := flags.Lookup(common.ClientFlag).Changed
+	isServer := flags.Lookup(common.ServerFlag).Changed
 
 	if isClient && isServer {
 		logger.Error("logs command: both flags provided, only one is expected")
 		return nil, common.ErrAdminLogsCmdBothFlagsProvided
 	}
 
-	return &AdminLogsPrintFlags{
+	printFlags := AdminLogsPrintFlags{
 		IsClient: isClient,
 		IsServer: isServer,
-	}, nil
+		Follow:   flags.Lookup(common.FollowFlag).Changed,
+	}
+	now := time.Now()
+
+	since, err := flags.GetString(common.SinceFlag)
+	if err != nil {
+		logger.Error("logs command: error while parsing flag: "+common.SinceFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.SinceFlag)
+	}
+	if since != "" {
+		printFlags.Since, err = utils.ParsePointInTime(since, now)
+		if err != nil {
+			logger.Error("logs command: invalid time provided for flag: "+common.SinceFlag, err)
+			return nil, err
+		}
+	}
+
+	until, err := flags.GetString(common.UntilFlag)
+	if err != nil {
+		logger.Error("logs command: error while parsing flag: "+common.UntilFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.UntilFlag)
+	}
+	if until != "" {
+		if printFlags.Follow {
+			logger.Error("logs command: both --follow and --until flags provided")
+			return nil, common.ErrAdminLogsPrintCmdFollowWithUntil
+		}
+		printFlags.Until, err = utils.ParsePointInTime(until, now)
+		if err != nil {
+			logger.Error("logs command: invalid time provided for flag: "+common.UntilFlag, err)
+			return nil, err
+		}
+	}
+
+	level, err := flags.GetString(common.LevelFlag)
+	if err != nil {
+		logger.Error("logs command: error while parsing flag: "+common.LevelFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.LevelFlag)
+	}
+	if level != "" {
+		parsedLevel, err := logger.ParseLevel(level)
+		if err != nil {
+			logger.Error("logs command: invalid level provided: "+level, err)
+			return nil, common.ErrAdminLogsPrintCmdInvalidLevel
+		}
+		printFlags.Level = &parsedLevel
+	}
+
+	grep, err := flags.GetString(common.GrepFlag)
+	if err != nil {
+		logger.Error("logs command: error while parsing flag: "+common.GrepFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.GrepFlag)
+	}
+	if grep != "" {
+		printFlags.Grep, err = regexp.Compile(grep)
+		if err != nil {
+			logger.Error("logs command: invalid grep pattern provided: "+grep, err)
+			return nil, common.ErrAdminLogsPrintCmdInvalidGrepPattern
+		}
+	}
+
+	lines, err := flags.GetInt(common.LinesFlag)
+	if err != nil {
+		logger.Error("logs command: error while parsing flag: "+common.LinesFlag, err)
+		return nil, common.ErrWrongFormattedIntFlag(common.LinesFlag)
+	}
+	if lines < 0 {
+		logger.Error("logs command: negative number of lines provided")
+		return nil, common.ErrAdminLogsPrintCmdInvalidLines
+	}
+	printFlags.Lines = lines
+
+	printFlags.Request, err = flags.GetString(common.RequestFlag)
+	if err != nil {
+		logger.Error("logs command: error while parsing flag: "+common.RequestFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.RequestFlag)
+	}
+	if printFlags.Request != "" && printFlags.Follow {
+		logger.Error("logs command: both --follow and --request flags provided")
+		return nil, common.ErrAdminLogsPrintCmdFollowWithRequest
+	}
+
+	return &printFlags, nil
 }
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// adminProfilesCmd represents the admin profiles command
+var adminProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "Admin profiles commands: list profiles",
+	Long: `Admin profiles commands: list profiles.
+
+The list of available subcommands:
+- admin profiles list 	- print the profiles and whether their servers are running
+
+Each profile has its own data directory with the reminders DB, configs, logs and the server address.
+The profile is requested with the "--profile" flag or the REMINDME_PROFILE env var, and is created on the first use.`,
+}
+
+func init() {
+	adminCmd.AddCommand(adminProfilesCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"text/tabwriter"
+)
+
+const (
+	profilesTitle    = "Profile\tStatus\tAddress\tData dir"
+	profilesTemplate = "%s\t%s\t%s\t%s\n"
+)
+
+// adminProfilesListCmd represents the admin profiles list command
+var adminProfilesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the profiles and whether their servers are running",
+	Long: `Print the profiles and whether their servers are running.
+
+The current profile is marked with "*".`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("admin profiles list command: called")
+
+		profiles, err := utils.ListProfiles()
+		if err != nil {
+			logger.Error("admin profiles list command: error while listing profiles", err)
+			return common.ErrAdminProfilesListCmdCannotListProfiles
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 1, 1, 3, ' ', 0)
+		fmt.Fprintln(w, profilesTitle)
+		// the default profile is listed first, and is represented as an empty string
+		for _, profile := range append([]string{""}, profiles...) {
+			printProfile(w, profile)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	adminProfilesCmd.AddCommand(adminProfilesListCmd)
+}
+
+func printProfile(w *tabwriter.Writer, profile string) {
+	name := profile
+	if name == "" {
+		name = common.DefaultProfile
+	}
+	if profile == utils.Profile() {
+		name += " *"
+	}
+
+	address, err := config.ResolveProfileServerAddress(profile)
+	if err != nil {
+		logger.Error("admin profiles list command: error while resolving server address of the profile "+name, err)
+		fmt.Fprintf(w, profilesTemplate, name, "unknown", "-", utils.GetProfileAppDataDir(profile))
+		return
+	}
+
+	status := "stopped"
+	httpClient := httpclient.NewHttpClient(address)
+	if httpClient.Healthcheck() {
+		status = "running"
+	}
+	fmt.Fprintf(w, profilesTemplate, name, status, address.String(), utils.GetProfileAppDataDir(profile))
+}
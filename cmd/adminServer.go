@@ -8,19 +8,22 @@ import (
 // adminServerCmd represents the adminStartServer command
 var adminServerCmd = &cobra.Command{
 	Use:   "server",
-	Short: "Admin server commands: start and stop server",
-	Long: `Admin server commands: start and stop server. 
+	Short: "Admin server commands: start, stop and reload server",
+	Long: `Admin server commands: start, stop and reload server. 
 WARNING: these commands are for the admin use only: please, don't run them on your own, they might crash the app.
 
 The list of available subcommands:
 - admin server start 	- to be run by the app to start the server
 - admin server stop 	- to be run by the admin to stop the server
+- admin server reload 	- to be run by the admin to reload the user configs of the server
 
-Accepts the --port flag to specify which port to start/stop the server at.`,
+Accepts the --port flag to specify which port to start/stop/reload the server at,
+and the --transport flag to specify whether to use the Unix domain socket or TCP port.`,
 }
 
 func init() {
 	adminCmd.AddCommand(adminServerCmd)
 
-	adminServerCmd.PersistentFlags().IntP("port", "p", common.DefaultHttpServerPort, "Port to start the HTTP server at")
+	adminServerCmd.PersistentFlags().IntP(common.PortFlag, "p", common.DefaultHttpServerPort, "Port to start the HTTP server at - implies TCP transport")
+	adminServerCmd.PersistentFlags().String(common.TransportFlag, "", "Transport of the HTTP server: either unix (Unix domain socket, default on Linux and MacOS) or tcp (default on Windows)")
 }
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+)
+
+// adminServerReloadCmd represents the admin server reload command
+var adminServerReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload the user configs of the running server",
+	Long: `Reload the user configs of the running server: e.g. once the configs file has been edited manually.
+The same happens if the server process receives the SIGHUP signal.
+
+If the configs are invalid, the server keeps the current ones, and the error is printed: run "config validate" for details.
+The "repo.backend" config is applied on the app restart only.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("admin server reload command: called")
+
+		address, err := resolveServerAddress(cmd)
+		if err != nil {
+			logger.Error("admin server reload command: error while resolving server address", err)
+			return err
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		err = httpClient.ReloadConfigs()
+		if err != nil {
+			logger.Error("admin server reload command: error while reloading configs", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	adminServerCmd.AddCommand(adminServerReloadCmd)
+}
@@ -1,13 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"github.com/spf13/cobra"
 	"n0rdy.foo/remindme/common"
 	"n0rdy.foo/remindme/config"
 	"n0rdy.foo/remindme/httpserver"
 	"n0rdy.foo/remindme/logger"
 	"n0rdy.foo/remindme/utils"
-	"strconv"
 )
 
 // adminServerStartCmd represents the adminStartServer command
@@ -20,15 +20,31 @@ Use "start" command instead if you need to start the remindme app.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Info("admin server start command: called")
 
-		port, err := resolveAdminServerStartPort(cmd)
+		// the lock is acquired first, so that the address and API token of the running server are not overwritten
+		pidFile, err := config.AcquirePidFile()
 		if err != nil {
+			logger.Error("admin server start command: error while acquiring PID file", err)
+			if errors.Is(err, common.ErrPidFileLocked) {
+				return common.ErrStartCmdAlreadyRunning
+			}
 			return err
 		}
+		defer pidFile.Release()
 
-		logger.Info("admin server start command: starting HTTP server at port " + strconv.Itoa(port))
+		address, err := resolveAdminServerStartAddress(cmd)
+		if err != nil {
+			return err
+		}
+
+		apiToken, err := config.GenerateApiToken()
+		if err != nil {
+			logger.Error("admin server start command: error while generating API token", err)
+			return common.ErrAdminServerStartCmdCannotGenerateApiToken
+		}
+
+		logger.Info("admin server start command: starting HTTP server at " + address.String())
 
-		httpserver.Start(port)
-		return nil
+		return httpserver.Start(address, apiToken)
 	},
 }
 
@@ -36,29 +52,30 @@ func init() {
 	adminServerCmd.AddCommand(adminServerStartCmd)
 }
 
-func resolveAdminServerStartPort(cmd *cobra.Command) (int, error) {
-	port, err := cmd.Flags().GetInt(common.PortFlag)
+func resolveAdminServerStartAddress(cmd *cobra.Command) (common.ServerAddress, error) {
+	address, err := resolveServerAddress(cmd)
 	if err != nil {
-		logger.Error("admin server start command: error while parsing flag: "+common.PortFlag, err)
-		return 0, common.ErrWrongFormattedIntFlag(common.PortFlag)
+		logger.Error("admin server start command: error while resolving server address", err)
+		return address, err
 	}
 
-	if !utils.IsPortValid(port) {
-		logger.Error("admin server start command: invalid port provided: " + strconv.Itoa(port))
-		return 0, common.ErrCmdInvalidPort
-	}
-
-	err = persistResolvedServerPort(port)
+	err = persistResolvedServerAddress(address)
 	if err != nil {
-		return 0, err
+		return address, err
 	}
 
-	return port, nil
+	return address, nil
 }
 
-func persistResolvedServerPort(resolvedPort int) error {
-	if resolvedPort != common.DefaultHttpServerPort {
-		err := config.PersistAdminConfigs(common.AdminConfigs{ServerPort: resolvedPort})
+func persistResolvedServerAddress(resolvedAddress common.ServerAddress) error {
+	isDefaultTransport := resolvedAddress.Transport == utils.DefaultTransport()
+	isDefaultPort := resolvedAddress.Transport == common.UnixTransport || resolvedAddress.Port == utils.DefaultProfilePort(utils.Profile())
+
+	if !isDefaultTransport || !isDefaultPort {
+		err := config.PersistAdminConfigs(common.AdminConfigs{
+			ServerPort:      resolvedAddress.Port,
+			ServerTransport: resolvedAddress.Transport,
+		})
 		if err != nil {
 			logger.Error("admin server start command: error while persisting admin configs into file", err)
 			return common.ErrAdminServerStartCmdCannotPersistConfigs
@@ -5,8 +5,6 @@ import (
 	"n0rdy.foo/remindme/common"
 	"n0rdy.foo/remindme/httpclient"
 	"n0rdy.foo/remindme/logger"
-	"n0rdy.foo/remindme/utils"
-	"strconv"
 )
 
 // adminServerStopCmd represents the adminStartServer command
@@ -16,18 +14,20 @@ var adminServerStopCmd = &cobra.Command{
 	Long: `WARNING: To be run by the admin to start the server: please, use "remindme stop" instead unless you know what you're doing.
 
 The usecase for this command is if there are multiple remindme instances running on the same machine and you want to stop the old one.
-Otherwise, you'll have to find the admin configs file, change the port there, run "remindme stop" command and then change the port back tp the previous value.`,
+Otherwise, you'll have to find the admin configs file, change the port there, run "remindme stop" command and then change the port back tp the previous value.
+If the instances are started with different profiles, run "remindme stop --profile <name>" instead.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Info("admin server stop command: called")
 
-		port, err := resolveAdminServerStopPort(cmd)
+		address, err := resolveServerAddress(cmd)
 		if err != nil {
+			logger.Error("admin server stop command: error while resolving server address", err)
 			return err
 		}
 
-		logger.Info("admin server stop command: stopping HTTP server at port " + strconv.Itoa(port))
+		logger.Info("admin server stop command: stopping HTTP server at " + address.String())
 
-		httpClient := httpclient.NewHttpClient(port)
+		httpClient := httpclient.NewHttpClient(address)
 		err = httpClient.StopServer()
 		if err != nil {
 			logger.Error("admin server stop command: error while stopping HTTP server", err)
@@ -40,17 +40,3 @@ Otherwise, you'll have to find the admin configs file, change the port there, ru
 func init() {
 	adminServerCmd.AddCommand(adminServerStopCmd)
 }
-
-func resolveAdminServerStopPort(cmd *cobra.Command) (int, error) {
-	port, err := cmd.Flags().GetInt(common.PortFlag)
-	if err != nil {
-		logger.Error("admin server stop command: error while parsing flag: "+common.PortFlag, err)
-		return 0, common.ErrWrongFormattedIntFlag(common.PortFlag)
-	}
-
-	if !utils.IsPortValid(port) {
-		logger.Error("admin server stop command: invalid port provided: " + strconv.Itoa(port))
-		return 0, common.ErrCmdInvalidPort
-	}
-	return port, nil
-}
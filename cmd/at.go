@@ -20,6 +20,8 @@ var atCmd = &cobra.Command{
 The command accepts the exact time the notification should be sent at in either 24 hours "hh:mm" format (e.g. 13:05 or 09:45) via --time flag, or 12 hours "hh:mm" A.M./P.M. format via --am/--pm flag.
 The provided time should be in future - otherwise, the error will be produced.
 The command expects a reminder message to be provided via the "--about" flag - otherwise, the error will be produced.
+The "--urgency" flag defines how the reminder is notified if it comes due within the quiet hours or the do-not-disturb mode:
+it's dropped if low, deferred till their end if normal (default), or notified silently if high.
 
 List the upcoming reminders with the "list" command.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -30,13 +32,13 @@ List the upcoming reminders with the "list" command.`,
 			return err
 		}
 
-		port, err := config.ResolveRunningServerPort()
+		address, err := config.ResolveRunningServerAddress()
 		if err != nil {
-			logger.Error("at command: error while resolving running server port", err)
-			return common.ErrCmdCannotResolveServerPort
+			logger.Error("at command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
 		}
 
-		httpClient := httpclient.NewHttpClient(port)
+		httpClient := httpclient.NewHttpClient(address)
 		return httpClient.CreateReminder(*reminder)
 	},
 }
@@ -49,6 +51,12 @@ func init() {
 	atCmd.Flags().String(common.AmFlag, "", "A.M. time to remind at for `at` command in 12-hours HH:MM format: e.g. 07:45")
 	atCmd.Flags().String(common.PmFlag, "", "P.M. time to remind at for `at` command in 12-hours HH:MM format: e.g. 07:45")
 
+	atCmd.Flags().String(common.UrgencyFlag, common.UrgencyNormal, "Reminder urgency, which defines how it's notified within the quiet hours: low (dropped), normal (deferred till their end) or high (notified silently)")
+
+	atCmd.RegisterFlagCompletionFunc(common.TimeFlag, completeTimes)
+	atCmd.RegisterFlagCompletionFunc(common.AmFlag, completeAmTimes)
+	atCmd.RegisterFlagCompletionFunc(common.PmFlag, completePmTimes)
+
 	atCmd.MarkFlagRequired(common.AboutFlag)
 }
 
@@ -70,9 +78,15 @@ func parseAtCmd(cmd *cobra.Command) (*common.Reminder, error) {
 		return nil, err
 	}
 
+	urgency, err := parseUrgencyFlag(flags, "at")
+	if err != nil {
+		return nil, err
+	}
+
 	return &common.Reminder{
 		Message:  message,
 		RemindAt: remindAt,
+		Urgency:  urgency,
 	}, nil
 }
 
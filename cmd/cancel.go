@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/spf13/cobra"
 	"n0rdy.foo/remindme/common"
 	"n0rdy.foo/remindme/config"
@@ -9,19 +10,29 @@ import (
 )
 
 type CancelFlags struct {
-	Id    int
-	IsAll bool
+	Selector ReminderSelector
+	IsAll    bool
 }
 
 // cancelCmd represents the cancel command
 var cancelCmd = &cobra.Command{
 	Use:   "cancel",
-	Short: "Cancel reminder",
-	Long: `Cancel reminder.
+	Short: "Cancel reminders",
+	Long: `Cancel reminders.
 
-The command expects a reminder ID to be provided via the "--id" flag - otherwise, the error will be produced.
+The command expects either the reminders to be selected with the "--id", "--matching", "--before" and/or "--after" flags,
+or the "--all" flag to be provided - otherwise, the error will be produced.
+The "--id" flag accepts a single ID, or the comma-separated list of IDs and ID ranges: e.g. 3, 1,2,5 or 5-8.
+If several criteria are provided, the reminders matching all of them are cancelled.
+
+Unless a single reminder is selected by its ID, the selected reminders are previewed, and the confirmation is requested.
+The "--yes" flag skips the confirmation: e.g. for the scripts.
 
 List the upcoming reminders with the "list" command.`,
+	Example: `remindme cancel --id 3
+remindme cancel --id 1,2,5-8
+remindme cancel --matching standup --before 18:00
+remindme cancel --after 2h --yes`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Info("cancel command: called")
 
@@ -30,25 +41,27 @@ List the upcoming reminders with the "list" command.`,
 			return err
 		}
 
-		port, err := config.ResolveRunningServerPort()
+		address, err := config.ResolveRunningServerAddress()
 		if err != nil {
-			logger.Error("at command: error while resolving running server port", err)
-			return common.ErrCmdCannotResolveServerPort
+			logger.Error("cancel command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
 		}
 
-		httpClient := httpclient.NewHttpClient(port)
-		if cancelFlags.Id != 0 {
-			return httpClient.DeleteReminder(cancelFlags.Id)
-		} else {
+		httpClient := httpclient.NewHttpClient(address)
+		if cancelFlags.IsAll {
 			return httpClient.DeleteAllReminders()
 		}
+		if id, isSingle := cancelFlags.Selector.SingleId(); isSingle {
+			return httpClient.DeleteReminder(id)
+		}
+		return cancelSelectedReminders(httpClient, cancelFlags.Selector)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(cancelCmd)
 
-	cancelCmd.Flags().Int(common.IdFlag, 0, "Reminder ID to cancel")
+	addReminderSelectorFlags(cancelCmd, "Reminder IDs to cancel")
 	cancelCmd.Flags().Bool(common.AllFlag, false, "If this flag is provided, all the upcoming reminders will be canceled")
 }
 
@@ -56,20 +69,52 @@ func parseCancelCmd(cmd *cobra.Command) (*CancelFlags, error) {
 	flags := cmd.Flags()
 
 	isAll := flags.Lookup(common.AllFlag).Changed
-	id, err := flags.GetInt(common.IdFlag)
+	selector, err := parseReminderSelector(cmd)
 	if err != nil {
-		logger.Error("cancel command: error while parsing flag: "+common.IdFlag, err)
-		return nil, common.ErrWrongFormattedIntFlag(common.IdFlag)
+		return nil, err
 	}
 
 	// catches "no flags provided" and "all flags provided" cases
-	if (id == 0 && !isAll) || (id != 0 && isAll) {
+	if (selector.IsEmpty() && !isAll) || (!selector.IsEmpty() && isAll) {
 		logger.Error("cancel command: invalid flags provided")
 		return nil, common.ErrCancelCmdInvalidFlagsProvided
 	}
 
 	return &CancelFlags{
-		Id:    id,
-		IsAll: isAll,
+		Selector: *selector,
+		IsAll:    isAll,
 	}, nil
 }
+
+// cancelSelectedReminders cancels the selected reminders at once: if any of them can't be cancelled, none is
+func cancelSelectedReminders(httpClient httpclient.RemindmeHttpClient, selector ReminderSelector) error {
+	reminders, err := httpClient.GetAllReminders()
+	if err != nil {
+		return err
+	}
+	selected, err := selector.Select(reminders)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		fmt.Println("No reminders match the provided criteria")
+		return nil
+	}
+
+	confirmed, err := selector.confirmBulkOperation(fmt.Sprintf("Cancel %d reminder(s)?", len(selected)), selected)
+	if err != nil || !confirmed {
+		return err
+	}
+
+	operations := make([]common.BatchOperation, 0, len(selected))
+	for _, reminder := range selected {
+		operations = append(operations, common.BatchOperation{Type: common.BatchOperationDelete, ID: reminder.ID})
+	}
+	err = httpClient.ApplyBatch(common.BatchRequest{Operations: operations})
+	if err != nil {
+		return err
+	}
+	logger.Info("cancel command: reminders cancelled: " + selectedIds(selected))
+	fmt.Printf("Cancelled: %d\n", len(selected))
+	return nil
+}
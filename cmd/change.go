@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/spf13/cobra"
 	"n0rdy.foo/remindme/common"
 	"n0rdy.foo/remindme/config"
@@ -11,7 +12,9 @@ import (
 )
 
 type ChangeFlags struct {
+	// zero if several reminders are selected: only the postponing is supported then
 	Id         int
+	Selector   ReminderSelector
 	Message    string
 	RemindAt   time.Time
 	IsPostpone bool
@@ -34,7 +37,15 @@ It is valid to provide both "--about" and "--time" OR "--about" and "--postpone"
 If the "--postpone" flag is provided, "--sec", "--min" and/or "--hr" flags should be provided alongside - otherwise, the error will be produced.
 Negative integer values are not accepted - the error will be produced in such case.
 
+Several reminders can be postponed at once: the "--id" flag accepts the comma-separated list of IDs and ID ranges (e.g. 1,2,5 or 5-8),
+and the reminders can be selected with the "--matching", "--before" and/or "--after" flags, too.
+If several criteria are provided, the reminders matching all of them are postponed.
+The selected reminders are previewed, and the confirmation is requested. The "--yes" flag skips the confirmation: e.g. for the scripts.
+
 List the upcoming reminders with the "list" command.`,
+	Example: `remindme change --id 3 --about "Call mom" --time 18:30
+remindme change --id 1,2,5-8 --postpone --min 15
+remindme change --matching standup --before 18:00 --postpone --hr 1 --yes`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Info("change command: called")
 
@@ -43,13 +54,17 @@ List the upcoming reminders with the "list" command.`,
 			return err
 		}
 
-		port, err := config.ResolveRunningServerPort()
+		address, err := config.ResolveRunningServerAddress()
 		if err != nil {
-			logger.Error("at command: error while resolving running server port", err)
-			return common.ErrCmdCannotResolveServerPort
+			logger.Error("change command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		if changeFlags.Id == 0 {
+			return postponeSelectedReminders(httpClient, *changeFlags)
 		}
 
-		httpClient := httpclient.NewHttpClient(port)
 		reminder, err := httpClient.GetReminder(changeFlags.Id)
 		if err != nil {
 			return err
@@ -67,7 +82,7 @@ List the upcoming reminders with the "list" command.`,
 func init() {
 	rootCmd.AddCommand(changeCmd)
 
-	changeCmd.Flags().Int(common.IdFlag, 0, "Reminder ID to change")
+	addReminderSelectorFlags(changeCmd, "Reminder IDs to change")
 	changeCmd.Flags().StringP(common.AboutFlag, "a", "", "Reminder message to change to")
 	changeCmd.Flags().StringP(common.TimeFlag, "t", "", "Time to change the notification to in 24-hours HH:MM format: e.g. 16:30, 07:45, 00:00")
 	changeCmd.Flags().Bool(common.PostponeFlag, false, "If provided, specifies that no new time will be provided by `--time` flag, but rather a desired shift in time using `--sec`, `--min` and/or `--hr` flags (e.g. if the notification time is 15:30 and `--postpone --min 20` is provided, the new time will be 15:50)")
@@ -75,21 +90,21 @@ func init() {
 	changeCmd.Flags().Int(common.MinutesFlag, 0, "Minutes to shift the existing notification time with - should be passed alongside the `--postpone` flag")
 	changeCmd.Flags().Int(common.HoursFlag, 0, "Hours to shift the existing notification time with - should be passed alongside the `--postpone` flag")
 
-	changeCmd.MarkFlagRequired(common.IdFlag)
+	changeCmd.RegisterFlagCompletionFunc(common.TimeFlag, completeTimes)
 }
 
 func parseChangeCmd(cmd *cobra.Command) (*ChangeFlags, error) {
 	flags := cmd.Flags()
 
-	id, err := flags.GetInt(common.IdFlag)
+	selector, err := parseReminderSelector(cmd)
 	if err != nil {
-		logger.Error("change command: error while parsing flag: "+common.IdFlag, err)
-		return nil, common.ErrWrongFormattedIntFlag(common.IdFlag)
+		return nil, err
 	}
-	if id == 0 {
-		logger.Error("change command: mandatory flag not provided: " + common.IdFlag)
+	if selector.IsEmpty() {
+		logger.Error("change command: neither IDs nor filters provided")
 		return nil, common.ErrChangeCmdIdNotProvided
 	}
+	id, _ := selector.SingleId()
 
 	message, err := flags.GetString(common.AboutFlag)
 	if err != nil {
@@ -115,6 +130,11 @@ func parseChangeCmd(cmd *cobra.Command) (*ChangeFlags, error) {
 		logger.Error("change command: both new time and postpone provided")
 		return nil, common.ErrChangeCmdInvalidTimeFlagsProvided
 	}
+	// several reminders can't get the same message or time
+	if id == 0 && (message != "" || t != "") {
+		logger.Error("change command: several reminders selected, but not only postpone provided")
+		return nil, common.ErrChangeCmdBulkWithoutPostpone
+	}
 
 	seconds, err := flags.GetInt(common.SecondsFlag)
 	if err != nil {
@@ -143,6 +163,7 @@ func parseChangeCmd(cmd *cobra.Command) (*ChangeFlags, error) {
 
 	changeFlags := ChangeFlags{
 		Id:         id,
+		Selector:   *selector,
 		Message:    message,
 		IsPostpone: isPostpone,
 	}
@@ -177,3 +198,39 @@ func changeReminder(reminder common.Reminder, changeFlags ChangeFlags) (common.R
 	}
 	return reminder, changed
 }
+
+// postponeSelectedReminders postpones the selected reminders at once: if any of them can't be postponed, none is
+func postponeSelectedReminders(httpClient httpclient.RemindmeHttpClient, changeFlags ChangeFlags) error {
+	reminders, err := httpClient.GetAllReminders()
+	if err != nil {
+		return err
+	}
+	selected, err := changeFlags.Selector.Select(reminders)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		fmt.Println("No reminders match the provided criteria")
+		return nil
+	}
+
+	shift := time.Duration(changeFlags.Hours)*time.Hour + time.Duration(changeFlags.Minutes)*time.Minute + time.Duration(changeFlags.Seconds)*time.Second
+	question := fmt.Sprintf("Postpone %d reminder(s) by %s?", len(selected), utils.HumanizeDuration(shift))
+	confirmed, err := changeFlags.Selector.confirmBulkOperation(question, selected)
+	if err != nil || !confirmed {
+		return err
+	}
+
+	operations := make([]common.BatchOperation, 0, len(selected))
+	for _, reminder := range selected {
+		modifiedReminder, _ := changeReminder(reminder, changeFlags)
+		operations = append(operations, common.BatchOperation{Type: common.BatchOperationUpdate, ID: reminder.ID, Reminder: &modifiedReminder})
+	}
+	err = httpClient.ApplyBatch(common.BatchRequest{Operations: operations})
+	if err != nil {
+		return err
+	}
+	logger.Info("change command: reminders postponed: " + selectedIds(selected))
+	fmt.Printf("Postponed: %d\n", len(selected))
+	return nil
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the completion is run on every Tab press, so it gives up quickly rather than making the shell hang
+const completionTimeout = 500 * time.Millisecond
+
+// the number of the upcoming time slots suggested by the time flags completion
+const completionTimeSlots = 6
+
+var (
+	completionDurations = []string{"5m", "10m", "15m", "30m", "1h", "2h"}
+	// suggested if they are still ahead today, after the nearest time slots
+	completionCommonTimes = []string{"09:00", "12:00", "13:00", "17:00", "18:00", "20:00", "22:00"}
+)
+
+// completeReminderIds completes the IDs of the upcoming reminders with their messages as the descriptions:
+// nothing is suggested if the server is down, as the completion shouldn't print any errors.
+// The comma-separated lists accepted by the bulk operations are completed item by item.
+func completeReminderIds(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// the completion is run by the hidden command, which doesn't see the "--profile" flag of the completed one
+	profile, err := resolveProfile(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	utils.SetProfile(profile)
+
+	address, err := config.ResolveRunningServerAddress()
+	if err != nil {
+		logger.Error("completion: error while resolving running server address", err)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	httpClient := httpclient.NewHttpClient(address)
+	httpClient.SetTimeout(completionTimeout)
+	reminders, err := httpClient.GetAllReminders()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	sortByTime(reminders, true)
+
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+	}
+	selected := make(map[string]bool)
+	for _, id := range strings.Split(prefix, ",") {
+		selected[id] = true
+	}
+
+	completions := make([]string, 0, len(reminders))
+	for _, reminder := range reminders {
+		id := strconv.FormatInt(reminder.ID, 10)
+		if selected[id] {
+			continue
+		}
+		// the description is a single line, otherwise it breaks the shell output
+		completions = append(completions, prefix+id+"\t"+strings.Join(strings.Fields(reminder.Message), " "))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeTimes suggests the nearest half-hour slots and the common times that are still ahead today, in 24-hours HH:MM format
+func completeTimes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	now := time.Now()
+	return timeCompletions(upcomingTimes(now), now, "15:04"), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeAmTimes is the same as completeTimes, but for the A.M. times in 12-hours HH:MM format
+func completeAmTimes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return complete12HoursTimes(func(t time.Time) bool { return t.Hour() < 12 })
+}
+
+// completePmTimes is the same as completeTimes, but for the P.M. times in 12-hours HH:MM format
+func completePmTimes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return complete12HoursTimes(func(t time.Time) bool { return t.Hour() >= 12 })
+}
+
+// completeDurations suggests the common durations
+func completeDurations(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completionDurations, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeFilterTimes suggests both the durations from now and the upcoming times, as the filter flags accept either
+func completeFilterTimes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	now := time.Now()
+	completions := append([]string{}, completionDurations...)
+	completions = append(completions, timeCompletions(upcomingTimes(now), now, "15:04")...)
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+func complete12HoursTimes(filter func(t time.Time) bool) ([]string, cobra.ShellCompDirective) {
+	now := time.Now()
+	times := make([]time.Time, 0)
+	for _, t := range upcomingTimes(now) {
+		if filter(t) {
+			times = append(times, t)
+		}
+	}
+	return timeCompletions(times, now, "03:04"), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// upcomingTimes returns the nearest half-hour slots followed by the common times, all of them within today
+func upcomingTimes(now time.Time) []time.Time {
+	times := make([]time.Time, 0, completionTimeSlots+len(completionCommonTimes))
+	slot := now.Truncate(time.Minute)
+	slot = slot.Add(time.Duration(30-slot.Minute()%30) * time.Minute)
+	for i := 0; i < completionTimeSlots && slot.Day() == now.Day(); i++ {
+		times = append(times, slot)
+		slot = slot.Add(30 * time.Minute)
+	}
+
+	for _, value := range completionCommonTimes {
+		t, err := time.ParseInLocation("15:04", value, time.Local)
+		if err != nil {
+			continue
+		}
+		t = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+		// the slots already include the nearest ones
+		if !t.Before(slot) {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+// timeCompletions describes the times relative to now: e.g. 16:30 is described as "in 25m"
+func timeCompletions(times []time.Time, now time.Time, layout string) []string {
+	completions := make([]string, 0, len(times))
+	for _, t := range times {
+		completions = append(completions, t.Format(layout)+"\t"+utils.RelativeTime(t, now))
+	}
+	return completions
+}
+
+// completeTemplateNames completes the names of the reminder templates with their messages as the descriptions
+func completeTemplateNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	// the same as for the reminder IDs, the templates are stored per profile
+	profile, err := resolveProfile(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	utils.SetProfile(profile)
+
+	templates, err := config.ListTemplates()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]string, 0, len(templates))
+	for _, template := range templates {
+		completions = append(completions, template.Name+"\t"+strings.Join(strings.Fields(template.Message), " "))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -16,8 +16,13 @@ var completionCmd = &cobra.Command{
 
 To load remindme completions on run
    source <(remindme completion)
-To load remindme completions automatically on login, add this line to your .bashrc, .zshrc or config.fish file: 
+To load remindme completions automatically on login, add this line to your .bashrc or .zshrc file:
 source <(remindme completion)
+or this one to your config.fish file:
+remindme completion | source
+
+Besides the commands and flags, the IDs of the upcoming reminders are completed with their messages if the app is running,
+and the common times and durations are suggested for the time flags.
 
 Please, check the PowerShell documentation (https://learn.microsoft.com/en-us/powershell/module/microsoft.powershell.core/register-argumentcompleter?view=powershell-7.3) for more information about loading completions for this shell type.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -36,12 +41,13 @@ func setCompletionIfPossible() error {
 	case common.LinuxOS, common.MacOS:
 		shellType := utils.DetectShellType()
 		switch shellType {
+		// the descriptions of the completions are included, e.g. the reminder messages for the IDs
 		case common.BashShell:
-			return rootCmd.GenBashCompletion(os.Stdout)
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
 		case common.ZshShell:
 			return rootCmd.GenZshCompletion(os.Stdout)
 		case common.FishShell:
-			return rootCmd.GenZshCompletion(os.Stdout)
+			return rootCmd.GenFishCompletion(os.Stdout, true)
 		case "":
 			logger.Error("completion command: unknown shell type error")
 			return common.ErrCompletionCmdUnknownShell
@@ -50,7 +56,7 @@ func setCompletionIfPossible() error {
 			return common.ErrCompletionCmdUnsupportedShell(shellType)
 		}
 	case common.WindowsOS:
-		return rootCmd.GenPowerShellCompletion(os.Stdout)
+		return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
 	case "":
 		logger.Error("completion command: unknown OS type error")
 		return common.ErrCompletionCmdUnknownOS
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "User configs commands: get, set, list and validate configs",
+	Long: `User configs commands: get, set, list and validate configs.
+
+The list of available subcommands:
+- config get 		- print the effective value of the config
+- config set 		- change the config in the configs file
+- config list 		- print all the configs with their effective values and sources
+- config validate 	- check both the configs file and the env vars for invalid values
+
+The configs are stored in the "remindme_configs.yaml" file in the app data directory.
+Each config can be overridden with the corresponding env var: e.g. REMINDME_LOGS_LEVEL for "logs.level" - see "config list" for the full list.
+The invalid values are replaced with the defaults.
+
+The running app reloads the configs once they are changed with the "config set" command.
+If the file has been edited manually, run "remindme admin server reload" or send the SIGHUP signal to the app process.
+The "repo.backend" config is applied on the app restart only.`,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
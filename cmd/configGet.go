@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/logger"
+)
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of the config",
+	Long: `Print the effective value of the config: either from the env var, from the configs file or the default one.
+
+If the config is not set and has no default value (e.g. quiet hours), an empty line is printed.
+Run "config list" to see the available configs.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("config get command: called")
+
+		value, err := config.GetConfig(args[0])
+		if value == nil {
+			logger.Error("config get command: unknown config requested: "+args[0], err)
+			return err
+		}
+		if err != nil {
+			// the other configs might be invalid, which doesn't affect the requested one
+			logger.Warn("config get command: invalid configs found", err)
+		}
+
+		fmt.Println(value.Value)
+		return nil
+	},
+	ValidArgsFunction: completeConfigKeys,
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+}
+
+func completeConfigKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.ConfigKeys(), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/logger"
+	"os"
+	"text/tabwriter"
+)
+
+const (
+	configsTitle    = "Key\tValue\tSource\tEnv var\tDescription"
+	configsTemplate = "%s\t%s\t%s\t%s\t%s\n"
+)
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print all the configs with their effective values and sources",
+	Long: `Print all the configs with their effective values and sources: either "env", "file" or "default".
+
+The invalid values are printed as the defaults they are replaced with: run "config validate" to see the errors.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("config list command: called")
+
+		values, err := config.ListConfigs()
+		if err != nil {
+			logger.Warn("config list command: invalid configs found", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 1, 1, 3, ' ', 0)
+		fmt.Fprintln(w, configsTitle)
+		for _, value := range values {
+			fmt.Fprintf(w, configsTemplate, value.Key, value.Value, value.Source, value.EnvVar, value.Description)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configListCmd)
+}
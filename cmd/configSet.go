@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"os"
+)
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change the config in the configs file",
+	Long: `Change the config in the configs file.
+
+The value is validated before being saved: the invalid value produces the error.
+An empty value (e.g. "config set quietHours.from ''") resets the config to its default.
+
+If the app is running, it reloads the configs right away.
+Note, that the config might be overridden with the env var - in such case, the warning is printed.
+Run "config list" to see the available configs.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("config set command: called")
+
+		key, value := args[0], args[1]
+		err := config.SetConfig(key, value)
+		if err != nil {
+			logger.Error("config set command: error while setting config: "+key, err)
+			var pathErr *os.PathError
+			if errors.As(err, &pathErr) {
+				return common.ErrConfigSetCmdCannotPersistConfigs
+			}
+			return err
+		}
+
+		effective, _ := config.GetConfig(key)
+		if effective != nil && effective.Source == config.EnvSource {
+			fmt.Println("Warning: the config is overridden with the " + effective.EnvVar + " env var")
+		}
+
+		reloadRunningServerConfigs()
+		return nil
+	},
+	ValidArgsFunction: completeConfigKeys,
+}
+
+func init() {
+	configCmd.AddCommand(configSetCmd)
+}
+
+// reloadRunningServerConfigs asks the app to reload the configs if it's running, and does nothing otherwise
+func reloadRunningServerConfigs() {
+	address, err := config.ResolveRunningServerAddress()
+	if err != nil {
+		logger.Error("config set command: error while resolving running server address", err)
+		return
+	}
+
+	httpClient := httpclient.NewHttpClient(address)
+	if !httpClient.Healthcheck() {
+		logger.Info("config set command: the app is not running, so the configs will be applied on its start")
+		return
+	}
+
+	err = httpClient.ReloadConfigs()
+	if err != nil {
+		fmt.Println("The config has been saved, but the running app hasn't applied it: " + err.Error())
+	}
+}
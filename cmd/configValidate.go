@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/logger"
+)
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check both the configs file and the env vars for invalid values",
+	Long: `Check both the configs file and the env vars for invalid values.
+
+Prints every invalid value found, and produces the error if there is any.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("config validate command: called")
+
+		err := config.ValidateConfigs()
+		if err != nil {
+			logger.Error("config validate command: invalid configs found", err)
+			fmt.Println(err)
+			return common.ErrConfigValidateCmdInvalidConfigs
+		}
+
+		fmt.Println("The configs are valid")
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+}
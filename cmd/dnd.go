@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// dndCmd represents the dnd command
+var dndCmd = &cobra.Command{
+	Use:   "dnd",
+	Short: "Do-not-disturb commands: turn the do-not-disturb mode on and off",
+	Long: `Do-not-disturb commands: turn the do-not-disturb mode on and off.
+
+The list of available subcommands:
+- dnd on 	- hold the notifications back for the provided duration: e.g. for the focus time
+- dnd off 	- turn the do-not-disturb mode off before its end
+
+Within the do-not-disturb period, the reminders are handled the same way as within the quiet hours, depending on their urgency:
+- low 		- dropped without notifying
+- normal 	- deferred till the end of the period
+- high 		- notified silently: via the desktop notification only, without the alert and beep ones
+
+The do-not-disturb mode is kept in memory, so it's turned off on the app restart.
+Run the "status" command to check whether it's on.`,
+}
+
+func init() {
+	rootCmd.AddCommand(dndCmd)
+}
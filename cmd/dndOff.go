@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+)
+
+// dndOffCmd represents the dnd off command
+var dndOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Turn the do-not-disturb mode off",
+	Long: `Turn the do-not-disturb mode off.
+
+The reminders deferred by the do-not-disturb mode are notified right away, unless it's the quiet hours.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("dnd off command: called")
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("dnd off command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		return httpClient.DisableDnd()
+	},
+}
+
+func init() {
+	dndCmd.AddCommand(dndOffCmd)
+}
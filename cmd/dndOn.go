@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"time"
+)
+
+const defaultDndDuration = "1h"
+
+// dndOnCmd represents the dnd on command
+var dndOnCmd = &cobra.Command{
+	Use:   "on",
+	Short: "Turn the do-not-disturb mode on",
+	Long: `Turn the do-not-disturb mode on.
+
+The "--for" flag specifies how long the mode lasts: e.g. 30m, 1h30m - 1 hour by default.
+If the mode is on already, its end is changed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("dnd on command: called")
+
+		duration, err := parseDndOnCmd(cmd)
+		if err != nil {
+			return err
+		}
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("dnd on command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		until := time.Now().Add(duration)
+		httpClient := httpclient.NewHttpClient(address)
+		err = httpClient.EnableDnd(until)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Do not disturb till " + until.Format(resolveTimeFormat()))
+		return nil
+	},
+}
+
+func init() {
+	dndCmd.AddCommand(dndOnCmd)
+
+	dndOnCmd.Flags().String(common.ForFlag, defaultDndDuration, "Duration of the do-not-disturb mode: e.g. 30m, 1h30m")
+
+	dndOnCmd.RegisterFlagCompletionFunc(common.ForFlag, completeDurations)
+}
+
+func parseDndOnCmd(cmd *cobra.Command) (time.Duration, error) {
+	durationAsString, err := cmd.Flags().GetString(common.ForFlag)
+	if err != nil {
+		logger.Error("dnd on command: error while parsing flag: "+common.ForFlag, err)
+		return 0, common.ErrWrongFormattedStringFlag(common.ForFlag)
+	}
+
+	duration, err := time.ParseDuration(durationAsString)
+	if err != nil || duration <= 0 {
+		logger.Error("dnd on command: invalid duration provided: " + durationAsString)
+		return 0, common.ErrDndOnCmdInvalidDuration
+	}
+	return duration, nil
+}
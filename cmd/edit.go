@@ -0,0 +1,504 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"io"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	editReminderHeader = `Change the reminder, and save the file to apply the changes, or clear the file to abort.
+The time is in "2006-01-02 15:04:05" format, and the urgency is one of: low, normal or high.`
+	editAllRemindersHeader = `Change, add or remove the reminders, and save the file to apply the changes, or clear the file to abort.
+The new reminders should have no ID, and the removed ones are cancelled.
+The time is in "2006-01-02 15:04:05" format, and the urgency is one of: low, normal or high.`
+	editErrorPrefix = "ERROR: "
+)
+
+type EditFlags struct {
+	Id    int
+	IsAll bool
+}
+
+// editedReminder is the reminder as it's edited in the file:
+// the time is kept as the text, so that the invalid one can be fixed by the user rather than typed again
+type editedReminder struct {
+	ID       int64  `yaml:"id,omitempty"`
+	Message  string `yaml:"message"`
+	RemindAt string `yaml:"remindAt"`
+	Urgency  string `yaml:"urgency"`
+}
+
+// editError is reported as the comment above the field of the edited reminder, or above the reminder itself if the field is empty
+type editError struct {
+	index   int
+	field   string
+	message string
+}
+
+// editResult is the number of the applied changes of each type
+type editResult struct {
+	changed   int
+	added     int
+	cancelled int
+	// whether the file has been cleared to abort the editing
+	aborted bool
+	// the saved file, to be kept if the changes fail to be applied
+	content []byte
+}
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit reminders in the text editor",
+	Long: `Edit reminders in the text editor.
+
+The command expects either a reminder ID to be provided via the "--id" flag, or the "--all" flag - otherwise, the error will be produced.
+The reminder (or the list of all the upcoming reminders) is opened as YAML in the editor set via the "VISUAL" or "EDITOR" env var ("vi" by default).
+Once the file is saved and the editor is closed, the changes are validated and applied at once.
+If some of them are invalid, the editor is opened again with the errors as the comments above the invalid values.
+To abort the editing, clear the file.
+
+With the "--all" flag, the new reminders can be added without the ID, and the removed reminders are cancelled.
+The changes of the reminders that have been notified about or cancelled while the editor was open are dropped.
+If the changes fail to be applied, the edited file is kept, and its path is printed.
+
+List the upcoming reminders with the "list" command.`,
+	Example: `remindme edit --id 1
+EDITOR=nano remindme edit --all`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("edit command: called")
+
+		editFlags, err := parseEditCmd(cmd)
+		if err != nil {
+			return err
+		}
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("edit command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		var originals []common.Reminder
+		if editFlags.IsAll {
+			originals, err = httpClient.GetAllReminders()
+			if err != nil {
+				return err
+			}
+			sortById(originals, true)
+		} else {
+			reminder, err := httpClient.GetReminder(editFlags.Id)
+			if err != nil {
+				return err
+			}
+			originals = []common.Reminder{*reminder}
+		}
+
+		operations, result, err := editReminders(originals, editFlags.IsAll)
+		if err != nil {
+			return err
+		}
+		if result.aborted {
+			fmt.Println("The editing has been aborted: the file has been cleared")
+			return nil
+		}
+		if editFlags.IsAll {
+			// the reminders might have been notified about or cancelled while the editor was open
+			current, err := httpClient.GetAllReminders()
+			if err != nil {
+				keepEditedFile(result.content)
+				return err
+			}
+			var dropped []int64
+			operations, dropped = dropOperationsOnMissingReminders(operations, current, &result)
+			for _, id := range dropped {
+				fmt.Printf("Reminder #%d has been notified about or cancelled while editing: its changes are dropped\n", id)
+			}
+		}
+		if len(operations) == 0 {
+			fmt.Println("Nothing to apply: the reminders haven't been changed")
+			return nil
+		}
+
+		err = httpClient.ApplyBatch(common.BatchRequest{Operations: operations})
+		if err != nil {
+			keepEditedFile(result.content)
+			return err
+		}
+		fmt.Printf("Changed: %d, added: %d, cancelled: %d\n", result.changed, result.added, result.cancelled)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+
+	editCmd.Flags().Int(common.IdFlag, 0, "Reminder ID to edit")
+	editCmd.Flags().Bool(common.AllFlag, false, "If this flag is provided, all the upcoming reminders are edited at once")
+
+	editCmd.RegisterFlagCompletionFunc(common.IdFlag, completeReminderIds)
+}
+
+func parseEditCmd(cmd *cobra.Command) (*EditFlags, error) {
+	flags := cmd.Flags()
+
+	isAll := flags.Lookup(common.AllFlag).Changed
+	id, err := flags.GetInt(common.IdFlag)
+	if err != nil {
+		logger.Error("edit command: error while parsing flag: "+common.IdFlag, err)
+		return nil, common.ErrWrongFormattedIntFlag(common.IdFlag)
+	}
+
+	// catches "no flags provided" and "all flags provided" cases
+	if (id == 0 && !isAll) || (id != 0 && isAll) {
+		logger.Error("edit command: invalid flags provided")
+		return nil, common.ErrEditCmdInvalidFlagsProvided
+	}
+
+	return &EditFlags{
+		Id:    id,
+		IsAll: isAll,
+	}, nil
+}
+
+// editReminders opens the editor until the valid changes are saved, and returns the batch operations to apply them with.
+func editReminders(originals []common.Reminder, isAll bool) ([]common.BatchOperation, editResult, error) {
+	edited := make([]editedReminder, 0, len(originals))
+	for _, reminder := range originals {
+		edited = append(edited, toEditedReminder(reminder))
+	}
+
+	content, err := encodeEditedReminders(edited, nil, isAll)
+	if err != nil {
+		return nil, editResult{}, err
+	}
+
+	for {
+		content, err = openInEditor(content)
+		if err != nil {
+			return nil, editResult{}, err
+		}
+		if isBlankYaml(content) {
+			logger.Info("edit command: editing aborted")
+			return nil, editResult{aborted: true}, nil
+		}
+
+		edited, err = decodeEditedReminders(content, isAll)
+		if err != nil {
+			logger.Info("edit command: invalid YAML provided: " + err.Error())
+			content = prependEditErrors(content, err)
+			continue
+		}
+
+		operations, result, editErrs := diffEditedReminders(originals, edited, isAll, time.Now())
+		if len(editErrs) == 0 {
+			result.content = content
+			return operations, result, nil
+		}
+
+		logger.Info(fmt.Sprintf("edit command: %d validation errors found", len(editErrs)))
+		content, err = encodeEditedReminders(edited, editErrs, isAll)
+		if err != nil {
+			return nil, editResult{}, err
+		}
+	}
+}
+
+func toEditedReminder(reminder common.Reminder) editedReminder {
+	urgency := reminder.Urgency
+	if urgency == "" {
+		urgency = common.UrgencyNormal
+	}
+	return editedReminder{
+		ID:       reminder.ID,
+		Message:  reminder.Message,
+		RemindAt: reminder.RemindAt.Local().Format(common.DateTimeFormatWithoutTimeZone),
+		Urgency:  urgency,
+	}
+}
+
+// diffEditedReminders validates the edited reminders, and turns the changes into the batch operations
+func diffEditedReminders(originals []common.Reminder, edited []editedReminder, isAll bool, now time.Time) ([]common.BatchOperation, editResult, []editError) {
+	originalsById := make(map[int64]common.Reminder, len(originals))
+	for _, reminder := range originals {
+		originalsById[reminder.ID] = reminder
+	}
+
+	operations := make([]common.BatchOperation, 0)
+	result := editResult{}
+	editErrs := make([]editError, 0)
+	seen := make(map[int64]bool, len(edited))
+	for i, reminder := range edited {
+		original, exists := originalsById[reminder.ID]
+		switch {
+		case !isAll && reminder.ID != originals[0].ID:
+			editErrs = append(editErrs, editError{index: i, field: "id", message: "the reminder ID can't be changed"})
+			continue
+		case reminder.ID != 0 && !exists:
+			editErrs = append(editErrs, editError{index: i, field: "id", message: "unknown reminder ID: the new reminders should have no ID"})
+			continue
+		case reminder.ID != 0 && seen[reminder.ID]:
+			editErrs = append(editErrs, editError{index: i, field: "id", message: "duplicate reminder ID"})
+			continue
+		}
+		seen[reminder.ID] = true
+
+		errsCount := len(editErrs)
+		message := strings.TrimSpace(reminder.Message)
+		if message == "" {
+			editErrs = append(editErrs, editError{index: i, field: "message", message: "message should be provided"})
+		}
+
+		// the time is compared as the text, as the seconds fractions are not displayed
+		remindAt := original.RemindAt
+		if !exists || reminder.RemindAt != toEditedReminder(original).RemindAt {
+			var err error
+			remindAt, err = parseEditedTime(reminder.RemindAt)
+			if err != nil {
+				editErrs = append(editErrs, editError{index: i, field: "remindAt", message: "time should be provided in \"2006-01-02 15:04:05\" format"})
+			} else if !remindAt.After(now) {
+				editErrs = append(editErrs, editError{index: i, field: "remindAt", message: common.ErrCmdTimeShouldBeInFuture.Error()})
+			}
+		}
+
+		urgency := reminder.Urgency
+		switch urgency {
+		case "":
+			urgency = common.UrgencyNormal
+		case common.UrgencyLow, common.UrgencyNormal, common.UrgencyHigh:
+		default:
+			editErrs = append(editErrs, editError{index: i, field: "urgency", message: "urgency should be one of: low, normal or high"})
+		}
+
+		if len(editErrs) > errsCount {
+			continue
+		}
+
+		modified := common.Reminder{ID: reminder.ID, Message: message, RemindAt: remindAt, Urgency: urgency}
+		if !exists {
+			operations = append(operations, common.BatchOperation{Type: common.BatchOperationCreate, Reminder: &modified})
+			result.added++
+		} else if modified.Message != original.Message || !modified.RemindAt.Equal(original.RemindAt) || modified.Urgency != toEditedReminder(original).Urgency {
+			operations = append(operations, common.BatchOperation{Type: common.BatchOperationUpdate, ID: reminder.ID, Reminder: &modified})
+			result.changed++
+		}
+	}
+
+	for _, reminder := range originals {
+		if !seen[reminder.ID] {
+			operations = append(operations, common.BatchOperation{Type: common.BatchOperationDelete, ID: reminder.ID})
+			result.cancelled++
+		}
+	}
+	return operations, result, editErrs
+}
+
+// dropOperationsOnMissingReminders drops the changes of the reminders that no longer exist, as the whole batch would be rejected otherwise,
+// and returns the IDs of the dropped ones
+func dropOperationsOnMissingReminders(operations []common.BatchOperation, current []common.Reminder, result *editResult) ([]common.BatchOperation, []int64) {
+	exists := make(map[int64]bool, len(current))
+	for _, reminder := range current {
+		exists[reminder.ID] = true
+	}
+
+	kept := make([]common.BatchOperation, 0, len(operations))
+	dropped := make([]int64, 0)
+	for _, operation := range operations {
+		switch {
+		case operation.Type == common.BatchOperationCreate || exists[operation.ID]:
+			kept = append(kept, operation)
+			continue
+		case operation.Type == common.BatchOperationUpdate:
+			result.changed--
+		case operation.Type == common.BatchOperationDelete:
+			result.cancelled--
+		}
+		dropped = append(dropped, operation.ID)
+	}
+	return kept, dropped
+}
+
+// keepEditedFile saves the edited reminders to the file that is not removed, so that the changes are not lost if they fail to be applied
+func keepEditedFile(content []byte) {
+	file, err := os.CreateTemp("", "remindme-*.yaml")
+	if err != nil {
+		logger.Error("edit command: error while creating the file to keep the changes in", err)
+		return
+	}
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		logger.Error("edit command: error while writing the file to keep the changes in", err)
+		return
+	}
+	fmt.Println("The changes haven't been applied: the edited file is kept at " + file.Name())
+}
+
+func parseEditedTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.ParseInLocation(common.DateTimeFormatWithoutTimeZone, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// encodeEditedReminders encodes either the single reminder or the list of them with the header and the validation errors as the comments
+func encodeEditedReminders(edited []editedReminder, editErrs []editError, isAll bool) ([]byte, error) {
+	var node yaml.Node
+	var err error
+	if isAll {
+		err = node.Encode(edited)
+	} else {
+		err = node.Encode(edited[0])
+	}
+	if err != nil {
+		logger.Error("edit command: error while encoding the reminders", err)
+		return nil, err
+	}
+
+	for _, editErr := range editErrs {
+		target := &node
+		if isAll {
+			target = node.Content[editErr.index]
+		}
+		// the mapping node content is the list of the keys followed by their values
+		for i := 0; i < len(target.Content); i += 2 {
+			if target.Content[i].Value == editErr.field {
+				target = target.Content[i]
+				break
+			}
+		}
+		target.HeadComment = strings.TrimPrefix(target.HeadComment+"\n"+editErrorPrefix+editErr.message, "\n")
+	}
+
+	header := editReminderHeader
+	if isAll {
+		header = editAllRemindersHeader
+	}
+	document := &yaml.Node{Kind: yaml.DocumentNode, HeadComment: header, Content: []*yaml.Node{&node}}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err = encoder.Encode(document)
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		logger.Error("edit command: error while encoding the reminders", err)
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeEditedReminders(content []byte, isAll bool) ([]editedReminder, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	// so that the typos in the field names are reported rather than ignored
+	decoder.KnownFields(true)
+
+	if isAll {
+		edited := make([]editedReminder, 0)
+		err := decoder.Decode(&edited)
+		if errors.Is(err, io.EOF) {
+			// the list has been removed, but the header has been kept
+			return edited, nil
+		}
+		return edited, err
+	}
+
+	var edited editedReminder
+	err := decoder.Decode(&edited)
+	return []editedReminder{edited}, err
+}
+
+// prependEditErrors reports the YAML syntax error at the top of the file, as it can't be placed next to the invalid value.
+// The previously reported errors are removed first.
+func prependEditErrors(content []byte, err error) []byte {
+	lines := strings.Split(string(content), "\n")
+	for len(lines) > 0 && strings.HasPrefix(lines[0], "# "+editErrorPrefix) {
+		lines = lines[1:]
+	}
+
+	errLines := make([]string, 0)
+	for _, errLine := range strings.Split(err.Error(), "\n") {
+		errLines = append(errLines, "# "+editErrorPrefix+strings.TrimSpace(errLine))
+	}
+	return []byte(strings.Join(append(errLines, lines...), "\n"))
+}
+
+// isBlankYaml reports whether the content has nothing but the comments and the whitespaces
+func isBlankYaml(content []byte) bool {
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+// openInEditor opens the content in the user's editor as the temporary file, and returns it once the editor is closed
+func openInEditor(content []byte) ([]byte, error) {
+	file, err := os.CreateTemp("", "remindme-*.yaml")
+	if err != nil {
+		logger.Error("edit command: error while creating the temporary file", err)
+		return nil, common.ErrEditCmdCannotAccessFile
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		logger.Error("edit command: error while writing the temporary file", err)
+		return nil, common.ErrEditCmdCannotAccessFile
+	}
+
+	editor := resolveEditor()
+	command := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	err = command.Run()
+	if err != nil {
+		logger.Error("edit command: error while running the editor: "+strings.Join(editor, " "), err)
+		return nil, common.ErrEditCmdEditorFailed
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		logger.Error("edit command: error while reading the edited file", err)
+		return nil, common.ErrEditCmdCannotAccessFile
+	}
+	return edited, nil
+}
+
+// resolveEditor resolves the editor command with its arguments, if any: e.g. "code --wait"
+func resolveEditor() []string {
+	for _, envVar := range []string{common.VisualEnvVar, common.EditorEnvVar} {
+		if editor := strings.Fields(os.Getenv(envVar)); len(editor) > 0 {
+			return editor
+		}
+	}
+	if utils.DetectOsType() == common.WindowsOS {
+		return []string{common.DefaultWindowsEditor}
+	}
+	return []string{common.DefaultEditor}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpserver/repo/inmemory"
+	"n0rdy.foo/remindme/httpserver/service"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	foregroundRefreshInterval = 200 * time.Millisecond
+	// the conventional exit code of the process interrupted by Ctrl-C
+	foregroundInterruptedExitCode = 130
+	// moves the cursor to the beginning of the line, and clears it
+	clearLine = "\r\033[K"
+)
+
+// runInForeground schedules the reminder within the current process rather than the running app,
+// and waits for it to be notified about while printing the countdown.
+// The result is reported via the exit code: 0 if the reminder has been notified about, 1 if it has been dropped within the quiet hours,
+// and 130 if the countdown has been interrupted with Ctrl-C.
+func runInForeground(cmd *cobra.Command, reminder common.Reminder) error {
+	cmdName := cmd.Name()
+	// the flags are valid by now, so the usage is not printed if the countdown is interrupted
+	cmd.SilenceUsage = true
+
+	settings, err := config.ResolveSettings()
+	if err != nil {
+		logger.Warn(cmdName+" command: invalid configs, falling back to defaults for the invalid values", err)
+	}
+
+	ctx := context.Background()
+	// the foreground reminder is not the app's one, so it's not published as the next reminder for the shell prompt
+	srv := service.NewReminderService(inmemory.NewImMemoryReminderRepo(), settings.NotificationBackends, settings.QuietHours, nil)
+	defer srv.Shutdown(ctx)
+
+	// subscribed before the reminder is scheduled, so that Ctrl-C doesn't kill the process in the middle of the notification
+	interruptCh := make(chan os.Signal, 1)
+	signal.Notify(interruptCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interruptCh)
+
+	err = srv.Set(ctx, reminder)
+	if err != nil {
+		logger.Error(cmdName+" command: error while scheduling the reminder in the foreground", err)
+		return err
+	}
+	logger.Info(cmdName + " command: reminder scheduled in the foreground at " + reminder.RemindAt.Format(time.RFC3339))
+
+	ticker := time.NewTicker(foregroundRefreshInterval)
+	defer ticker.Stop()
+
+	countdown := newCountdown(reminder)
+	for {
+		status, err := srv.Status(ctx)
+		if err != nil {
+			countdown.clear()
+			logger.Error(cmdName+" command: error while getting the scheduler status", err)
+			return err
+		}
+
+		if notification := status.LastNotification; notification != nil {
+			countdown.finish(*notification)
+			logger.Info(cmdName + " command: reminder notified about in the foreground")
+			return nil
+		}
+		// the timer is removed only if the reminder is notified about or dropped, while the deferred one gets the new timer
+		if status.ActiveTimers == 0 {
+			countdown.clear()
+			logger.Info(cmdName + " command: reminder dropped within the quiet hours")
+			return common.ErrForegroundCmdReminderDropped
+		}
+		countdown.update(status.NextReminder)
+
+		select {
+		case <-interruptCh:
+			countdown.clear()
+			logger.Info(cmdName + " command: countdown interrupted")
+			return &common.ExitCodeError{Code: foregroundInterruptedExitCode, Err: common.ErrForegroundCmdInterrupted}
+		case <-ticker.C:
+		}
+	}
+}
+
+// countdown prints the time left till the reminder: it's updated in place if the output is a terminal,
+// otherwise, e.g. in the CI job logs, the line is printed only if the reminder time changes
+type countdown struct {
+	message    string
+	remindAt   time.Time
+	terminal   bool
+	timeFormat string
+	printed    bool
+	deferred   bool
+}
+
+func newCountdown(reminder common.Reminder) *countdown {
+	return &countdown{
+		message:    reminder.Message,
+		remindAt:   reminder.RemindAt,
+		terminal:   utils.IsTerminal(os.Stdout),
+		timeFormat: resolveTimeFormat(),
+	}
+}
+
+// update prints the time left: the upcoming reminder is missing if its time has come, but the notification hasn't been sent yet
+func (c *countdown) update(upcoming *common.Reminder) {
+	changed := upcoming != nil && !upcoming.RemindAt.Equal(c.remindAt)
+	if changed {
+		c.remindAt = upcoming.RemindAt
+		c.deferred = true
+	}
+
+	line := fmt.Sprintf("%q at %s", c.message, c.remindAt.Format(c.timeFormat))
+	if c.deferred {
+		line += " (deferred till the end of the quiet hours)"
+	}
+
+	if c.terminal {
+		fmt.Print(clearLine + utils.FormatCountdown(time.Until(c.remindAt)) + " left: " + line)
+	} else if !c.printed || changed {
+		fmt.Println(line)
+	}
+	c.printed = true
+}
+
+func (c *countdown) finish(notification common.NotificationResult) {
+	c.clear()
+	if c.terminal {
+		// the terminal bell, as the desktop notification might be unavailable: e.g. via SSH
+		fmt.Print("\a")
+	}
+	fmt.Println("Reminder: " + notification.Message)
+	if !notification.Success {
+		fmt.Fprintln(os.Stderr, "The notification has failed to be sent: "+notification.Error)
+	}
+}
+
+func (c *countdown) clear() {
+	if c.terminal && c.printed {
+		fmt.Print(clearLine)
+	}
+}
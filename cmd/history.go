@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"os"
+	"text/tabwriter"
+)
+
+const (
+	historyTitle    = "Reminder ID\tMessage\tSent at\tResult"
+	historyTemplate = "%d\t%s\t%s\t%s\n"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Print the most recent notifications",
+	Long: `Print the most recent notifications, the latest first.
+
+The app keeps up to 100 notifications, and restores them on restart if it has been stopped properly.
+
+The "--output" flag prints the notifications as json, yaml, csv or with the Go template provided via the "--template" flag,
+e.g. '{{.SentAt}}: {{.Message}}'. The fields are: ReminderID, Message, SentAt, Success and Error.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("history command: called")
+
+		output, err := resolveOutputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("history command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		notifications, err := httpClient.GetNotifications()
+		if err != nil {
+			return err
+		}
+
+		if !output.isTable() {
+			return output.print(toNotificationsOutput(notifications))
+		}
+		printNotifications(notifications)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
+
+func printNotifications(notifications []common.NotificationResult) {
+	timeFormat := resolveTimeFormat()
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 5, ' ', 0)
+	fmt.Fprintln(w, historyTitle)
+
+	for _, notification := range notifications {
+		result := "sent"
+		if !notification.Success {
+			result = "failed: " + notification.Error
+		}
+		fmt.Fprintf(w, historyTemplate, notification.ReminderID, notification.Message, notification.SentAt.Format(timeFormat), result)
+	}
+	w.Flush()
+}
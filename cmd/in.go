@@ -22,6 +22,11 @@ The command accepts seconds, minutes and/or hours to be notified in.
 Negative integer values are not accepted - the error will be produced in such case. 
 At least one of the mentioned durations should be positive - otherwise, the error will be produced.
 The command expects a reminder message to be provided via the "--about" flag - otherwise, the error will be produced.
+The "--urgency" flag defines how the reminder is notified if it comes due within the quiet hours or the do-not-disturb mode:
+it's dropped if low, deferred till their end if normal (default), or notified silently if high.
+
+With the "--foreground" flag, the reminder is scheduled within the command itself rather than the running app, which is not needed then:
+the command prints the countdown, and exits once the reminder is notified about. Ctrl-C cancels the reminder.
 
 List the upcoming reminders with the "list" command.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -32,13 +37,22 @@ List the upcoming reminders with the "list" command.`,
 			return err
 		}
 
-		port, err := config.ResolveRunningServerPort()
+		foreground, err := cmd.Flags().GetBool(common.ForegroundFlag)
 		if err != nil {
-			logger.Error("at command: error while resolving running server port", err)
-			return common.ErrCmdCannotResolveServerPort
+			logger.Error("in command: error while parsing flag: "+common.ForegroundFlag, err)
+			return err
+		}
+		if foreground {
+			return runInForeground(cmd, *reminder)
 		}
 
-		httpClient := httpclient.NewHttpClient(port)
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("in command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
 		return httpClient.CreateReminder(*reminder)
 	},
 }
@@ -51,6 +65,10 @@ func init() {
 	inCmd.Flags().Int(common.MinutesFlag, 0, "Minutes for `in` command")
 	inCmd.Flags().Int(common.HoursFlag, 0, "Hours for `in` command")
 
+	inCmd.Flags().String(common.UrgencyFlag, common.UrgencyNormal, "Reminder urgency, which defines how it's notified within the quiet hours: low (dropped), normal (deferred till their end) or high (notified silently)")
+
+	inCmd.Flags().Bool(common.ForegroundFlag, false, "Wait for the reminder in the terminal with the countdown rather than scheduling it in the running app")
+
 	inCmd.MarkFlagRequired(common.AboutFlag)
 }
 
@@ -72,9 +90,15 @@ func parseInCmd(cmd *cobra.Command) (*common.Reminder, error) {
 		return nil, err
 	}
 
+	urgency, err := parseUrgencyFlag(flags, "in")
+	if err != nil {
+		return nil, err
+	}
+
 	return &common.Reminder{
 		Message:  message,
 		RemindAt: remindAt,
+		Urgency:  urgency,
 	}, nil
 }
 
@@ -108,3 +132,20 @@ func calcRemindAtForInFlag(flags *pflag.FlagSet) (time.Time, error) {
 
 	return utils.AddDuration(now, seconds, minutes, hours), nil
 }
+
+// parseUrgencyFlag is shared by the `in` and `at` commands
+func parseUrgencyFlag(flags *pflag.FlagSet, cmdName string) (string, error) {
+	urgency, err := flags.GetString(common.UrgencyFlag)
+	if err != nil {
+		logger.Error(cmdName+" command: error while parsing flag: "+common.UrgencyFlag, err)
+		return "", common.ErrWrongFormattedStringFlag(common.UrgencyFlag)
+	}
+
+	switch urgency {
+	case common.UrgencyLow, common.UrgencyNormal, common.UrgencyHigh:
+		return urgency, nil
+	default:
+		logger.Error(cmdName + " command: invalid urgency provided: " + urgency)
+		return "", common.ErrInAtCmdInvalidUrgency
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"n0rdy.foo/remindme/config"
 	"n0rdy.foo/remindme/httpclient"
 	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
 	"os"
 	"sort"
 	"text/tabwriter"
+	"time"
 )
 
 type SortingFlags struct {
@@ -20,6 +22,8 @@ type SortingFlags struct {
 
 const reminderTitle = "ID\tMessage\tRemind at\t"
 const reminderTemplate = "%d\t%s\t%s\n"
+const relativeReminderTitle = "ID\tMessage\tRemind at\tIn\t"
+const relativeReminderTemplate = "%d\t%s\t%s\t%s\n"
 
 // listCmd represents the list command
 var listCmd = &cobra.Command{
@@ -30,6 +34,10 @@ var listCmd = &cobra.Command{
 Please, note that due to possible eventual consistency, past reminders might be included within the list.
 This should be resolved in a matter of seconds.
 
+The "--output" flag prints the reminders as json, yaml, csv or with the Go template provided via the "--template" flag,
+e.g. '{{.ID}}: {{.Message}}' prints every reminder on its own line. The fields are: ID, Message, RemindAt, Urgency, State, CreatedAt and UpdatedAt.
+The "--relative" flag adds the column with the time left till the reminder to the table.
+
 Cancel reminder with the "cancel --id ${REMINDER_ID}" command`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Info("list command: called")
@@ -39,13 +47,24 @@ Cancel reminder with the "cancel --id ${REMINDER_ID}" command`,
 			return err
 		}
 
-		port, err := config.ResolveRunningServerPort()
+		output, err := resolveOutputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		relative, err := cmd.Flags().GetBool(common.RelativeFlag)
 		if err != nil {
-			logger.Error("at command: error while resolving running server port", err)
-			return common.ErrCmdCannotResolveServerPort
+			logger.Error("list command: error while parsing flag: "+common.RelativeFlag, err)
+			return err
 		}
 
-		httpClient := httpclient.NewHttpClient(port)
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("list command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
 		reminders, err := httpClient.GetAllReminders()
 		if err != nil {
 			return err
@@ -55,7 +74,10 @@ Cancel reminder with the "cancel --id ${REMINDER_ID}" command`,
 			sortingFlags.SortingFunc(reminders, sortingFlags.Asc)
 		}
 
-		printReminders(reminders)
+		if !output.isTable() {
+			return output.print(toRemindersOutput(reminders))
+		}
+		printReminders(reminders, relative)
 		return nil
 	},
 }
@@ -63,6 +85,7 @@ Cancel reminder with the "cancel --id ${REMINDER_ID}" command`,
 func init() {
 	rootCmd.AddCommand(listCmd)
 
+	listCmd.Flags().BoolP(common.RelativeFlag, "r", false, "Add the column with the time left till the reminder: e.g. \"in 2h 14m\"")
 	listCmd.Flags().BoolP(common.SortFlag, "s", false, "Request sorting the output. Use --id, --message or --time flags to specify the parameter to sort by. If no parameters are specified, the ID is used as a default one. If --sort flag is not specified, the order of the output is no guaranteed.")
 	listCmd.Flags().Bool(common.IdFlag, true, "Request sorting by ID")
 	listCmd.Flags().BoolP(common.MessageFlag, "m", false, "Request sorting by Message")
@@ -116,16 +139,33 @@ func resolveSorting(cmd *cobra.Command) (*SortingFlags, error) {
 	}, nil
 }
 
-func printReminders(reminders []common.Reminder) {
+func printReminders(reminders []common.Reminder, relative bool) {
+	now := time.Now()
+	timeFormat := resolveTimeFormat()
 	w := tabwriter.NewWriter(os.Stdout, 1, 1, 5, ' ', 0)
-	fmt.Fprintln(w, reminderTitle)
+	if relative {
+		fmt.Fprintln(w, relativeReminderTitle)
+	} else {
+		fmt.Fprintln(w, reminderTitle)
+	}
 
 	for _, reminder := range reminders {
-		fmt.Fprintf(w, reminderTemplate, reminder.ID, reminder.Message, reminder.RemindAt.Format(common.DateTimeFormatWithoutTimeZone))
+		if relative {
+			fmt.Fprintf(w, relativeReminderTemplate, reminder.ID, reminder.Message, reminder.RemindAt.Format(timeFormat), utils.RelativeTime(reminder.RemindAt, now))
+		} else {
+			fmt.Fprintf(w, reminderTemplate, reminder.ID, reminder.Message, reminder.RemindAt.Format(timeFormat))
+		}
 	}
 	w.Flush()
 }
 
+// resolveTimeFormat resolves the layout to display the time with according to the user configs:
+// the invalid configs have been reported on the app start already, so the default is used silently
+func resolveTimeFormat() string {
+	settings, _ := config.ResolveSettings()
+	return settings.TimeFormat
+}
+
 func sortById(reminders []common.Reminder, asc bool) {
 	sort.Slice(reminders, func(i, j int) bool {
 		return reminders[i].ID < reminders[j].ID == asc
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"strings"
+	"time"
+)
+
+const (
+	// the longer messages are truncated in the compact output, so that the prompt stays short
+	compactMessageLength = 20
+	// the state is considered outdated if the next reminder is past due for longer, e.g. if the app has crashed
+	nextReminderStaleAfter = time.Minute
+)
+
+// nextCmd represents the next command
+var nextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Print the next reminder",
+	Long: `Print the next reminder and the time left till it.
+
+The command doesn't request the app, but reads the file the app updates on every change of the schedule, so it's fast enough to be run by the shell prompt.
+The "--compact" flag prints it as "next: Standup in 4m", or nothing if there are no upcoming reminders or the app is not running.
+
+Run the "prompt" command to get the ready-made shell prompt snippet.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// the prompt runs the command on every render, so it's not logged unless the debug level is set
+		logger.Debug("next command: called")
+
+		isCompact := cmd.Flags().Lookup(common.CompactFlag).Changed
+
+		state, err := config.FetchNextReminderState()
+		if err != nil {
+			logger.Error("next command: error while reading the next reminder state", err)
+			if isCompact {
+				// the broken prompt is worse than the missing reminder
+				return nil
+			}
+			return common.ErrNextCmdCannotReadState
+		}
+
+		now := time.Now()
+		var next *common.Reminder
+		if state != nil && state.Reminder != nil && now.Sub(state.Reminder.RemindAt) < nextReminderStaleAfter {
+			next = state.Reminder
+		}
+
+		if isCompact {
+			if next != nil {
+				fmt.Printf("next: %s %s\n", truncateMessage(next.Message, compactMessageLength), compactRelativeTime(next.RemindAt, now))
+			}
+			return nil
+		}
+
+		if next == nil {
+			fmt.Println("No upcoming reminders")
+			return nil
+		}
+		fmt.Printf("Next: #%d %q at %s, %s\n", next.ID, next.Message, next.RemindAt.Format(resolveTimeFormat()), utils.RelativeTime(next.RemindAt, now))
+		if state.Scheduled > 1 {
+			fmt.Printf("Scheduled: %d\n", state.Scheduled)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(nextCmd)
+
+	nextCmd.Flags().BoolP(common.CompactFlag, "c", false, "Print the next reminder in the compact format for the shell prompt: e.g. \"next: Standup in 4m\"")
+}
+
+// compactRelativeTime keeps the most significant unit only: e.g. "in 2h" rather than "in 2h 14m"
+func compactRelativeTime(t time.Time, now time.Time) string {
+	if !t.After(now) {
+		return "now"
+	}
+	return "in " + strings.Fields(utils.HumanizeDuration(t.Sub(now)))[0]
+}
+
+// truncateMessage keeps the message on a single line too
+func truncateMessage(message string, length int) string {
+	runes := []rune(strings.Join(strings.Fields(message), " "))
+	if len(runes) <= length {
+		return string(runes)
+	}
+	return string(runes[:length-1]) + "…"
+}
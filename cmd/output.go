@@ -0,0 +1,366 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/logger"
+	"os"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// outputFormat is the format the read commands print their results in, requested via the global "--output" flag
+type outputFormat struct {
+	kind     string
+	template *template.Template
+}
+
+// tabularOutput is implemented by the output models, so that they can be printed as CSV
+type tabularOutput interface {
+	csvHeader() []string
+	csvRows() [][]string
+}
+
+// listOutput is implemented by the output models of the lists, so that the template is applied to every item
+type listOutput interface {
+	items() []any
+}
+
+// the output models have stable field names, so that the scripts don't break if the API models change
+
+type reminderOutput struct {
+	ID       int64     `json:"id" yaml:"id"`
+	Message  string    `json:"message" yaml:"message"`
+	RemindAt time.Time `json:"remindAt" yaml:"remindAt"`
+	Urgency  string    `json:"urgency" yaml:"urgency"`
+	State    string    `json:"state" yaml:"state"`
+	// nil for the reminders created by the previous app versions
+	CreatedAt *time.Time `json:"createdAt" yaml:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt" yaml:"updatedAt"`
+}
+
+type remindersOutput []reminderOutput
+
+type notificationOutput struct {
+	ReminderID int64     `json:"reminderId" yaml:"reminderId"`
+	Message    string    `json:"message" yaml:"message"`
+	SentAt     time.Time `json:"sentAt" yaml:"sentAt"`
+	Success    bool      `json:"success" yaml:"success"`
+	Error      string    `json:"error" yaml:"error"`
+}
+
+type notificationsOutput []notificationOutput
+
+type journalEntryOutput struct {
+	ID          int64           `json:"id" yaml:"id"`
+	Operation   string          `json:"operation" yaml:"operation"`
+	At          time.Time       `json:"at" yaml:"at"`
+	Description string          `json:"description" yaml:"description"`
+	Created     remindersOutput `json:"created" yaml:"created"`
+	Before      remindersOutput `json:"before" yaml:"before"`
+}
+
+type journalOutput []journalEntryOutput
+
+type statusOutput struct {
+	Status             string              `json:"status" yaml:"status"`
+	Version            string              `json:"version" yaml:"version"`
+	StartedAt          *time.Time          `json:"startedAt" yaml:"startedAt"`
+	UptimeSeconds      int64               `json:"uptimeSeconds" yaml:"uptimeSeconds"`
+	Address            string              `json:"address" yaml:"address"`
+	Storage            string              `json:"storage" yaml:"storage"`
+	DbPath             string              `json:"dbPath" yaml:"dbPath"`
+	ScheduledReminders int                 `json:"scheduledReminders" yaml:"scheduledReminders"`
+	NextReminder       *reminderOutput     `json:"nextReminder" yaml:"nextReminder"`
+	LastNotification   *notificationOutput `json:"lastNotification" yaml:"lastNotification"`
+	LastCleanupAt      *time.Time          `json:"lastCleanupAt" yaml:"lastCleanupAt"`
+	QuietUntil         *time.Time          `json:"quietUntil" yaml:"quietUntil"`
+	DndUntil           *time.Time          `json:"dndUntil" yaml:"dndUntil"`
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringP(common.OutputFlag, "o", common.TableOutput, "Output format of the list, show, status, history and undo --list commands: table, json, yaml, csv or template")
+	rootCmd.PersistentFlags().String(common.TemplateFlag, "", "Go template to print every item with alongside the \"--output template\" flag: e.g. '{{.ID}} {{.Message}}'")
+}
+
+// resolveOutputFormat should be called before the request to the server, so that the invalid flags are reported right away
+func resolveOutputFormat(cmd *cobra.Command) (*outputFormat, error) {
+	kind, err := cmd.Flags().GetString(common.OutputFlag)
+	if err != nil {
+		logger.Error(cmd.Name()+" command: error while parsing flag: "+common.OutputFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.OutputFlag)
+	}
+
+	switch kind {
+	case common.TableOutput, common.JsonOutput, common.YamlOutput, common.CsvOutput:
+		return &outputFormat{kind: kind}, nil
+	case common.TemplateOutput:
+	default:
+		logger.Error(cmd.Name() + " command: invalid output format provided: " + kind)
+		return nil, common.ErrCmdInvalidOutput
+	}
+
+	text, err := cmd.Flags().GetString(common.TemplateFlag)
+	if err != nil {
+		logger.Error(cmd.Name()+" command: error while parsing flag: "+common.TemplateFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.TemplateFlag)
+	}
+	if text == "" {
+		logger.Error(cmd.Name() + " command: template output requested, but no template provided")
+		return nil, common.ErrCmdTemplateNotProvided
+	}
+
+	tmpl, err := template.New(common.TemplateOutput).Parse(text)
+	if err != nil {
+		logger.Error(cmd.Name()+" command: invalid template provided: "+text, err)
+		return nil, common.ErrCmdInvalidTemplate
+	}
+	return &outputFormat{kind: kind, template: tmpl}, nil
+}
+
+func (of *outputFormat) isTable() bool {
+	return of.kind == common.TableOutput
+}
+
+// print prints the output model in the requested format, except for the table one, which is specific to every command
+func (of *outputFormat) print(output tabularOutput) error {
+	switch of.kind {
+	case common.JsonOutput:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	case common.YamlOutput:
+		encoder := yaml.NewEncoder(os.Stdout)
+		defer encoder.Close()
+		return encoder.Encode(output)
+	case common.CsvOutput:
+		writer := csv.NewWriter(os.Stdout)
+		writer.Write(output.csvHeader())
+		writer.WriteAll(output.csvRows())
+		return writer.Error()
+	default:
+		return of.printTemplate(output)
+	}
+}
+
+// printTemplate prints every item of the list on its own line, or the single one otherwise
+func (of *outputFormat) printTemplate(output tabularOutput) error {
+	items := []any{output}
+	if list, ok := output.(listOutput); ok {
+		items = list.items()
+	}
+
+	for _, item := range items {
+		err := of.template.Execute(os.Stdout, item)
+		if err != nil {
+			logger.Error("error while executing template", err)
+			return common.ErrCmdInvalidTemplate
+		}
+		os.Stdout.WriteString("\n")
+	}
+	return nil
+}
+
+func toReminderOutput(reminder common.Reminder) reminderOutput {
+	urgency := reminder.Urgency
+	if urgency == "" {
+		urgency = common.UrgencyNormal
+	}
+	output := reminderOutput{
+		ID:       reminder.ID,
+		Message:  reminder.Message,
+		RemindAt: reminder.RemindAt,
+		Urgency:  urgency,
+		State:    reminderState(reminder, time.Now()),
+	}
+	if !reminder.CreatedAt.IsZero() {
+		output.CreatedAt = &reminder.CreatedAt
+	}
+	if !reminder.UpdatedAt.IsZero() {
+		output.UpdatedAt = &reminder.UpdatedAt
+	}
+	return output
+}
+
+func toRemindersOutput(reminders []common.Reminder) remindersOutput {
+	output := make(remindersOutput, 0, len(reminders))
+	for _, reminder := range reminders {
+		output = append(output, toReminderOutput(reminder))
+	}
+	return output
+}
+
+func toNotificationOutput(notification common.NotificationResult) notificationOutput {
+	return notificationOutput{
+		ReminderID: notification.ReminderID,
+		Message:    notification.Message,
+		SentAt:     notification.SentAt,
+		Success:    notification.Success,
+		Error:      notification.Error,
+	}
+}
+
+func toNotificationsOutput(notifications []common.NotificationResult) notificationsOutput {
+	output := make(notificationsOutput, 0, len(notifications))
+	for _, notification := range notifications {
+		output = append(output, toNotificationOutput(notification))
+	}
+	return output
+}
+
+func toJournalOutput(journal []common.JournalEntry) journalOutput {
+	output := make(journalOutput, 0, len(journal))
+	for _, entry := range journal {
+		output = append(output, journalEntryOutput{
+			ID:          entry.ID,
+			Operation:   entry.Operation,
+			At:          entry.At,
+			Description: describeJournalEntry(entry),
+			Created:     toRemindersOutput(entry.Created),
+			Before:      toRemindersOutput(entry.Before),
+		})
+	}
+	return output
+}
+
+func toStatusOutput(status common.Status) statusOutput {
+	output := statusOutput{
+		Status:             status.Status,
+		Version:            status.Server.Version,
+		StartedAt:          &status.Server.StartedAt,
+		UptimeSeconds:      status.UptimeSeconds,
+		Address:            status.Server.Address,
+		Storage:            status.Server.RepoType,
+		DbPath:             status.Server.DbPath,
+		ScheduledReminders: status.Scheduler.ActiveTimers,
+		LastCleanupAt:      status.Scheduler.LastCleanupAt,
+		QuietUntil:         status.Scheduler.QuietUntil,
+		DndUntil:           status.Scheduler.DndUntil,
+	}
+	if status.Scheduler.NextReminder != nil {
+		nextReminder := toReminderOutput(*status.Scheduler.NextReminder)
+		output.NextReminder = &nextReminder
+	}
+	if status.Scheduler.LastNotification != nil {
+		lastNotification := toNotificationOutput(*status.Scheduler.LastNotification)
+		output.LastNotification = &lastNotification
+	}
+	return output
+}
+
+func (ro reminderOutput) csvHeader() []string {
+	return remindersOutput{}.csvHeader()
+}
+
+func (ro reminderOutput) csvRows() [][]string {
+	return remindersOutput{ro}.csvRows()
+}
+
+func (ro remindersOutput) csvHeader() []string {
+	return []string{"id", "message", "remindAt", "urgency", "state", "createdAt", "updatedAt"}
+}
+
+func (ro remindersOutput) csvRows() [][]string {
+	rows := make([][]string, 0, len(ro))
+	for _, reminder := range ro {
+		rows = append(rows, []string{
+			strconv.FormatInt(reminder.ID, 10), reminder.Message, reminder.RemindAt.Format(time.RFC3339), reminder.Urgency, reminder.State,
+			formatOptionalTime(reminder.CreatedAt), formatOptionalTime(reminder.UpdatedAt),
+		})
+	}
+	return rows
+}
+
+func (ro remindersOutput) items() []any {
+	items := make([]any, 0, len(ro))
+	for _, reminder := range ro {
+		items = append(items, reminder)
+	}
+	return items
+}
+
+func (no notificationsOutput) csvHeader() []string {
+	return []string{"reminderId", "message", "sentAt", "success", "error"}
+}
+
+func (no notificationsOutput) csvRows() [][]string {
+	rows := make([][]string, 0, len(no))
+	for _, notification := range no {
+		rows = append(rows, []string{
+			strconv.FormatInt(notification.ReminderID, 10), notification.Message, notification.SentAt.Format(time.RFC3339),
+			strconv.FormatBool(notification.Success), notification.Error,
+		})
+	}
+	return rows
+}
+
+func (no notificationsOutput) items() []any {
+	items := make([]any, 0, len(no))
+	for _, notification := range no {
+		items = append(items, notification)
+	}
+	return items
+}
+
+func (jo journalOutput) csvHeader() []string {
+	return []string{"id", "operation", "at", "description"}
+}
+
+// csvRows omits the affected reminders, as CSV can't represent them
+func (jo journalOutput) csvRows() [][]string {
+	rows := make([][]string, 0, len(jo))
+	for _, entry := range jo {
+		rows = append(rows, []string{strconv.FormatInt(entry.ID, 10), entry.Operation, entry.At.Format(time.RFC3339), entry.Description})
+	}
+	return rows
+}
+
+func (jo journalOutput) items() []any {
+	items := make([]any, 0, len(jo))
+	for _, entry := range jo {
+		items = append(items, entry)
+	}
+	return items
+}
+
+func (so statusOutput) csvHeader() []string {
+	return []string{
+		"status", "version", "startedAt", "uptimeSeconds", "address", "storage", "dbPath", "scheduledReminders",
+		"nextReminderId", "nextReminderAt", "lastNotificationReminderId", "lastNotificationSentAt", "lastNotificationSuccess",
+		"lastCleanupAt", "quietUntil", "dndUntil",
+	}
+}
+
+// csvRows flattens the nested fields, as CSV can't represent them
+func (so statusOutput) csvRows() [][]string {
+	row := []string{
+		so.Status, so.Version, formatOptionalTime(so.StartedAt), strconv.FormatInt(so.UptimeSeconds, 10), so.Address, so.Storage, so.DbPath,
+		strconv.Itoa(so.ScheduledReminders),
+	}
+	if so.NextReminder != nil {
+		row = append(row, strconv.FormatInt(so.NextReminder.ID, 10), so.NextReminder.RemindAt.Format(time.RFC3339))
+	} else {
+		row = append(row, "", "")
+	}
+	if so.LastNotification != nil {
+		row = append(row,
+			strconv.FormatInt(so.LastNotification.ReminderID, 10), so.LastNotification.SentAt.Format(time.RFC3339),
+			strconv.FormatBool(so.LastNotification.Success),
+		)
+	} else {
+		row = append(row, "", "", "")
+	}
+	row = append(row, formatOptionalTime(so.LastCleanupAt), formatOptionalTime(so.QuietUntil), formatOptionalTime(so.DndUntil))
+	return [][]string{row}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// pomodoroCmd represents the pomodoro command
+var pomodoroCmd = &cobra.Command{
+	Use:   "pomodoro",
+	Short: "Pomodoro commands: start, check, skip and stop the chain of the work and break intervals",
+	Long: `Pomodoro commands: start, check, skip and stop the chain of the work and break intervals.
+
+The list of available subcommands:
+- pomodoro start 	- schedule the whole chain of the work and break reminders
+- pomodoro status 	- print the current interval and the rest of the session
+- pomodoro skip 	- end the current interval right away, and move the rest of the chain earlier
+- pomodoro stop 	- cancel the rest of the chain
+
+The chain consists of the regular reminders, which are listed by the "list" command, and notified about the same way as any other ones.
+They are linked as the group though, so that the pomodoro commands manage the chain as a unit without touching the other reminders.
+Only one session runs at a time: it's over once none of its reminders is scheduled.
+
+Each of the start, skip and stop commands can be undone as a whole with the "undo" command.`,
+}
+
+func init() {
+	rootCmd.AddCommand(pomodoroCmd)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+)
+
+// pomodoroSkipCmd represents the pomodoro skip command
+var pomodoroSkipCmd = &cobra.Command{
+	Use:   "skip",
+	Short: "Skip the current interval of the pomodoro session",
+	Long: `Skip the current interval of the pomodoro session: e.g. to start the break earlier, or to get back to work before the break is over.
+
+The interval ends right away without the notification, and the rest of the chain is moved earlier by the time left, so that the intervals keep their durations.
+Skipping the last interval ends the session.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("pomodoro skip command: called")
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("pomodoro skip command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		pomodoro, err := httpClient.SkipPomodoroInterval()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Skipped")
+		printPomodoro(*pomodoro)
+		return nil
+	},
+}
+
+func init() {
+	pomodoroCmd.AddCommand(pomodoroSkipCmd)
+}
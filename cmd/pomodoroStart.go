@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPomodoroWork   = "25m"
+	defaultPomodoroBreak  = "5m"
+	defaultPomodoroCycles = 4
+)
+
+// pomodoroStartCmd represents the pomodoro start command
+var pomodoroStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the pomodoro session",
+	Long: `Start the pomodoro session: the cycles of the work interval followed by the break, except for the last cycle.
+
+The whole chain of the reminders is scheduled right away: each one notifies about the end of the interval.
+The "--work" and "--break" flags specify the durations of the intervals: e.g. 50m, 1h - 25 and 5 minutes by default.
+The "--cycles" flag specifies the number of the work intervals: from 1 to 24, 4 by default.
+
+Only one session runs at a time: stop the running one with the "pomodoro stop" command first.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("pomodoro start command: called")
+
+		pomodoroReq, err := parsePomodoroStartCmd(cmd)
+		if err != nil {
+			return err
+		}
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("pomodoro start command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		pomodoro, err := httpClient.StartPomodoro(*pomodoroReq)
+		if err != nil {
+			return err
+		}
+
+		work := time.Duration(pomodoro.WorkSeconds) * time.Second
+		breakDuration := time.Duration(pomodoro.BreakSeconds) * time.Second
+		fmt.Printf("Pomodoro started: %d cycles of %s work and %s break\n", pomodoro.Cycles, utils.HumanizeDuration(work), utils.HumanizeDuration(breakDuration))
+		printPomodoro(*pomodoro)
+		return nil
+	},
+}
+
+func init() {
+	pomodoroCmd.AddCommand(pomodoroStartCmd)
+
+	pomodoroStartCmd.Flags().String(common.WorkFlag, defaultPomodoroWork, "Duration of the work interval: e.g. 25m, 50m")
+	pomodoroStartCmd.Flags().String(common.BreakFlag, defaultPomodoroBreak, "Duration of the break: e.g. 5m, 10m")
+	pomodoroStartCmd.Flags().Int(common.CyclesFlag, defaultPomodoroCycles, "Number of the work intervals: from 1 to 24")
+
+	pomodoroStartCmd.RegisterFlagCompletionFunc(common.WorkFlag, completeDurations)
+	pomodoroStartCmd.RegisterFlagCompletionFunc(common.BreakFlag, completeDurations)
+}
+
+func parsePomodoroStartCmd(cmd *cobra.Command) (*common.PomodoroRequest, error) {
+	flags := cmd.Flags()
+
+	workAsString, err := flags.GetString(common.WorkFlag)
+	if err != nil {
+		logger.Error("pomodoro start command: error while parsing flag: "+common.WorkFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.WorkFlag)
+	}
+	breakAsString, err := flags.GetString(common.BreakFlag)
+	if err != nil {
+		logger.Error("pomodoro start command: error while parsing flag: "+common.BreakFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.BreakFlag)
+	}
+	cycles, err := flags.GetInt(common.CyclesFlag)
+	if err != nil {
+		logger.Error("pomodoro start command: error while parsing flag: "+common.CyclesFlag, err)
+		return nil, common.ErrWrongFormattedIntFlag(common.CyclesFlag)
+	}
+
+	work, err := time.ParseDuration(workAsString)
+	// the reminders are scheduled with the seconds precision
+	if err != nil || work < time.Second {
+		logger.Error("pomodoro start command: invalid work duration provided: " + workAsString)
+		return nil, common.ErrPomodoroStartCmdInvalidDuration
+	}
+	breakDuration, err := time.ParseDuration(breakAsString)
+	if err != nil || breakDuration < time.Second {
+		logger.Error("pomodoro start command: invalid break duration provided: " + breakAsString)
+		return nil, common.ErrPomodoroStartCmdInvalidDuration
+	}
+	if cycles < 1 || cycles > common.PomodoroMaxCycles {
+		logger.Error("pomodoro start command: invalid number of cycles provided: " + strconv.Itoa(cycles))
+		return nil, common.ErrPomodoroStartCmdInvalidCycles
+	}
+
+	return &common.PomodoroRequest{
+		WorkSeconds:  int64(work.Seconds()),
+		BreakSeconds: int64(breakDuration.Seconds()),
+		Cycles:       cycles,
+	}, nil
+}
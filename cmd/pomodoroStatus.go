@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"time"
+)
+
+// pomodoroStatusCmd represents the pomodoro status command
+var pomodoroStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print the current interval of the pomodoro session",
+	Long: `Print the current interval of the pomodoro session, the time left till its end, the next interval, and the end of the whole session.
+
+The times reflect the changes made to the reminders of the chain: e.g. the ones deferred due to the quiet hours.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("pomodoro status command: called")
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("pomodoro status command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		pomodoro, err := httpClient.GetPomodoro()
+		if err != nil {
+			return err
+		}
+
+		printPomodoro(*pomodoro)
+		return nil
+	},
+}
+
+func init() {
+	pomodoroCmd.AddCommand(pomodoroStatusCmd)
+}
+
+// printPomodoro prints the current interval and what's next: the session is expected to have the intervals left
+func printPomodoro(pomodoro common.Pomodoro) {
+	if len(pomodoro.Intervals) == 0 {
+		fmt.Println("The session is over")
+		return
+	}
+
+	now := time.Now()
+	timeFormat := resolveTimeFormat()
+	current := pomodoro.Intervals[0]
+	fmt.Printf("Pomodoro %d/%d: %s till %s, %s left\n", current.Cycle, pomodoro.Cycles, current.Type, current.EndsAt.Format(timeFormat), utils.HumanizeDuration(current.EndsAt.Sub(now)))
+
+	if len(pomodoro.Intervals) == 1 {
+		fmt.Println("It's the last interval of the session")
+		return
+	}
+	next := pomodoro.Intervals[1]
+	fmt.Printf("Then: %s till %s\n", next.Type, next.EndsAt.Format(timeFormat))
+	last := pomodoro.Intervals[len(pomodoro.Intervals)-1]
+	fmt.Printf("The session ends at %s, %s\n", last.EndsAt.Format(timeFormat), utils.RelativeTime(last.EndsAt, now))
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+)
+
+// pomodoroStopCmd represents the pomodoro stop command
+var pomodoroStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the pomodoro session",
+	Long: `Stop the pomodoro session: the reminders of the rest of the chain are cancelled, while the other reminders are kept as is.
+
+Run the "undo" command to restore the session.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("pomodoro stop command: called")
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("pomodoro stop command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		pomodoro, err := httpClient.StopPomodoro()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Pomodoro stopped: %d reminder(s) cancelled\n", len(pomodoro.Intervals))
+		return nil
+	},
+}
+
+func init() {
+	pomodoroCmd.AddCommand(pomodoroStopCmd)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+)
+
+// the snippets can be loaded several times, e.g. on the config reload, so they don't add the next reminder to the prompt twice
+const (
+	bashPromptSnippet = `__remindme_prompt() {
+    local next
+    next="$(remindme next --compact 2>/dev/null)"
+    if [ -n "$next" ]; then
+        printf '[%s] ' "$next"
+    fi
+}
+case "$PS1" in
+    *__remindme_prompt*) ;;
+    *) PS1='$(__remindme_prompt)'"$PS1" ;;
+esac
+`
+	zshPromptSnippet = `setopt prompt_subst
+__remindme_prompt() {
+    local next
+    next="$(remindme next --compact 2>/dev/null)"
+    if [[ -n "$next" ]]; then
+        printf '[%s]' "$next"
+    fi
+}
+if [[ "$RPROMPT" != *__remindme_prompt* ]]; then
+    RPROMPT='$(__remindme_prompt)'"$RPROMPT"
+fi
+`
+	fishPromptSnippet = `function __remindme_prompt
+    set -l next (remindme next --compact 2>/dev/null)
+    if test -n "$next"
+        echo -n "[$next] "
+    end
+end
+if functions -q fish_right_prompt; and not functions -q __remindme_original_right_prompt
+    functions -c fish_right_prompt __remindme_original_right_prompt
+end
+function fish_right_prompt
+    __remindme_prompt
+    if functions -q __remindme_original_right_prompt
+        __remindme_original_right_prompt
+    end
+end
+`
+)
+
+// promptCmd represents the prompt command
+var promptCmd = &cobra.Command{
+	Use:       "prompt [bash|zsh|fish]",
+	Short:     "Generate the shell prompt snippet showing the next reminder",
+	ValidArgs: []string{common.BashShell, common.ZshShell, common.FishShell},
+	Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	Long: `Generate the shell prompt snippet showing the next reminder: e.g. "[next: Standup in 4m]".
+
+The snippet is generated for the current shell, unless the shell is provided as the argument: bash, zsh or fish.
+The reminder is added in front of the bash prompt, and to the right prompt of zsh and fish.
+
+To show the next reminder in the prompt on login, add this line to your .bashrc or .zshrc file:
+eval "$(remindme prompt)"
+or this one to your config.fish file:
+remindme prompt | source
+
+The prompt runs the "next" command, which reads the file the app keeps up to date rather than requesting the app, so it doesn't slow the shell down.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("prompt command: called")
+
+		shellType := utils.DetectShellType()
+		if len(args) > 0 {
+			shellType = args[0]
+		}
+
+		var snippet string
+		switch shellType {
+		case common.BashShell:
+			snippet = bashPromptSnippet
+		case common.ZshShell:
+			snippet = zshPromptSnippet
+		case common.FishShell:
+			snippet = fishPromptSnippet
+		case "":
+			logger.Error("prompt command: unknown shell type error")
+			return common.ErrPromptCmdUnknownShell
+		default:
+			logger.Error("prompt command: unsupported shell type error: " + shellType)
+			return common.ErrPromptCmdUnsupportedShell(shellType)
+		}
+
+		fmt.Print(snippet)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+}
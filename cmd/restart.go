@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+)
+
+// restartCmd represents the restart command
+var restartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart the remindme app",
+	Long: `Restart the remindme app.
+
+Under the hood, the command stops the HTTP server if it's running, and starts it again.
+The reminders persisted in SQLite are restored on start, while the in-memory ones are lost.
+
+The app is restarted at the same address it has been running at, unless the "--transport" or "--port" flags are provided.
+If the app is not running, the command starts it the same way the "start" command does.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("restart command: called")
+
+		address, err := resolveRestartAddress(cmd)
+		if err != nil {
+			return err
+		}
+
+		_, running, _ := config.ReadRunningPid()
+		runningAddress, err := config.ResolveRunningServerAddress()
+		if err == nil && !running {
+			// the server started by the previous versions of the app doesn't hold the PID file
+			httpClient := httpclient.NewHttpClient(runningAddress)
+			running = httpClient.Healthcheck()
+		}
+
+		if running {
+			err = stopApp()
+			if err != nil {
+				return err
+			}
+		}
+		return startApp(address)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restartCmd)
+
+	restartCmd.Flags().IntP(common.PortFlag, "p", common.DefaultHttpServerPort, "Port to restart the HTTP server at - implies TCP transport")
+	restartCmd.Flags().String(common.TransportFlag, "", "Transport to restart the HTTP server with: either unix (Unix domain socket) or tcp")
+}
+
+// resolveRestartAddress keeps the address the server has been running at, unless another one is requested via the flags
+func resolveRestartAddress(cmd *cobra.Command) (common.ServerAddress, error) {
+	if cmd.Flags().Changed(common.PortFlag) || cmd.Flags().Changed(common.TransportFlag) {
+		return resolveServerAddress(cmd)
+	}
+
+	address, err := config.ResolveRunningServerAddress()
+	if err != nil {
+		logger.Error("restart command: error while resolving running server address", err)
+		return address, common.ErrCmdCannotResolveServerAddress
+	}
+	return address, nil
+}
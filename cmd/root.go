@@ -1,31 +1,93 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
 	"os"
+	"regexp"
 
 	"github.com/spf13/cobra"
 )
 
+// the profile name is a part of the data dir path, so only the characters that are safe for the paths on any OS are allowed
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:     "remindme",
 	Short:   "A tool to set reminders from the terminal",
 	Long:    `A tool to set reminders from the terminal.`,
-	Version: "1.2.0",
+	Version: common.AppVersion,
+	// the profile defines where the configs and logs are, so it's resolved before anything else
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		profile, err := resolveProfile(cmd)
+		if err != nil {
+			return err
+		}
+		utils.SetProfile(profile)
+
+		settings, err := config.ResolveSettings()
+		// the server reports them to its logs, as its output is not shown to the user
+		if err != nil && cmd != adminServerStartCmd {
+			fmt.Fprintln(os.Stderr, "invalid configs, falling back to defaults for the invalid values:", err)
+		}
+
+		// the app works without the logs if the logs file can't be opened
+		logger.SetupLogger(utils.GetOsSpecificAppDataDir(), common.ClientLogsFileName, settings.Logger)
+		logger.SetRequestId(httpclient.RequestId())
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := rootCmd.Execute()
+	logger.Close()
 	if err != nil {
+		if httpclient.RequestSent() {
+			// so that the failed request can be found in both client and server logs
+			fmt.Fprintln(os.Stderr, "Request ID: "+httpclient.RequestId()+" (run \"remindme admin logs print --request "+httpclient.RequestId()+"\" to see the related logs)")
+		}
+		var exitCodeErr *common.ExitCodeError
+		if errors.As(err, &exitCodeErr) {
+			os.Exit(exitCodeErr.Code)
+		}
 		os.Exit(1)
 	}
 }
 
 func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.PersistentFlags().String(common.ProfileFlag, "", "Profile to work with: each profile has its own reminders, configs, logs and server - \"default\" if not provided")
 
 	versionTemplate := `{{printf "%s version %s\n" .Name .Version}}`
 	rootCmd.SetVersionTemplate(versionTemplate)
 }
+
+// resolveProfile resolves the profile in the following order:
+// 1. From the `--profile` flag
+// 2. From the `REMINDME_PROFILE` environment variable
+// 3. The default profile, which is returned as an empty string
+func resolveProfile(cmd *cobra.Command) (string, error) {
+	profile, err := cmd.Flags().GetString(common.ProfileFlag)
+	if err != nil {
+		return "", common.ErrWrongFormattedStringFlag(common.ProfileFlag)
+	}
+	if profile == "" {
+		profile = os.Getenv(common.ProfileEnvVar)
+	}
+
+	if profile == "" || profile == common.DefaultProfile {
+		return "", nil
+	}
+	if !profileNamePattern.MatchString(profile) {
+		return "", common.ErrCmdInvalidProfile
+	}
+	return profile, nil
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReminderSelector selects the reminders for the bulk operations by the IDs, the ID ranges and the filters: all the provided criteria should match
+type ReminderSelector struct {
+	Ids      []int64
+	Ranges   []IdRange
+	Matching string
+	Before   time.Time
+	After    time.Time
+	// skips the confirmation prompt
+	Yes bool
+}
+
+// IdRange is inclusive on both ends
+type IdRange struct {
+	From int64
+	To   int64
+}
+
+// addReminderSelectorFlags adds the flags parsed by parseReminderSelector to the command
+func addReminderSelectorFlags(cmd *cobra.Command, idUsage string) {
+	cmd.Flags().StringSlice(common.IdFlag, nil, idUsage+": either a single ID, the comma-separated list of IDs or ID ranges, e.g. 3, 1,2,5 or 5-8")
+	cmd.Flags().String(common.MatchingFlag, "", "Select the reminders with the message that contains the provided text, case-insensitively")
+	cmd.Flags().String(common.BeforeFlag, "", "Select the reminders due before the provided time: either in the duration from now (e.g. 2h), or in one of the formats: 15:04, 2006-01-02, 2006-01-02 15:04:05")
+	cmd.Flags().String(common.AfterFlag, "", "Select the reminders due after the provided time: either in the duration from now (e.g. 2h), or in one of the formats: 15:04, 2006-01-02, 2006-01-02 15:04:05")
+	cmd.Flags().BoolP(common.YesFlag, "y", false, "Skip the confirmation prompt if several reminders are selected: e.g. for the scripts")
+
+	cmd.RegisterFlagCompletionFunc(common.IdFlag, completeReminderIds)
+	cmd.RegisterFlagCompletionFunc(common.BeforeFlag, completeFilterTimes)
+	cmd.RegisterFlagCompletionFunc(common.AfterFlag, completeFilterTimes)
+}
+
+func parseReminderSelector(cmd *cobra.Command) (*ReminderSelector, error) {
+	flags := cmd.Flags()
+	cmdName := cmd.Name()
+
+	idsAsStrings, err := flags.GetStringSlice(common.IdFlag)
+	if err != nil {
+		logger.Error(cmdName+" command: error while parsing flag: "+common.IdFlag, err)
+		return nil, common.ErrCmdInvalidIds
+	}
+	selector := ReminderSelector{}
+	for _, idAsString := range idsAsStrings {
+		from, to, isRange := strings.Cut(strings.TrimSpace(idAsString), "-")
+		fromId, err := strconv.ParseInt(from, 10, 64)
+		if err != nil || fromId <= 0 {
+			logger.Error(cmdName + " command: invalid ID provided: " + idAsString)
+			return nil, common.ErrCmdInvalidIds
+		}
+		if !isRange {
+			selector.Ids = append(selector.Ids, fromId)
+			continue
+		}
+
+		toId, err := strconv.ParseInt(to, 10, 64)
+		if err != nil || toId < fromId {
+			logger.Error(cmdName + " command: invalid ID range provided: " + idAsString)
+			return nil, common.ErrCmdInvalidIds
+		}
+		selector.Ranges = append(selector.Ranges, IdRange{From: fromId, To: toId})
+	}
+
+	selector.Matching, err = flags.GetString(common.MatchingFlag)
+	if err != nil {
+		logger.Error(cmdName+" command: error while parsing flag: "+common.MatchingFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.MatchingFlag)
+	}
+
+	now := time.Now()
+	for flagName, target := range map[string]*time.Time{common.BeforeFlag: &selector.Before, common.AfterFlag: &selector.After} {
+		value, err := flags.GetString(flagName)
+		if err != nil {
+			logger.Error(cmdName+" command: error while parsing flag: "+flagName, err)
+			return nil, common.ErrWrongFormattedStringFlag(flagName)
+		}
+		if value == "" {
+			continue
+		}
+
+		*target, err = parseFilterTime(value, now)
+		if err != nil {
+			logger.Error(cmdName+" command: invalid time provided via flag: "+flagName+": "+value, err)
+			return nil, common.ErrCmdWrongFormattedFilterTime(flagName)
+		}
+	}
+
+	selector.Yes = flags.Lookup(common.YesFlag).Changed
+	return &selector, nil
+}
+
+// parseFilterTime treats the duration as the one from now, unlike utils.ParsePointInTime, as the upcoming reminders are filtered
+func parseFilterTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(d), nil
+	}
+	return utils.ParsePointInTime(value, now)
+}
+
+func (rs *ReminderSelector) IsEmpty() bool {
+	return len(rs.Ids) == 0 && len(rs.Ranges) == 0 && !rs.hasFilters()
+}
+
+// SingleId returns the ID if the only reminder is requested by it: such a request is handled without the preview and the confirmation, as before
+func (rs *ReminderSelector) SingleId() (int, bool) {
+	if len(rs.Ids) == 1 && len(rs.Ranges) == 0 && !rs.hasFilters() {
+		return int(rs.Ids[0]), true
+	}
+	return 0, false
+}
+
+func (rs *ReminderSelector) hasFilters() bool {
+	return rs.Matching != "" || !rs.Before.IsZero() || !rs.After.IsZero()
+}
+
+// Select returns the matching reminders sorted by time.
+// Every ID requested explicitly should exist, while the ranges select the existing reminders only.
+func (rs *ReminderSelector) Select(reminders []common.Reminder) ([]common.Reminder, error) {
+	existing := make(map[int64]bool, len(reminders))
+	for _, reminder := range reminders {
+		existing[reminder.ID] = true
+	}
+	missing := make([]string, 0)
+	for _, id := range rs.Ids {
+		if !existing[id] {
+			missing = append(missing, strconv.FormatInt(id, 10))
+		}
+	}
+	if len(missing) > 0 {
+		logger.Error("reminders not found by IDs: " + strings.Join(missing, ","))
+		return nil, common.ErrCmdRemindersNotFound(missing)
+	}
+
+	selected := make([]common.Reminder, 0)
+	for _, reminder := range reminders {
+		if rs.matches(reminder) {
+			selected = append(selected, reminder)
+		}
+	}
+	sortByTime(selected, true)
+	return selected, nil
+}
+
+func (rs *ReminderSelector) matches(reminder common.Reminder) bool {
+	if len(rs.Ids) > 0 || len(rs.Ranges) > 0 {
+		found := false
+		for _, id := range rs.Ids {
+			found = found || id == reminder.ID
+		}
+		for _, idRange := range rs.Ranges {
+			found = found || (reminder.ID >= idRange.From && reminder.ID <= idRange.To)
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if rs.Matching != "" && !strings.Contains(strings.ToLower(reminder.Message), strings.ToLower(rs.Matching)) {
+		return false
+	}
+	if !rs.Before.IsZero() && !reminder.RemindAt.Before(rs.Before) {
+		return false
+	}
+	if !rs.After.IsZero() && !reminder.RemindAt.After(rs.After) {
+		return false
+	}
+	return true
+}
+
+// confirmBulkOperation previews the selected reminders, and asks the user to confirm the operation unless the "--yes" flag is provided
+func (rs *ReminderSelector) confirmBulkOperation(question string, reminders []common.Reminder) (bool, error) {
+	if rs.Yes {
+		return true, nil
+	}
+	// otherwise, the script would hang waiting for the answer
+	if !utils.IsTerminal(os.Stdin) {
+		logger.Error("confirmation required, but stdin is not a terminal")
+		return false, common.ErrCmdConfirmationRequired
+	}
+
+	printReminders(reminders, true)
+	fmt.Printf("\n%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		// e.g. Ctrl-D
+		fmt.Println()
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		fmt.Println("Aborted")
+		return false, nil
+	}
+}
+
+// selectedIds is used for logging
+func selectedIds(reminders []common.Reminder) string {
+	ids := make([]string, 0, len(reminders))
+	for _, reminder := range reminders {
+		ids = append(ids, strconv.FormatInt(reminder.ID, 10))
+	}
+	return strings.Join(ids, ",")
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const showTemplate = "%s:\t%s\n"
+
+// showCmd represents the show command
+var showCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the details of the reminder",
+	Long: `Print the details of the reminder.
+
+The command expects a reminder ID to be provided via the "--id" flag - otherwise, the error will be produced.
+The details include the message, the time to be notified at with the time zone and relative to now, the urgency, the state,
+and the time the reminder has been created and last updated at.
+
+The "--output" flag prints the reminder as json, yaml, csv or with the Go template provided via the "--template" flag: e.g. '{{.RemindAt}}'.
+
+List the upcoming reminders with the "list" command.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("show command: called")
+
+		id, err := cmd.Flags().GetInt(common.IdFlag)
+		if err != nil {
+			logger.Error("show command: error while parsing flag: "+common.IdFlag, err)
+			return common.ErrWrongFormattedIntFlag(common.IdFlag)
+		}
+		if id == 0 {
+			logger.Error("show command: mandatory flag not provided: " + common.IdFlag)
+			return common.ErrShowCmdIdNotProvided
+		}
+
+		output, err := resolveOutputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("show command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		reminder, err := httpClient.GetReminder(id)
+		if err != nil {
+			return err
+		}
+
+		if !output.isTable() {
+			return output.print(toReminderOutput(*reminder))
+		}
+		printReminder(*reminder)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().Int(common.IdFlag, 0, "Reminder ID to show")
+
+	showCmd.RegisterFlagCompletionFunc(common.IdFlag, completeReminderIds)
+
+	showCmd.MarkFlagRequired(common.IdFlag)
+}
+
+func printReminder(reminder common.Reminder) {
+	now := time.Now()
+	timeFormat := resolveTimeFormat()
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 5, ' ', 0)
+
+	urgency := reminder.Urgency
+	if urgency == "" {
+		urgency = common.UrgencyNormal
+	}
+
+	fmt.Fprintf(w, showTemplate, "ID", strconv.FormatInt(reminder.ID, 10))
+	fmt.Fprintf(w, showTemplate, "Message", reminder.Message)
+	fmt.Fprintf(w, showTemplate, "Remind at", reminder.RemindAt.Format(timeFormat+" MST (-07:00)"))
+	fmt.Fprintf(w, showTemplate, "Remind in", utils.RelativeTime(reminder.RemindAt, now))
+	fmt.Fprintf(w, showTemplate, "Urgency", urgency)
+	fmt.Fprintf(w, showTemplate, "State", reminderState(reminder, now))
+	fmt.Fprintf(w, showTemplate, "Created", formatTimestamp(reminder.CreatedAt, timeFormat, now))
+	fmt.Fprintf(w, showTemplate, "Updated", formatTimestamp(reminder.UpdatedAt, timeFormat, now))
+	w.Flush()
+}
+
+// reminderState is the state of the reminder as of now:
+// the reminder is kept until the notification is sent, so the one that is due is about to be notified about
+func reminderState(reminder common.Reminder, now time.Time) string {
+	if !reminder.RemindAt.After(now) {
+		return common.ReminderStateDue
+	}
+	return common.ReminderStateScheduled
+}
+
+// formatTimestamp formats the time alongside its relative one, or "-" if it's unknown: e.g. for the reminders created by the previous app versions
+func formatTimestamp(t time.Time, timeFormat string, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(timeFormat) + " (" + utils.RelativeTime(t, now) + ")"
+}
@@ -1,14 +1,23 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/spf13/cobra"
 	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
 	"n0rdy.foo/remindme/httpclient"
 	"n0rdy.foo/remindme/logger"
 	"n0rdy.foo/remindme/utils"
 	"os"
 	"os/exec"
 	"strconv"
+	"time"
+)
+
+const (
+	startReadinessTimeout = 10 * time.Second
+	stopTimeout           = 10 * time.Second
+	lifecyclePollInterval = 100 * time.Millisecond
 )
 
 // startCmd represents the start command
@@ -17,74 +26,201 @@ var startCmd = &cobra.Command{
 	Short: "Start the remindme app",
 	Long: `Start the remindme app.
 
-Under the hood, the command starts an HTTP server at port 15555
+Under the hood, the command starts an HTTP server
 that is responsible for the persistence of the reminders and 
 for sending the notifications once the requested time comes.
 
+On Linux and MacOS, the server listens to the Unix domain socket within the app data directory, 
+which is accessible by the current user only.
+On Windows, or if requested via the "--transport tcp" or "--port" flags, the server listens to the TCP port 15555 on the localhost.
+
+Each profile requested via the "--profile" flag or the REMINDME_PROFILE env var has its own server:
+it listens to the socket within the profile data directory, or to the TCP port derived from the profile name.
+
+The command waits for the app to become ready, and reports the failure if it doesn't within 10 seconds.
+The output of the server process is written to the "remindme_server_output.log" file within the app data directory.
+
 Stop the remindme app with the "stop" command.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Info("start command: called")
 
-		resolvedPort, err := resolveStartPort(cmd)
+		resolvedAddress, err := resolveServerAddress(cmd)
 		if err != nil {
 			return err
 		}
+		return startApp(resolvedAddress)
+	},
+}
 
-		httpClient := httpclient.NewHttpClient(resolvedPort)
-		if httpClient.Healthcheck() {
-			return common.ErrStartCmdAlreadyRunning
-		}
+func init() {
+	rootCmd.AddCommand(startCmd)
 
-		command := exec.Command(resolveExecBinary(), "admin", "server", "start", "--port", strconv.Itoa(resolvedPort))
-		command.Stderr = os.Stderr
+	startCmd.Flags().IntP(common.PortFlag, "p", common.DefaultHttpServerPort, "Port to start the HTTP server at - implies TCP transport")
+	startCmd.Flags().String(common.TransportFlag, "", "Transport to start the HTTP server with: either unix (Unix domain socket, default on Linux and MacOS) or tcp (default on Windows)")
+}
 
-		if err := command.Start(); err != nil {
-			logger.Error("start command: error while starting HTTP server", err)
-			return err
+// startApp starts the server process at the provided address, and waits for it to become ready
+func startApp(address common.ServerAddress) error {
+	if pid, running, _ := config.ReadRunningPid(); running {
+		logger.Error("start command: the server is running already with PID " + strconv.Itoa(pid))
+		return common.ErrStartCmdAlreadyRunning
+	}
+	// the server started by the previous versions of the app doesn't hold the PID file
+	httpClient := httpclient.NewHttpClient(address)
+	if httpClient.Healthcheck() {
+		return common.ErrStartCmdAlreadyRunning
+	}
+
+	outputFile, err := os.Create(getServerOutputFilePath())
+	if err != nil {
+		logger.Error("start command: error while creating server output file", err)
+		return err
+	}
+	defer outputFile.Close()
+
+	command := exec.Command(
+		resolveExecBinary(), "admin", "server", "start",
+		"--"+common.TransportFlag, address.Transport,
+		"--"+common.PortFlag, strconv.Itoa(address.Port),
+	)
+	if profile := utils.Profile(); profile != "" {
+		command.Args = append(command.Args, "--"+common.ProfileFlag, profile)
+	}
+	// the output is written to the file rather than the terminal, as the server outlives the command
+	command.Stdout = outputFile
+	command.Stderr = outputFile
+
+	if err := command.Start(); err != nil {
+		logger.Error("start command: error while starting HTTP server", err)
+		return err
+	}
+
+	exitedCh := make(chan error, 1)
+	go func() {
+		exitedCh <- command.Wait()
+	}()
+
+	err = waitForReadiness(httpClient, exitedCh)
+	if err != nil {
+		printServerOutput()
+		return err
+	}
+
+	fmt.Println("The remindme app is started with PID " + strconv.Itoa(command.Process.Pid))
+	return nil
+}
+
+// waitForReadiness polls the healthcheck till it succeeds, the server process exits or the timeout is reached
+func waitForReadiness(httpClient httpclient.RemindmeHttpClient, exitedCh chan error) error {
+	deadline := time.NewTimer(startReadinessTimeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(lifecyclePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-exitedCh:
+			logger.Error("start command: server process exited before becoming ready", err)
+			return common.ErrStartCmdServerExited
+		case <-deadline.C:
+			logger.Error("start command: server hasn't become ready within " + startReadinessTimeout.String())
+			return common.ErrStartCmdReadinessTimeout
+		case <-ticker.C:
+			if httpClient.Healthcheck() {
+				return nil
+			}
 		}
-		return nil
-	},
+	}
 }
 
-func init() {
-	rootCmd.AddCommand(startCmd)
+func printServerOutput() {
+	output, err := os.ReadFile(getServerOutputFilePath())
+	if err != nil || len(output) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Server output:")
+	fmt.Fprint(os.Stderr, string(output))
+}
 
-	startCmd.Flags().IntP(common.PortFlag, "p", common.DefaultHttpServerPort, "Port to start the HTTP server at")
+func getServerOutputFilePath() string {
+	return utils.GetOsSpecificAppDataDir() + common.ServerOutputFileName
+}
+
+// resolveServerAddress resolves the address of the HTTP server:
+// the port is resolved by resolveStartPort, while the transport is resolved in the following order:
+// 1. From the `--transport` flag
+// 2. From the `REMINDME_SERVER_TRANSPORT` environment variable
+// 3. TCP if the port has been requested explicitly
+// 4. The default OS-specific one: Unix domain socket on Linux and MacOS, TCP otherwise
+func resolveServerAddress(cmd *cobra.Command) (common.ServerAddress, error) {
+	address := common.ServerAddress{SocketPath: utils.GetServerSocketPath()}
+
+	port, portRequested, err := resolveStartPort(cmd)
+	if err != nil {
+		return address, err
+	}
+	address.Port = port
+
+	if cmd.Flags().Changed(common.TransportFlag) {
+		transport, err := cmd.Flags().GetString(common.TransportFlag)
+		if err != nil {
+			logger.Error("error while parsing flag: "+common.TransportFlag, err)
+			return address, common.ErrWrongFormattedStringFlag(common.TransportFlag)
+		}
+		address.Transport = transport
+	} else if transport := os.Getenv(common.ServerTransportEnvVar); transport != "" {
+		address.Transport = transport
+	} else if portRequested {
+		address.Transport = common.TcpTransport
+	} else {
+		address.Transport = utils.DefaultTransport()
+	}
+
+	if address.Transport != common.UnixTransport && address.Transport != common.TcpTransport {
+		logger.Error("invalid transport provided: " + address.Transport)
+		return address, common.ErrCmdInvalidTransport
+	}
+	return address, nil
 }
 
 // resolveStartPort resolves the port to start the HTTP server at in the following order:
 // 1. From the `--port` flag
 // 2. From the `REMINDME_SERVER_PORT` environment variable
-// 3. The default port is 15555
-func resolveStartPort(cmd *cobra.Command) (int, error) {
-	resolvedPort := common.DefaultHttpServerPort
+// 3. The default port of the profile: 15555 for the default profile
+//
+// Reports whether the port has been requested explicitly via either the flag or the environment variable.
+func resolveStartPort(cmd *cobra.Command) (int, bool, error) {
+	resolvedPort := utils.DefaultProfilePort(utils.Profile())
+	requested := false
 
 	// If the port flag is set, use it as the highest priority value
 	if cmd.Flags().Changed(common.PortFlag) {
 		port, err := cmd.Flags().GetInt(common.PortFlag)
 		if err != nil {
-			logger.Error("start command: error while parsing port flag", err)
-			return 0, common.ErrWrongFormattedIntFlag(common.PortFlag)
+			logger.Error("error while parsing port flag", err)
+			return 0, false, common.ErrWrongFormattedIntFlag(common.PortFlag)
 		}
 		resolvedPort = port
+		requested = true
 	} else {
 		// If the port flag is not set, try to get the port from the environment variable
 		portAsString := os.Getenv(common.ServerPortEnvVar)
 		if portAsString != "" {
 			port, err := strconv.Atoi(portAsString)
 			if err != nil {
-				logger.Error("start command: error while parsing environment variable "+common.ServerPortEnvVar+", the value: "+portAsString, err)
-				return 0, common.ErrWrongFormattedIntEnvVar(common.ServerPortEnvVar)
+				logger.Error("error while parsing environment variable "+common.ServerPortEnvVar+", the value: "+portAsString, err)
+				return 0, false, common.ErrWrongFormattedIntEnvVar(common.ServerPortEnvVar)
 			}
 			resolvedPort = port
+			requested = true
 		}
 	}
 
 	if !utils.IsPortValid(resolvedPort) {
-		return 0, common.ErrCmdInvalidPort
+		return 0, false, common.ErrCmdInvalidPort
 	}
 
-	return resolvedPort, nil
+	return resolvedPort, requested, nil
 }
 
 func resolveExecBinary() string {
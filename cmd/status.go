@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const statusTemplate = "%s:\t%s\n"
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print the status of the remindme app",
+	Long: `Print the status of the remindme app.
+
+The status includes the app version, uptime, address, the type of the storage the reminders are persisted in, 
+the number of the scheduled reminders, the next one to be notified about, the result of the last notification, 
+the time of the last cleanup of the expired reminders and the end of the current quiet hours or do-not-disturb period.
+
+The status is "DEGRADED" if the app works, but not as expected: e.g. the reminders are stored in memory due to the SQLite issues,
+or the last notification has failed to be sent.
+
+If the app is not running, the corresponding message is printed, or the "DOWN" status for the other output formats.
+
+The "--output" flag prints the status as json, yaml, csv or with the Go template provided via the "--template" flag: e.g. '{{.Status}}'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("status command: called")
+
+		output, err := resolveOutputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("status command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		status, err := httpClient.GetStatus()
+		if err != nil {
+			if errors.Is(err, common.ErrHttpOnCallingServer) {
+				if !output.isTable() {
+					return output.print(statusOutput{Status: common.HealthStatusDown})
+				}
+				fmt.Println("The remindme app is down: run the \"start\" command to start it")
+				return nil
+			}
+			return err
+		}
+
+		if !output.isTable() {
+			return output.print(toStatusOutput(*status))
+		}
+		printStatus(*status)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+func printStatus(status common.Status) {
+	now := time.Now()
+	timeFormat := resolveTimeFormat()
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 5, ' ', 0)
+
+	fmt.Fprintf(w, statusTemplate, "Status", status.Status)
+	fmt.Fprintf(w, statusTemplate, "Version", status.Server.Version)
+	fmt.Fprintf(w, statusTemplate, "Uptime", utils.HumanizeDuration(time.Duration(status.UptimeSeconds)*time.Second))
+	fmt.Fprintf(w, statusTemplate, "Address", status.Server.Address)
+
+	repo := status.Server.RepoType
+	if status.Server.DbPath != "" {
+		repo += " (" + status.Server.DbPath + ")"
+	}
+	fmt.Fprintf(w, statusTemplate, "Storage", repo)
+	fmt.Fprintf(w, statusTemplate, "Scheduled reminders", strconv.Itoa(status.Scheduler.ActiveTimers))
+
+	next := "-"
+	if reminder := status.Scheduler.NextReminder; reminder != nil {
+		next = fmt.Sprintf("#%d \"%s\" at %s (%s)",
+			reminder.ID, reminder.Message, reminder.RemindAt.Format(timeFormat), utils.RelativeTime(reminder.RemindAt, now),
+		)
+	}
+	fmt.Fprintf(w, statusTemplate, "Next reminder", next)
+
+	lastNotification := "-"
+	if notification := status.Scheduler.LastNotification; notification != nil {
+		result := "sent"
+		if !notification.Success {
+			result = "failed: " + notification.Error
+		}
+		lastNotification = fmt.Sprintf("#%d \"%s\" at %s - %s",
+			notification.ReminderID, notification.Message, notification.SentAt.Format(timeFormat), result,
+		)
+	}
+	fmt.Fprintf(w, statusTemplate, "Last notification", lastNotification)
+
+	lastCleanup := "-"
+	if status.Scheduler.LastCleanupAt != nil {
+		lastCleanup = status.Scheduler.LastCleanupAt.Format(timeFormat)
+	}
+	fmt.Fprintf(w, statusTemplate, "Last cleanup", lastCleanup)
+
+	quiet := "-"
+	if status.Scheduler.QuietUntil != nil {
+		quiet = "till " + status.Scheduler.QuietUntil.Format(timeFormat)
+	}
+	if status.Scheduler.DndUntil != nil {
+		quiet += " (do not disturb till " + status.Scheduler.DndUntil.Format(timeFormat) + ")"
+	}
+	fmt.Fprintf(w, statusTemplate, "Quiet", quiet)
+
+	w.Flush()
+}
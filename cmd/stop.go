@@ -6,33 +6,99 @@ import (
 	"n0rdy.foo/remindme/config"
 	"n0rdy.foo/remindme/httpclient"
 	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
 )
 
 // stopCmd represents the stop command
 var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop the remindme app",
-	Long: `Start the remindme app.
+	Long: `Stop the remindme app.
 
-Under the hood, the command requests the HTTP server at port 15555 to stop.
-Please, note that all the requested reminders will be permanently deleted,
-and the notifications won't be sent.
+Under the hood, the command requests the HTTP server to stop, and waits for its process to exit.
+The server finishes sending the notifications in progress, and persists its state: the notifications history,
+the do-not-disturb mode, the operations to undo and the pomodoro session.
+If the server doesn't respond, its process is terminated using the PID from the "remindme.pid" file within the app data directory.
 
-Start the remindme app with the "start" command.`,
+The reminders stored in the SQLite DB are kept, and scheduled again on the next start:
+the ones that have come due while the app was stopped, or haven't been notified about before it stopped, are notified about right away.
+If the app has fallen back to the in-memory storage, the reminders are lost.
+
+Start the remindme app with the "start" command, or use the "restart" command to do both.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Info("stop command: called")
 
-		port, err := config.ResolveRunningServerPort()
-		if err != nil {
-			logger.Error("at command: error while resolving running server port", err)
-			return common.ErrCmdCannotResolveServerPort
-		}
-
-		httpClient := httpclient.NewHttpClient(port)
-		return httpClient.StopServer()
+		return stopApp()
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(stopCmd)
 }
+
+// stopApp requests the server to stop, and waits for its process to exit.
+// If the request fails, the process is terminated via the PID from the PID file, if any.
+func stopApp() error {
+	address, err := config.ResolveRunningServerAddress()
+	if err != nil {
+		logger.Error("stop command: error while resolving running server address", err)
+		return common.ErrCmdCannotResolveServerAddress
+	}
+
+	pid, running, err := config.ReadRunningPid()
+	if err != nil {
+		logger.Error("stop command: error while reading PID file", err)
+	}
+
+	httpClient := httpclient.NewHttpClient(address)
+	err = httpClient.StopServer()
+	if err != nil {
+		// e.g. the app is down, or has been started by the previous version that doesn't hold the PID file
+		if !running {
+			return err
+		}
+
+		logger.Error("stop command: HTTP shutdown failed - terminating the server process with PID "+strconv.Itoa(pid), err)
+		err = terminateProcess(pid)
+		if err != nil {
+			logger.Error("stop command: error while terminating the server process", err)
+			return common.ErrStopCmdCannotTerminateProcess
+		}
+	}
+
+	if !running {
+		return nil
+	}
+	return waitForExit()
+}
+
+// waitForExit polls the PID file till it's released by the server process, or the timeout is reached
+func waitForExit() error {
+	deadline := time.Now().Add(stopTimeout)
+	for time.Now().Before(deadline) {
+		_, running, err := config.ReadRunningPid()
+		if err == nil && !running {
+			return nil
+		}
+		time.Sleep(lifecyclePollInterval)
+	}
+
+	logger.Error("stop command: server process hasn't exited within " + stopTimeout.String())
+	return common.ErrStopCmdTimeout
+}
+
+func terminateProcess(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	// Windows doesn't support sending signals other than kill
+	if utils.DetectOsType() == common.WindowsOS {
+		return process.Kill()
+	}
+	return process.Signal(syscall.SIGTERM)
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// templateCmd represents the template command
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Reminder templates commands: add, list and remove templates",
+	Long: `Reminder templates commands: add, list and remove templates.
+
+The template is the named preset of the frequent reminder: its message, and optionally the time and urgency.
+Run "use <template>" to create the reminder from it.
+
+The list of available subcommands:
+- template add 		- add the template
+- template list 	- print all the templates
+- template rm 		- remove the template
+
+The templates are stored in the "remindme_configs.yaml" file in the app data directory, under the "templates" key,
+so they can be edited manually too: run "config validate" to check them afterwards.`,
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+}
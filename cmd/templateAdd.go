@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/logger"
+	"os"
+)
+
+// templateAddCmd represents the template add command
+var templateAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add the reminder template",
+	Long: `Add the reminder template to be created with the "use" command.
+
+The name should consist of up to 64 latin letters, digits, "-" and "_".
+The command expects a reminder message to be provided via the "--about" flag - otherwise, the error will be produced.
+
+The time is optional: either the duration from now via the "--in" flag (e.g. "20m", "1h30m"),
+or the time in 24-hours HH:MM format via the "--time" flag (e.g. "16:30"). If none is set, it's provided to the "use" command.
+The "--urgency" flag is the same as the one of the "in" and "at" commands: low, normal (default) or high.
+
+The existing template is not replaced: remove it with the "template rm" command first.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("template add command: called")
+
+		name := args[0]
+		template, err := parseTemplateAddCmd(cmd)
+		if err != nil {
+			return err
+		}
+
+		err = config.AddTemplate(name, *template)
+		if err != nil {
+			logger.Error("template add command: error while adding template: "+name, err)
+			var pathErr *os.PathError
+			if errors.As(err, &pathErr) {
+				return common.ErrTemplateAddCmdCannotPersistTemplate
+			}
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateAddCmd)
+
+	templateAddCmd.Flags().StringP(common.AboutFlag, "a", "", "Reminder message")
+	templateAddCmd.Flags().String(common.InFlag, "", "Duration from now to be notified in: e.g. 20m, 1h30m")
+	templateAddCmd.Flags().String(common.TimeFlag, "", "Time to be notified at in 24-hours HH:MM format: e.g. 16:30")
+	templateAddCmd.Flags().String(common.UrgencyFlag, "", "Reminder urgency, which defines how it's notified within the quiet hours: low (dropped), normal (deferred till their end) or high (notified silently)")
+
+	templateAddCmd.MarkFlagRequired(common.AboutFlag)
+	templateAddCmd.RegisterFlagCompletionFunc(common.InFlag, completeDurations)
+	templateAddCmd.RegisterFlagCompletionFunc(common.TimeFlag, completeTimes)
+}
+
+func parseTemplateAddCmd(cmd *cobra.Command) (*common.ReminderTemplate, error) {
+	flags := cmd.Flags()
+
+	message, err := flags.GetString(common.AboutFlag)
+	if err != nil {
+		logger.Error("template add command: error while parsing flag: "+common.AboutFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.AboutFlag)
+	}
+	in, err := flags.GetString(common.InFlag)
+	if err != nil {
+		logger.Error("template add command: error while parsing flag: "+common.InFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.InFlag)
+	}
+	at, err := flags.GetString(common.TimeFlag)
+	if err != nil {
+		logger.Error("template add command: error while parsing flag: "+common.TimeFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.TimeFlag)
+	}
+	urgency, err := flags.GetString(common.UrgencyFlag)
+	if err != nil {
+		logger.Error("template add command: error while parsing flag: "+common.UrgencyFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.UrgencyFlag)
+	}
+
+	if in != "" && at != "" {
+		logger.Error("template add command: both flags provided: " + common.InFlag + " and " + common.TimeFlag)
+		return nil, common.ErrTemplateAddCmdInvalidTimeFlagsProvided
+	}
+
+	// the rest is validated alongside the templates edited manually
+	return &common.ReminderTemplate{
+		Message: message,
+		In:      in,
+		At:      at,
+		Urgency: urgency,
+	}, nil
+}
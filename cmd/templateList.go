@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/logger"
+	"os"
+	"text/tabwriter"
+)
+
+const (
+	templatesTitle    = "Name\tMessage\tTime\tUrgency"
+	templatesTemplate = "%s\t%s\t%s\t%s\n"
+)
+
+// templateListCmd represents the template list command
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print all the reminder templates",
+	Long: `Print all the reminder templates sorted by name.
+
+The time is printed as "in <duration>" or "at <HH:MM>", or "-" if it's provided to the "use" command.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("template list command: called")
+
+		templates, err := config.ListTemplates()
+		if err != nil {
+			logger.Error("template list command: error while reading templates", err)
+			return err
+		}
+
+		if len(templates) == 0 {
+			fmt.Println("No templates: add one with `template add` command")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 1, 1, 3, ' ', 0)
+		fmt.Fprintln(w, templatesTitle)
+		for _, template := range templates {
+			fmt.Fprintf(w, templatesTemplate, template.Name, template.Message, describeTemplateTime(template.ReminderTemplate), templateUrgency(template.ReminderTemplate))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateListCmd)
+}
+
+func describeTemplateTime(template common.ReminderTemplate) string {
+	if template.In != "" {
+		return "in " + template.In
+	}
+	if template.At != "" {
+		return "at " + template.At
+	}
+	return "-"
+}
+
+func templateUrgency(template common.ReminderTemplate) string {
+	if template.Urgency == "" {
+		return common.UrgencyNormal
+	}
+	return template.Urgency
+}
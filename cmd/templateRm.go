@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/logger"
+	"os"
+)
+
+// templateRmCmd represents the template rm command
+var templateRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove the reminder template",
+	Long: `Remove the reminder template.
+
+The reminders already created from it are kept as is.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("template rm command: called")
+
+		name := args[0]
+		err := config.RemoveTemplate(name)
+		if err != nil {
+			logger.Error("template rm command: error while removing template: "+name, err)
+			var pathErr *os.PathError
+			if errors.As(err, &pathErr) {
+				return common.ErrTemplateRmCmdCannotPersistTemplates
+			}
+			return err
+		}
+		return nil
+	},
+	ValidArgsFunction: completeTemplateNames,
+}
+
+func init() {
+	templateCmd.AddCommand(templateRmCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/logger"
+	"time"
+)
+
+// timerCmd represents the timer command
+var timerCmd = &cobra.Command{
+	Use:   "timer <duration>",
+	Short: "Run the countdown in the terminal, and get notified once it's over",
+	Long: `Run the countdown in the terminal, and get notified once it's over.
+
+The command expects the duration as an argument: e.g. 30s, 25m, 1h30m.
+The notification message can be provided via the "--about" flag - otherwise, the default one is used.
+
+Unlike the "in" command, the countdown runs within the command itself, so the remindme app doesn't need to be started:
+e.g. for a one-off timer in the terminal or the CI job.
+The command exits with the following codes: 0 once the notification is sent, 1 if it's dropped due to the quiet hours, 130 if interrupted with Ctrl-C.`,
+	Example: `remindme timer 25m
+remindme timer 1h30m --about "Stretch"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("timer command: called")
+
+		reminder, err := parseTimerCmd(cmd, args)
+		if err != nil {
+			return err
+		}
+		return runInForeground(cmd, *reminder)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(timerCmd)
+
+	timerCmd.Flags().StringP(common.AboutFlag, "a", "", "Notification message - if not provided, the default one is used")
+	timerCmd.Flags().String(common.UrgencyFlag, common.UrgencyNormal, "Timer urgency, which defines how it's notified within the quiet hours: low (dropped), normal (deferred till their end) or high (notified silently)")
+}
+
+func parseTimerCmd(cmd *cobra.Command, args []string) (*common.Reminder, error) {
+	flags := cmd.Flags()
+
+	duration, err := time.ParseDuration(args[0])
+	if err != nil || duration <= 0 {
+		logger.Error("timer command: invalid duration provided: " + args[0])
+		return nil, common.ErrTimerCmdInvalidDuration
+	}
+
+	message, err := flags.GetString(common.AboutFlag)
+	if err != nil {
+		logger.Error("timer command: error while parsing flag: "+common.AboutFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.AboutFlag)
+	}
+	if message == "" {
+		message = "The " + args[0] + " timer is over"
+	}
+
+	urgency, err := parseUrgencyFlag(flags, "timer")
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.Reminder{
+		Message:  message,
+		RemindAt: time.Now().Add(duration),
+		Urgency:  urgency,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/tui"
+	"n0rdy.foo/remindme/utils"
+)
+
+// uiCmd represents the ui command
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Manage the reminders in the interactive terminal UI",
+	Long: `Manage the reminders in the interactive terminal UI.
+
+The command opens the full-screen list of the upcoming reminders with the live countdowns, which is refreshed every 2 seconds.
+The reminders are managed with the following keys:
+- ↑/↓ or j/k to move, PgUp/PgDn, Home/End or g/G to scroll
+- n to set up the new reminder: either in the provided duration (e.g. 10m) or at the provided time (e.g. 16:30)
+- e or Enter to change the message and/or the time of the selected reminder
+- p to postpone the selected reminder: by the "notifications.defaultSnooze" config, if the duration is not provided
+- c or Delete to cancel the selected reminder
+- s to snooze the last notification: the reminder with the same message is set up for the "notifications.defaultSnooze" config
+- / to filter the reminders by the message or ID, Esc to clear the filter
+- r to refresh, q or Ctrl-C to quit
+
+If the app is down, the error is shown instead of the list until the app is started again.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("ui command: called")
+
+		// the invalid configs have been reported on the command start already
+		settings, _ := config.ResolveSettings()
+		return tui.Run(tui.Options{
+			Profile:       utils.Profile(),
+			TimeFormat:    settings.TimeFormat,
+			DefaultSnooze: settings.DefaultSnooze,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/spf13/cobra"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	journalTitle    = "ID\tOperation\tAt"
+	journalTemplate = "%d\t%s\t%s\n"
+)
+
+type UndoFlags struct {
+	Id     int64
+	IsList bool
+}
+
+// undoCmd represents the undo command
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the latest operation on the reminders",
+	Long: `Undo the latest operation on the reminders: e.g. the cancelled reminders are restored, the changed ones get their previous message and time back,
+and the created ones are cancelled. The restored reminders are scheduled again: the ones that have come due in the meantime are notified about right away.
+
+The app keeps the 20 most recent operations that have set, changed or cancelled the reminders, including the ones made via the interactive UI,
+and restores them on restart if it has been stopped properly and the reminders are stored in the SQLite DB.
+
+The "--list" flag prints the operations that can be undone, the latest first, and asks which one to undo if run in the terminal.
+The older operation can also be undone with the "--id" flag: the operations made after it are kept as is.
+It is rejected if any of the operations made after it has set, changed or cancelled the same reminders, as those should be undone first.
+
+The "--output" flag prints the list as json, yaml, csv or with the Go template provided via the "--template" flag,
+e.g. '{{.ID}}: {{.Description}}'. The fields are: ID, Operation, At, Description, Created and Before.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("undo command: called")
+
+		flags, err := parseUndoFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("undo command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+		httpClient := httpclient.NewHttpClient(address)
+
+		if flags.IsList {
+			return listJournal(cmd, httpClient)
+		}
+		return undo(httpClient, flags.Id)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().Int64(common.IdFlag, 0, "Operation ID to undo instead of the latest one: see the \"--list\" flag")
+	undoCmd.Flags().Bool(common.ListFlag, false, "Print the operations that can be undone, and choose one of them")
+}
+
+func parseUndoFlags(cmd *cobra.Command) (*UndoFlags, error) {
+	flags := cmd.Flags()
+
+	id, err := flags.GetInt64(common.IdFlag)
+	if err != nil {
+		logger.Error("undo command: error while parsing flag: "+common.IdFlag, err)
+		return nil, common.ErrWrongFormattedIntFlag(common.IdFlag)
+	}
+	if id < 0 {
+		logger.Error("undo command: invalid ID provided: " + strconv.FormatInt(id, 10))
+		return nil, common.ErrUndoCmdInvalidId
+	}
+
+	isList := flags.Lookup(common.ListFlag).Changed
+	if isList && id != 0 {
+		logger.Error("undo command: both flags provided: " + common.IdFlag + " and " + common.ListFlag)
+		return nil, common.ErrUndoCmdInvalidFlagsProvided
+	}
+	return &UndoFlags{Id: id, IsList: isList}, nil
+}
+
+func undo(httpClient httpclient.RemindmeHttpClient, entryId int64) error {
+	entry, err := httpClient.Undo(entryId)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("undo command: journal entry " + strconv.FormatInt(entry.ID, 10) + " undone")
+	fmt.Println("Undone: " + describeJournalEntry(*entry))
+	return nil
+}
+
+// listJournal asks which operation to undo only if the journal is printed as the table to the terminal, so that the scripts don't hang
+func listJournal(cmd *cobra.Command, httpClient httpclient.RemindmeHttpClient) error {
+	output, err := resolveOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	journal, err := httpClient.GetJournal()
+	if err != nil {
+		return err
+	}
+
+	if !output.isTable() {
+		return output.print(toJournalOutput(journal))
+	}
+	if len(journal) == 0 {
+		fmt.Println("Nothing to undo")
+		return nil
+	}
+	printJournal(journal)
+	if !utils.IsTerminal(os.Stdin) {
+		return nil
+	}
+
+	fmt.Print("\nOperation ID to undo (empty to keep everything as is): ")
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if err != nil || answer == "" {
+		if err != nil {
+			// e.g. Ctrl-D
+			fmt.Println()
+		}
+		return nil
+	}
+
+	entryId, err := strconv.ParseInt(answer, 10, 64)
+	if err != nil || entryId <= 0 {
+		logger.Error("undo command: invalid ID provided: " + answer)
+		return common.ErrUndoCmdInvalidId
+	}
+	return undo(httpClient, entryId)
+}
+
+func printJournal(journal []common.JournalEntry) {
+	timeFormat := resolveTimeFormat()
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 5, ' ', 0)
+	fmt.Fprintln(w, journalTitle)
+
+	for _, entry := range journal {
+		fmt.Fprintf(w, journalTemplate, entry.ID, describeJournalEntry(entry), entry.At.Format(timeFormat))
+	}
+	w.Flush()
+}
+
+// describeJournalEntry names the single reminder, and counts the several ones
+func describeJournalEntry(entry common.JournalEntry) string {
+	switch entry.Operation {
+	case common.JournalOperationCreate:
+		return "set " + describeReminders(entry.Created)
+	case common.JournalOperationChange:
+		return "changed " + describeReminders(entry.Before)
+	case common.JournalOperationCancel:
+		return "cancelled " + describeReminders(entry.Before)
+	case common.JournalOperationCancelAll:
+		return "cancelled all " + describeReminders(entry.Before)
+	default:
+		return fmt.Sprintf("edited reminders: changed or cancelled %d, added %d", len(entry.Before), len(entry.Created))
+	}
+}
+
+func describeReminders(reminders []common.Reminder) string {
+	if len(reminders) == 1 {
+		return fmt.Sprintf("reminder #%d %q", reminders[0].ID, reminders[0].Message)
+	}
+	return strconv.Itoa(len(reminders)) + " reminders"
+}
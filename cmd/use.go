@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"time"
+)
+
+// useCmd represents the use command
+var useCmd = &cobra.Command{
+	Use:   "use <template>",
+	Short: "Create a reminder from the template",
+	Long: `Create a reminder from the template added with the "template add" command.
+
+The template values can be overridden with the flags: the message via the "--about" flag, the urgency via the "--urgency" flag,
+and the time either via the "--in" flag with the duration from now (e.g. "20m", "1h30m"), or via the "--time" flag with the time in 24-hours HH:MM format (e.g. "16:30").
+If the template has no time set, one of the time flags should be provided - otherwise, the error will be produced.
+
+With the "--foreground" flag, the reminder is scheduled within the command itself rather than the running app, the same way as with the "in" and "at" commands.
+
+List the available templates with the "template list" command.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Info("use command: called")
+
+		name := args[0]
+		template, err := config.GetTemplate(name)
+		if err != nil {
+			logger.Error("use command: error while reading template: "+name, err)
+			return err
+		}
+
+		reminder, err := reminderFromTemplate(cmd.Flags(), *template)
+		if err != nil {
+			return err
+		}
+
+		foreground, err := cmd.Flags().GetBool(common.ForegroundFlag)
+		if err != nil {
+			logger.Error("use command: error while parsing flag: "+common.ForegroundFlag, err)
+			return err
+		}
+		if foreground {
+			return runInForeground(cmd, *reminder)
+		}
+
+		address, err := config.ResolveRunningServerAddress()
+		if err != nil {
+			logger.Error("use command: error while resolving running server address", err)
+			return common.ErrCmdCannotResolveServerAddress
+		}
+
+		httpClient := httpclient.NewHttpClient(address)
+		return httpClient.CreateReminder(*reminder)
+	},
+	ValidArgsFunction: completeTemplateNames,
+}
+
+func init() {
+	rootCmd.AddCommand(useCmd)
+
+	useCmd.Flags().StringP(common.AboutFlag, "a", "", "Reminder message to use instead of the template one")
+	useCmd.Flags().String(common.InFlag, "", "Duration from now to be notified in instead of the template time: e.g. 20m, 1h30m")
+	useCmd.Flags().String(common.TimeFlag, "", "Time to be notified at in 24-hours HH:MM format instead of the template time: e.g. 16:30")
+	useCmd.Flags().String(common.UrgencyFlag, "", "Reminder urgency to use instead of the template one: low, normal or high")
+
+	useCmd.Flags().Bool(common.ForegroundFlag, false, "Wait for the reminder in the terminal with the countdown rather than scheduling it in the running app")
+
+	useCmd.RegisterFlagCompletionFunc(common.InFlag, completeDurations)
+	useCmd.RegisterFlagCompletionFunc(common.TimeFlag, completeTimes)
+}
+
+// reminderFromTemplate applies the flags on top of the template, so that the flags take precedence
+func reminderFromTemplate(flags *pflag.FlagSet, template common.ReminderTemplate) (*common.Reminder, error) {
+	message, err := flags.GetString(common.AboutFlag)
+	if err != nil {
+		logger.Error("use command: error while parsing flag: "+common.AboutFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.AboutFlag)
+	}
+	in, err := flags.GetString(common.InFlag)
+	if err != nil {
+		logger.Error("use command: error while parsing flag: "+common.InFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.InFlag)
+	}
+	at, err := flags.GetString(common.TimeFlag)
+	if err != nil {
+		logger.Error("use command: error while parsing flag: "+common.TimeFlag, err)
+		return nil, common.ErrWrongFormattedStringFlag(common.TimeFlag)
+	}
+
+	if in != "" && at != "" {
+		logger.Error("use command: both flags provided: " + common.InFlag + " and " + common.TimeFlag)
+		return nil, common.ErrUseCmdInvalidTimeFlagsProvided
+	}
+	if in == "" && at == "" {
+		in, at = template.In, template.At
+	}
+	if message == "" {
+		message = template.Message
+	}
+
+	var remindAt time.Time
+	switch {
+	case in != "":
+		duration, err := time.ParseDuration(in)
+		if err != nil || duration <= 0 {
+			logger.Error("use command: invalid duration provided: " + in)
+			return nil, common.ErrUseCmdInvalidDuration
+		}
+		remindAt = time.Now().Add(duration)
+	case at != "":
+		remindAt, err = utils.TimeFrom24HoursString(at)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		logger.Error("use command: no time provided")
+		return nil, common.ErrUseCmdTimeNotProvided
+	}
+
+	urgency := template.Urgency
+	if flags.Lookup(common.UrgencyFlag).Changed {
+		urgency, err = parseUrgencyFlag(flags, "use")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if urgency == "" {
+		urgency = common.UrgencyNormal
+	}
+
+	return &common.Reminder{
+		Message:  message,
+		RemindAt: remindAt,
+		Urgency:  urgency,
+	}, nil
+}
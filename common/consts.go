@@ -1,45 +1,187 @@
 package common
 
 const (
+	AppVersion = "1.2.0"
+
 	// flags:
 	AboutFlag      = "about"
+	AfterFlag      = "after"
 	AllFlag        = "all"
 	AmFlag         = "am"
 	AscendingFlag  = "asc"
+	BeforeFlag     = "before"
+	BreakFlag      = "break"
 	ClientFlag     = "client"
+	CompactFlag    = "compact"
+	CyclesFlag     = "cycles"
 	DescendingFlag = "desc"
 	DirFlag        = "dir"
+	FilesOnlyFlag  = "files-only"
+	FollowFlag     = "follow"
+	ForFlag        = "for"
+	ForegroundFlag = "foreground"
+	GrepFlag       = "grep"
 	HoursFlag      = "hr"
 	IdFlag         = "id"
+	InFlag         = "in"
+	LevelFlag      = "level"
+	LinesFlag      = "lines"
+	ListFlag       = "list"
+	MatchingFlag   = "matching"
 	MessageFlag    = "message"
 	MinutesFlag    = "min"
+	OutputFlag     = "output"
 	PmFlag         = "pm"
 	PortFlag       = "port"
 	PostponeFlag   = "postpone"
+	RelativeFlag   = "relative"
+	ProfileFlag    = "profile"
+	RequestFlag    = "request"
 	SecondsFlag    = "sec"
 	ServerFlag     = "server"
+	SinceFlag      = "since"
+	SocketFlag     = "socket"
 	SortFlag       = "sort"
+	TemplateFlag   = "template"
 	TimeFlag       = "time"
+	TransportFlag  = "transport"
+	UntilFlag      = "until"
+	UrgencyFlag    = "urgency"
+	WorkFlag       = "work"
+	YesFlag        = "yes"
+
+	// output formats:
+	TableOutput    = "table"
+	JsonOutput     = "json"
+	YamlOutput     = "yaml"
+	CsvOutput      = "csv"
+	TemplateOutput = "template"
+
+	// reminder states:
+	ReminderStateScheduled = "scheduled"
+	ReminderStateDue       = "due"
+
+	// batch operations:
+	BatchOperationCreate        = "create"
+	BatchOperationUpdate        = "update"
+	BatchOperationDelete        = "delete"
+	BatchOperationStatusApplied = "applied"
+	BatchOperationStatusFailed  = "failed"
+	BatchOperationStatusSkipped = "skipped"
+
+	// journal operations:
+	JournalOperationCreate    = "create"
+	JournalOperationChange    = "change"
+	JournalOperationCancel    = "cancel"
+	JournalOperationCancelAll = "cancel_all"
+	// the batch of the different operations, e.g. applied by the "edit" command
+	JournalOperationBatch = "batch"
+
+	// pomodoro intervals:
+	PomodoroIntervalWork  = "work"
+	PomodoroIntervalBreak = "break"
+	// the longer chains are rejected, as the session is supposed to fit into a day
+	PomodoroMaxCycles = 24
 
 	// time format:
+	DateFormat                    = "2006-01-02"
 	DateTimeFormatWithoutTimeZone = "2006-01-02 15:04:05"
 	TimeFormat12AmPmHours         = "03:04 PM"
 	TimeFormat24Hours             = "15:04:05"
+	DateTimeFormat12AmPmHours     = "2006-01-02 03:04:05 PM"
+
+	// time display formats:
+	TimeDisplayFormat12Hours = "12h"
+	TimeDisplayFormat24Hours = "24h"
+
+	// notification backends:
+	AlertNotificationBackend   = "alert"
+	BeepNotificationBackend    = "beep"
+	DesktopNotificationBackend = "desktop"
+
+	// urgency levels:
+	UrgencyLow    = "low"
+	UrgencyNormal = "normal"
+	UrgencyHigh   = "high"
+
+	// quiet hours window that disables the quiet hours for the weekday:
+	QuietHoursOff = "off"
 
 	// OS:
 	WindowsOS = "windows"
 	LinuxOS   = "linux"
 	MacOS     = "darwin"
 
+	// transport:
+	TcpTransport  = "tcp"
+	UnixTransport = "unix"
+
+	// repo types:
+	InMemoryRepoType = "inmemory"
+	SqliteRepoType   = "sqlite"
+
+	// health statuses:
+	HealthStatusOk       = "OK"
+	HealthStatusDegraded = "DEGRADED"
+	// reported by the client only, as the server is not reachable
+	HealthStatusDown = "DOWN"
+
+	// HTTP:
+	ApiTokenHeader       = "Authorization"
+	ApiTokenHeaderPrefix = "Bearer "
+	RequestIdHeader      = "X-Request-Id"
+
 	// Shell:
 	BashShell = "bash"
 	ZshShell  = "zsh"
 	FishShell = "fish"
 
+	// Editor:
+	EditorEnvVar         = "EDITOR"
+	VisualEnvVar         = "VISUAL"
+	DefaultEditor        = "vi"
+	DefaultWindowsEditor = "notepad"
+
 	// configs:
-	AdminConfigsFileName  = "remindme_admin_configs.yaml"
-	ClientLogsFileName    = "remindme_client_logs.log"
-	DefaultHttpServerPort = 15555
-	ServerPortEnvVar      = "REMINDME_SERVER_PORT"
-	ServerLogsFileName    = "remindme_server_logs.log"
+	AdminConfigsFileName   = "remindme_admin_configs.yaml"
+	ApiTokenFileName       = "remindme_api_token"
+	ClientLogsFileName     = "remindme_client_logs.log"
+	DbFileName             = "remindme.db"
+	DefaultHttpServerPort  = 15555
+	DefaultProfile         = "default"
+	NextReminderFileName   = "remindme_next.json"
+	ProfileEnvVar          = "REMINDME_PROFILE"
+	ServerPortEnvVar       = "REMINDME_SERVER_PORT"
+	PidFileName            = "remindme.pid"
+	SchedulerStateFileName = "remindme_scheduler_state.json"
+	ServerLogsFileName     = "remindme_server_logs.log"
+	ServerOutputFileName   = "remindme_server_output.log"
+	ServerSocketFileName   = "remindme.sock"
+	ServerTransportEnvVar  = "REMINDME_SERVER_TRANSPORT"
+	UserConfigsFileName    = "remindme_configs.yaml"
+
+	// logs configs:
+	LogsCompressEnvVar   = "REMINDME_LOGS_COMPRESS"
+	LogsFormatEnvVar     = "REMINDME_LOGS_FORMAT"
+	LogsLevelEnvVar      = "REMINDME_LOGS_LEVEL"
+	LogsMaxAgeEnvVar     = "REMINDME_LOGS_MAX_AGE_DAYS"
+	LogsMaxBackupsEnvVar = "REMINDME_LOGS_MAX_BACKUPS"
+	LogsMaxSizeEnvVar    = "REMINDME_LOGS_MAX_SIZE_MB"
+
+	// user configs:
+	CleanupIntervalEnvVar            = "REMINDME_CLEANUP_INTERVAL"
+	NotificationsBackendsEnvVar      = "REMINDME_NOTIFICATIONS_BACKENDS"
+	NotificationsDefaultSnoozeEnvVar = "REMINDME_NOTIFICATIONS_DEFAULT_SNOOZE"
+	QuietHoursFridayEnvVar           = "REMINDME_QUIET_HOURS_FRIDAY"
+	QuietHoursFromEnvVar             = "REMINDME_QUIET_HOURS_FROM"
+	QuietHoursHolidaysEnvVar         = "REMINDME_QUIET_HOURS_HOLIDAYS"
+	QuietHoursMondayEnvVar           = "REMINDME_QUIET_HOURS_MONDAY"
+	QuietHoursSaturdayEnvVar         = "REMINDME_QUIET_HOURS_SATURDAY"
+	QuietHoursSundayEnvVar           = "REMINDME_QUIET_HOURS_SUNDAY"
+	QuietHoursThursdayEnvVar         = "REMINDME_QUIET_HOURS_THURSDAY"
+	QuietHoursToEnvVar               = "REMINDME_QUIET_HOURS_TO"
+	QuietHoursTuesdayEnvVar          = "REMINDME_QUIET_HOURS_TUESDAY"
+	QuietHoursWednesdayEnvVar        = "REMINDME_QUIET_HOURS_WEDNESDAY"
+	RepoBackendEnvVar                = "REMINDME_REPO_BACKEND"
+	TimeFormatEnvVar                 = "REMINDME_TIME_FORMAT"
 )
@@ -3,71 +3,208 @@ package common
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 const (
 	errWrongFormattedStringFlagTemplate   = "wrong formatted flag [%s] - expected to be of type string"
 	errWrongFormattedIntFlagTemplate      = "wrong formatted flag [%s] - expected to be of type int32"
 	errWrongFormattedIntEnvVarTemplate    = "wrong formatted env var [%s] - expected to be of type int"
+	errInvalidConfigTemplate              = "invalid config [%s] provided via %s: %s"
+	errUnknownConfigTemplate              = "unknown config [%s]: run `config list` command to see the available ones"
 	errCompletionUnsupportedShellTemplate = "can't set up completion: unsupported shell type [%s]"
 	errCompletionUnsupportedOsTemplate    = "can't set up completion: unsupported OS type [%s]"
+	errPromptUnsupportedShellTemplate     = "can't generate the prompt snippet: unsupported shell type [%s], the supported ones are: bash, zsh or fish"
+	errTemplateNotFoundTemplate           = "template [%s] not found: run `template list` command to see the available ones"
+	errTemplateAlreadyExistsTemplate      = "template [%s] already exists: remove it with `template rm` command first"
+	errInvalidTemplateTemplate            = "invalid template [%s]: %s"
+	errBatchOperationTemplate             = "batch operation #%d can't be applied: %s"
+	errRemindersNotFoundTemplate          = "reminders not found with the provided IDs: %s"
+	errWrongFormattedFilterTimeTemplate   = "wrong formatted flag [%s] - expected either a duration from now (e.g. `2h`), or the time in one of the formats: `2006-01-02T15:04:05Z07:00`, `2006-01-02 15:04:05`, `2006-01-02` or `15:04`"
 )
 
 var (
 	// cmd errors:
+	ErrAdminAutostartInstallCmdCannotEnable           = errors.New("the autostart files have been written, but can't be enabled: see the output above")
+	ErrAdminAutostartInstallCmdCannotInstall          = errors.New("can't write the autostart files")
+	ErrAdminAutostartUninstallCmdCannotUninstall      = errors.New("can't remove the autostart files")
 	ErrAdminLogsCmdBothFlagsProvided                  = errors.New("either --server or --client flag should be provided, not both")
 	ErrAdminLogsCmdCannotOpenLogsFile                 = errors.New("can't open logs file")
 	ErrAdminLogsCmdCannotDeleteLogsFile               = errors.New("can't delete logs file")
+	ErrAdminLogsPrintCmdFollowWithRequest             = errors.New("either --follow or --request flag should be provided, not both")
+	ErrAdminLogsPrintCmdFollowWithUntil               = errors.New("either --follow or --until flag should be provided, not both")
+	ErrAdminLogsPrintCmdInvalidGrepPattern            = errors.New("--grep flag should be a valid regular expression")
+	ErrAdminLogsPrintCmdInvalidLevel                  = errors.New("--level flag should be one of: debug, info, warn or error")
+	ErrAdminLogsPrintCmdInvalidLines                  = errors.New("--lines flag should be a positive integer")
+	ErrAdminProfilesListCmdCannotListProfiles         = errors.New("can't list profiles")
 	ErrAdminServerStartCmdCannotPersistConfigs        = errors.New("can't persist admin configs")
 	ErrAdminServerStartCmdCannotDeleteConfigs         = errors.New("can't delete previous admin configs")
+	ErrAdminServerStartCmdCannotGenerateApiToken      = errors.New("can't generate API token")
 	ErrAdminServerStopCmdCannotStopServer             = errors.New("error on trying to stop the server as an admin")
 	ErrAtCmdTimeNotProvided                           = errors.New("time should be provided for `at` command: use either `--time` flag with corresponding text time in 24-hours HH:MM format (e.g. `16:30`, `07:45`, `00:00`), or --am/--pm flags with corresponding text time in A.M./P.M. 12-hours HH:MM format")
 	ErrAtCmdInvalidTimeFlagsProvided                  = errors.New("time should be provided for `at` command: use either `--time`, --am or --pm flag, not both")
-	ErrCancelCmdInvalidFlagsProvided                  = errors.New("either reminder ID or `--all` flag should be provided for `cancel` command: use `--id` flag with corresponding text ID or `--all` flag with no value")
-	ErrChangeCmdIdNotProvided                         = errors.New("reminder ID should be provided for `change` command: use `--id` flag with corresponding text ID")
+	ErrCancelCmdInvalidFlagsProvided                  = errors.New("either reminder IDs, filters or `--all` flag should be provided for `cancel` command: use `--id` flag with corresponding IDs (e.g. `3`, `1,2,5` or `5-8`), `--matching`, `--before` or/and `--after` flags, or `--all` flag with no value")
+	ErrChangeCmdBulkWithoutPostpone                   = errors.New("several reminders can only be postponed with `change` command: use `--postpone` flag without `--about` and `--time` flags")
+	ErrChangeCmdIdNotProvided                         = errors.New("reminder IDs or filters should be provided for `change` command: use `--id` flag with corresponding IDs (e.g. `3`, `1,2,5` or `5-8`), or `--matching`, `--before` or/and `--after` flags")
 	ErrChangeCmdInvalidFlagsProvided                  = errors.New("neither `--about`, `--time` nor `--postpone` flags provided for `change` command")
 	ErrChangeCmdInvalidPostponeDuration               = errors.New("duration provided for `change` command alongside the `--postpone` flag via `--hr`, `--min` or/and `--sec` flags should be either 0 or a positive integer value`")
 	ErrChangeCmdInvalidTimeFlagsProvided              = errors.New("either `--time` or `--postpone` flags should be provided for `change` command, not both")
 	ErrChangeCmdPostponeDurationNotProvided           = errors.New("duration should be provided for `change` command alongside the `--postpone` flag: use `--hr`, `--min` or/and `--sec` flags with corresponding integer values`")
 	ErrCompletionCmdUnknownOS                         = errors.New("can't set up completion: can't detect OS type")
 	ErrCompletionCmdUnknownShell                      = errors.New("can't set up completion: can't detect shell type")
+	ErrConfigSetCmdCannotPersistConfigs               = errors.New("can't persist user configs")
+	ErrConfigValidateCmdInvalidConfigs                = errors.New("the configs are invalid")
+	ErrDndOnCmdInvalidDuration                        = errors.New("duration provided for `dnd on` command via `--for` flag should be positive: e.g. `30m`, `1h30m`")
 	ErrDocsCmdOnDirCreation                           = errors.New("can't create directory for documentation")
 	ErrDocsCmdOnDocsGeneration                        = errors.New("can't generate documentation")
+	ErrEditCmdCannotAccessFile                        = errors.New("can't access the temporary file to edit the reminders in")
+	ErrEditCmdEditorFailed                            = errors.New("the editor has exited with an error: set the editor to use via `VISUAL` or `EDITOR` env var")
+	ErrEditCmdInvalidFlagsProvided                    = errors.New("either reminder ID or `--all` flag should be provided for `edit` command: use `--id` flag with corresponding text ID or `--all` flag with no value")
+	ErrForegroundCmdInterrupted                       = errors.New("the countdown has been interrupted")
+	ErrForegroundCmdReminderDropped                   = errors.New("the low urgency reminder has come due within the quiet hours, so it has been dropped")
 	ErrInAtCmdNoMessageProvided                       = errors.New("message should be provided for `in`/`at` command: use `--about` flag with corresponding text message")
+	ErrInAtCmdInvalidUrgency                          = errors.New("urgency provided for `in`/`at` command via `--urgency` flag should be one of: low, normal or high")
 	ErrInCmdDurationNotProvided                       = errors.New("duration should be provided for `in` command: use `--hr`, `--min` or/and `--sec` flags with corresponding integer values`")
 	ErrInCmdInvalidDuration                           = errors.New("duration provided for `in` command via `--hr`, `--min` or/and `--sec` flags should be either 0 or a positive integer value`")
 	ErrListCmdSortingInvalidSortByFlagsProvided       = errors.New("either --id, --message or --time flag should be provided, not both")
 	ErrListCmdSortingInvalidSortingOrderFlagsProvided = errors.New("either --asc or --desc flag should be provided, not both")
 	ErrListCmdSortingNotRequested                     = errors.New("--sort flag should be provided alongside the other sorting flags")
+	ErrNextCmdCannotReadState                         = errors.New("can't read the next reminder written by the app: run `list` command instead")
+	ErrPomodoroStartCmdInvalidCycles                  = errors.New("number of cycles provided for `pomodoro start` command via `--cycles` flag should be in range [1, 24]")
+	ErrPomodoroStartCmdInvalidDuration                = errors.New("durations provided for `pomodoro start` command via `--work` and `--break` flags should be positive: e.g. `25m`, `5m`")
+	ErrPromptCmdUnknownShell                          = errors.New("can't generate the prompt snippet: can't detect shell type, provide it as the argument: bash, zsh or fish")
+	ErrShowCmdIdNotProvided                           = errors.New("reminder ID should be provided for `show` command: use `--id` flag with corresponding text ID")
 	ErrStartCmdAlreadyRunning                         = errors.New("the application is already running, please, run the desired command")
+	ErrStartCmdReadinessTimeout                       = errors.New("the application hasn't become ready within 10 seconds: check the server logs with `admin logs print --server` command")
+	ErrStartCmdServerExited                           = errors.New("the application has failed to start: see the server output above")
+	ErrStopCmdCannotTerminateProcess                  = errors.New("the application hasn't responded to the stop request, and its process can't be terminated")
+	ErrStopCmdTimeout                                 = errors.New("the application hasn't stopped within 10 seconds")
+	ErrTemplateAddCmdCannotPersistTemplate            = errors.New("can't persist the template to the configs file")
+	ErrTemplateAddCmdInvalidTimeFlagsProvided         = errors.New("either `--in` or `--time` flag should be provided for `template add` command, not both")
+	ErrTemplateCmdInvalidName                         = errors.New("template name should consist of up to 64 latin letters, digits, `-` and `_`")
+	ErrTemplateRmCmdCannotPersistTemplates            = errors.New("can't remove the template from the configs file")
+	ErrTimerCmdInvalidDuration                        = errors.New("duration provided for `timer` command should be positive: e.g. `25m`, `1h30m`")
+	ErrUndoCmdInvalidFlagsProvided                    = errors.New("either `--id` or `--list` flag should be provided for `undo` command, not both")
+	ErrUndoCmdInvalidId                               = errors.New("operation ID should be a positive integer: run `undo --list` command to see the operations that can be undone")
+	ErrUseCmdInvalidDuration                          = errors.New("duration provided for `use` command via `--in` flag should be positive: e.g. `20m`, `1h30m`")
+	ErrUseCmdInvalidTimeFlagsProvided                 = errors.New("either `--in` or `--time` flag should be provided for `use` command, not both")
+	ErrUseCmdTimeNotProvided                          = errors.New("the template has no time set, so it should be provided for `use` command: use either `--in` flag with the duration (e.g. `20m`, `1h30m`), or `--time` flag with the time in 24-hours HH:MM format (e.g. `16:30`)")
 
-	ErrCmdCannotResolveServerPort       = errors.New("can't resolve server port")
+	ErrCmdCannotResolveServerAddress    = errors.New("can't resolve server address")
+	ErrCmdConfirmationRequired          = errors.New("several reminders are selected, so the confirmation is required, but the input is not a terminal: use `--yes` flag to skip it")
+	ErrCmdInvalidIds                    = errors.New("--id flag should be either a single positive integer ID, or the comma-separated list of IDs or ID ranges: e.g. `3`, `1,2,5` or `5-8`")
+	ErrCmdInvalidProfile                = errors.New("profile name should consist of up to 64 latin letters, digits, `-` and `_`")
+	ErrCmdInvalidOutput                 = errors.New("--output flag should be one of: table, json, yaml, csv or template")
+	ErrCmdInvalidTemplate               = errors.New("--template flag should be a valid Go template: e.g. `{{.ID}} {{.Message}}`")
 	ErrCmdInvalidPort                   = errors.New("port should be provided as an integer value in range [0, 65535]")
+	ErrCmdInvalidTransport              = errors.New("transport should be either `unix` or `tcp`")
+	ErrCmdTemplateNotProvided           = errors.New("--template flag should be provided alongside `--output template`")
 	ErrCmdTimeShouldBeInFuture          = errors.New("provided time should be in future")
 	ErrCmdWrongFormatted24HoursTime     = errors.New("time should be provided in 24-hours HH:MM format: e.g. `16:30`, `07:45`, `00:00`")
 	ErrCmdWrongFormatted12HoursAmPmTime = errors.New("time should be provided in A.M./P.M. 12-hours HH:MM format: e.g. `07:45`")
+	ErrCmdWrongFormattedPointInTime     = errors.New("time should be provided either as a duration ago (e.g. `30m`, `2h`), or in one of the formats: `2006-01-02T15:04:05Z07:00`, `2006-01-02 15:04:05`, `2006-01-02` or `15:04`")
+
+	// autostart errors:
+	ErrAutostartSocketActivationUnsupported = errors.New("socket activation is supported by systemd only")
+	ErrAutostartUnsupportedOs               = errors.New("autostart is supported on Linux (systemd) and MacOS (launchd) only")
+
+	// TUI errors:
+	ErrTuiCannotSetUpTerminal = errors.New("can't switch the terminal to the raw mode")
+	ErrTuiInvalidDuration     = errors.New("duration should be positive: e.g. `10m`, `1h30m`")
+	ErrTuiInvalidTime         = errors.New("time should be either a duration (e.g. `10m`, `1h30m`), or the time in `16:30` or `2006-01-02 16:30` format")
+	ErrTuiMessageNotProvided  = errors.New("message should be provided")
+	ErrTuiNothingToSnooze     = errors.New("nothing has been notified about since the app start")
+	ErrTuiNotTerminal         = errors.New("the interactive UI should be run in the terminal: use `list` command to print the reminders instead")
+
+	// scheduler errors:
+	ErrJournalEntryConflict   = errors.New("the journal entry has been followed by the operations on the same reminders")
+	ErrPomodoroAlreadyRunning = errors.New("the pomodoro session is running already")
+
+	// PID file errors:
+	ErrPidFileLocked = errors.New("the PID file is locked by another running server")
 
 	// HTTP client errors:
 	ErrHttpOnCallingServer        = errors.New("seems like the application is down: please, run `start` command")
+	ErrHttpOnApplyingBatch        = errors.New("error on applying the changes")
 	ErrHttpOnChangingReminder     = errors.New("error on changing the reminder")
 	ErrHttpOnDeletingAllReminders = errors.New("error on cancelling all reminders")
 	ErrHttpOnDeletingReminder     = errors.New("error on cancelling the reminder")
+	ErrHttpOnDisablingDnd         = errors.New("error on turning the do-not-disturb mode off")
+	ErrHttpOnEnablingDnd          = errors.New("error on turning the do-not-disturb mode on")
 	ErrHttpOnGettingAllReminders  = errors.New("error on getting all reminders")
+	ErrHttpOnGettingJournal       = errors.New("error on getting the operations journal")
+	ErrHttpOnGettingNotifications = errors.New("error on getting the notifications history")
+	ErrHttpOnGettingPomodoro      = errors.New("error on getting the pomodoro session")
 	ErrHttpOnGettingReminderById  = errors.New("error on getting reminder by ID")
+	ErrHttpOnGettingStatus        = errors.New("error on getting the app status")
+	ErrHttpOnReloadingConfigs     = errors.New("error on reloading the app configs")
 	ErrHttpOnSettingUpReminder    = errors.New("error on setting up the reminder")
+	ErrHttpOnSkippingPomodoro     = errors.New("error on skipping the pomodoro interval")
+	ErrHttpOnStartingPomodoro     = errors.New("error on starting the pomodoro session")
+	ErrHttpOnStoppingPomodoro     = errors.New("error on stopping the pomodoro session")
 	ErrHttpOnTerminatingApp       = errors.New("error on terminating the app")
+	ErrHttpOnUndoing              = errors.New("error on undoing the operation")
+	ErrHttpUnauthorized           = errors.New("the request has been rejected by the application due to missing or invalid API token: please, restart the app with `stop` and `start` commands")
 
-	ErrHttpInternal         = errors.New("internal error")
-	ErrHttpReminderNotFound = errors.New("reminder not found with the provided ID")
+	ErrHttpInternal             = errors.New("internal error")
+	ErrHttpInvalidConfigs       = errors.New("the configs have been rejected by the application as invalid: run `config validate` command for details")
+	ErrHttpJournalEntryConflict = errors.New("the operation can't be undone, as the later operations have changed the same reminders: undo them first")
+	ErrHttpJournalEntryNotFound = errors.New("journal entry not found with the provided ID: it has been undone already or is too old, run `undo --list` command to see the ones that can be undone")
+	ErrHttpNothingToUndo        = errors.New("nothing to undo")
+	ErrHttpPomodoroNotRunning   = errors.New("no pomodoro session is running: run `pomodoro start` command to start one")
+	ErrHttpPomodoroRunning      = errors.New("the pomodoro session is running already: run `pomodoro stop` command to stop it first")
+	ErrHttpReminderNotFound     = errors.New("reminder not found with the provided ID")
 
 	// HTTP server errors:
-	ErrCodeReminderIdWrongFormat = "bad_request.reminder_id"
-	ErrCodeReminderNotFound      = "not_found.reminder"
-	ErrCodeDbQuerying            = "internal.db"
-	ErrCodeRequestBody           = "bad_request.request_body"
-	ErrCodeResponseMarshaling    = "internal.response_marshaling"
+	ErrCodeReminderIdWrongFormat  = "bad_request.reminder_id"
+	ErrCodeReminderNotFound       = "not_found.reminder"
+	ErrCodeDbQuerying             = "internal.db"
+	ErrCodeRequestBody            = "bad_request.request_body"
+	ErrCodeResponseMarshaling     = "internal.response_marshaling"
+	ErrCodeBatchEmpty             = "bad_request.batch_empty"
+	ErrCodeBatchOperationType     = "bad_request.batch_operation_type"
+	ErrCodeBatchOperationId       = "bad_request.batch_operation_id"
+	ErrCodeBatchOperationReminder = "bad_request.batch_operation_reminder"
+	ErrCodeForbiddenHost          = "forbidden.host"
+	ErrCodeForbiddenOrigin        = "forbidden.origin"
+	ErrCodeUnauthorized           = "unauthorized.api_token"
+	ErrCodeInvalidConfigs         = "bad_request.configs"
+	ErrCodeReminderMessage        = "bad_request.reminder_message"
+	ErrCodeReminderTime           = "bad_request.reminder_time"
+	ErrCodeReminderUrgency        = "bad_request.reminder_urgency"
+	ErrCodeDndUntil               = "bad_request.dnd_until"
+	ErrCodeJournalEntryNotFound   = "not_found.journal_entry"
+	ErrCodeJournalEntryConflict   = "conflict.journal_entry"
+	ErrCodePomodoroRequest        = "bad_request.pomodoro"
+	ErrCodePomodoroNotFound       = "not_found.pomodoro"
+	ErrCodePomodoroRunning        = "conflict.pomodoro"
 )
 
+// ExitCodeError makes the app exit with the provided code rather than the default one
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error {
+	return e.Err
+}
+
+// BatchOperationError is returned by the repo if one of the batch operations can't be applied.
+// The whole batch is rolled back in such case.
+type BatchOperationError struct {
+	Index int
+	Code  string
+}
+
+func (e *BatchOperationError) Error() string {
+	return fmt.Sprintf(errBatchOperationTemplate, e.Index, e.Code)
+}
+
 func ErrWrongFormattedStringFlag(flagName string) error {
 	return errors.New(fmt.Sprintf(errWrongFormattedStringFlagTemplate, flagName))
 }
@@ -76,10 +213,28 @@ func ErrWrongFormattedIntFlag(flagName string) error {
 	return errors.New(fmt.Sprintf(errWrongFormattedIntFlagTemplate, flagName))
 }
 
+// ErrCmdWrongFormattedFilterTime is returned if the time to select the reminders with is invalid: e.g. via `--before` flag
+func ErrCmdWrongFormattedFilterTime(flagName string) error {
+	return errors.New(fmt.Sprintf(errWrongFormattedFilterTimeTemplate, flagName))
+}
+
+func ErrCmdRemindersNotFound(ids []string) error {
+	return errors.New(fmt.Sprintf(errRemindersNotFoundTemplate, strings.Join(ids, ", ")))
+}
+
 func ErrWrongFormattedIntEnvVar(envVar string) error {
 	return errors.New(fmt.Sprintf(errWrongFormattedIntEnvVarTemplate, envVar))
 }
 
+// ErrInvalidConfig describes the invalid config value, and where it has been provided: either via the configs file, or the env var
+func ErrInvalidConfig(key string, providedVia string, reason error) error {
+	return errors.New(fmt.Sprintf(errInvalidConfigTemplate, key, providedVia, reason))
+}
+
+func ErrUnknownConfig(key string) error {
+	return errors.New(fmt.Sprintf(errUnknownConfigTemplate, key))
+}
+
 func ErrCompletionCmdUnsupportedShell(shellType string) error {
 	return errors.New(fmt.Sprintf(errCompletionUnsupportedShellTemplate, shellType))
 }
@@ -87,3 +242,20 @@ func ErrCompletionCmdUnsupportedShell(shellType string) error {
 func ErrCompletionCmdUnsupportedOs(osType string) error {
 	return errors.New(fmt.Sprintf(errCompletionUnsupportedOsTemplate, osType))
 }
+
+func ErrPromptCmdUnsupportedShell(shellType string) error {
+	return errors.New(fmt.Sprintf(errPromptUnsupportedShellTemplate, shellType))
+}
+
+func ErrTemplateNotFound(name string) error {
+	return errors.New(fmt.Sprintf(errTemplateNotFoundTemplate, name))
+}
+
+func ErrTemplateAlreadyExists(name string) error {
+	return errors.New(fmt.Sprintf(errTemplateAlreadyExistsTemplate, name))
+}
+
+// ErrInvalidTemplate describes why the template can't be used: e.g. it has been edited manually in the configs file
+func ErrInvalidTemplate(name string, reason error) error {
+	return errors.New(fmt.Sprintf(errInvalidTemplateTemplate, name, reason))
+}
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"strconv"
 	"time"
 )
 
@@ -8,20 +9,304 @@ type Reminder struct {
 	ID       int64
 	Message  string
 	RemindAt time.Time
+	// low, normal or high: defines how the reminder is notified within the quiet hours, normal if empty
+	Urgency string `json:",omitempty"`
+	// managed by the repo: the client-provided values are ignored
+	CreatedAt time.Time `json:",omitzero"`
+	UpdatedAt time.Time `json:",omitzero"`
+}
+
+type BatchOperation struct {
+	Type     string    `json:"type,omitempty"`
+	ID       int64     `json:"id,omitempty"`
+	Reminder *Reminder `json:"reminder,omitempty"`
+}
+
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations,omitempty"`
+}
+
+type BatchOperationResult struct {
+	Index  int    `json:"index"`
+	Type   string `json:"type,omitempty"`
+	ID     int64  `json:"id,omitempty"`
+	Status string `json:"status,omitempty"`
+	Code   string `json:"code,omitempty"`
+}
+
+type BatchResponse struct {
+	Applied bool                   `json:"applied"`
+	Results []BatchOperationResult `json:"results"`
 }
 
 type AdminConfigs struct {
-	ServerPort int `yaml:"serverPort,omitempty"`
+	ServerPort      int    `yaml:"serverPort,omitempty"`
+	ServerTransport string `yaml:"serverTransport,omitempty"`
+}
+
+// UserConfigs are the configs managed by the user, unlike AdminConfigs that are managed by the app itself
+type UserConfigs struct {
+	Notifications   NotificationsConfigs `yaml:"notifications,omitempty"`
+	QuietHours      QuietHoursConfigs    `yaml:"quietHours,omitempty"`
+	CleanupInterval string               `yaml:"cleanupInterval,omitempty"`
+	TimeFormat      string               `yaml:"timeFormat,omitempty"`
+	Logs            LogsConfigs          `yaml:"logs,omitempty"`
+	Repo            RepoConfigs          `yaml:"repo,omitempty"`
+	// the templates are managed with the `template` commands rather than `config set`, as they are not the single values
+	Templates map[string]ReminderTemplate `yaml:"templates,omitempty"`
+}
+
+// ReminderTemplate is the preset of the frequent reminder, which is created with the `use` command
+type ReminderTemplate struct {
+	Message string `yaml:"message"`
+	// either the duration from now or the 24-hours HH:MM time: if none is set, the time is provided on use
+	In      string `yaml:"in,omitempty"`
+	At      string `yaml:"at,omitempty"`
+	Urgency string `yaml:"urgency,omitempty"`
+}
+
+type NotificationsConfigs struct {
+	Backends      []string `yaml:"backends,omitempty"`
+	DefaultSnooze string   `yaml:"defaultSnooze,omitempty"`
+}
+
+type QuietHoursConfigs struct {
+	From      string   `yaml:"from,omitempty"`
+	To        string   `yaml:"to,omitempty"`
+	Monday    string   `yaml:"monday,omitempty"`
+	Tuesday   string   `yaml:"tuesday,omitempty"`
+	Wednesday string   `yaml:"wednesday,omitempty"`
+	Thursday  string   `yaml:"thursday,omitempty"`
+	Friday    string   `yaml:"friday,omitempty"`
+	Saturday  string   `yaml:"saturday,omitempty"`
+	Sunday    string   `yaml:"sunday,omitempty"`
+	Holidays  []string `yaml:"holidays,omitempty"`
+}
+
+// Weekday returns the quiet hours window configured for the provided weekday
+func (qhc *QuietHoursConfigs) Weekday(weekday time.Weekday) *string {
+	switch weekday {
+	case time.Monday:
+		return &qhc.Monday
+	case time.Tuesday:
+		return &qhc.Tuesday
+	case time.Wednesday:
+		return &qhc.Wednesday
+	case time.Thursday:
+		return &qhc.Thursday
+	case time.Friday:
+		return &qhc.Friday
+	case time.Saturday:
+		return &qhc.Saturday
+	default:
+		return &qhc.Sunday
+	}
+}
+
+type LogsConfigs struct {
+	Level      string `yaml:"level,omitempty"`
+	Format     string `yaml:"format,omitempty"`
+	MaxSizeMb  int    `yaml:"maxSizeMb,omitempty"`
+	MaxAgeDays int    `yaml:"maxAgeDays,omitempty"`
+	MaxBackups int    `yaml:"maxBackups,omitempty"`
+	Compress   *bool  `yaml:"compress,omitempty"`
+}
+
+type RepoConfigs struct {
+	Backend string `yaml:"backend,omitempty"`
+}
+
+// QuietHoursWindow is the daily time window, defined in minutes since midnight.
+// If From is after To, the window lasts over midnight: e.g. from 22:00 to 07:00.
+type QuietHoursWindow struct {
+	From int
+	To   int
+}
+
+// QuietHours is the weekly schedule the notifications are held back during
+type QuietHours struct {
+	// indexed by time.Weekday, nil if there are no quiet hours on that day
+	Weekdays [7]*QuietHoursWindow
+	// the dates in YYYY-MM-DD format, which are quiet all day long
+	Holidays map[string]bool
+}
+
+// End returns the end of the quiet period if the provided time is within it.
+// The adjacent windows and holidays are merged: e.g. the Friday night window is followed by the holiday on Saturday.
+func (qh QuietHours) End(t time.Time) (time.Time, bool) {
+	end, within := t, false
+	// capped in case if the whole year is quiet
+	for i := 0; i < 2*366; i++ {
+		windowEnd, found := qh.windowEnd(end)
+		if !found {
+			break
+		}
+		end, within = windowEnd, true
+	}
+	return end, within
+}
+
+// windowEnd returns the end of the single window or holiday the provided time is within, if any
+func (qh QuietHours) windowEnd(t time.Time) (time.Time, bool) {
+	minutes := t.Hour()*60 + t.Minute()
+	year, month, day := t.Date()
+
+	if qh.Holidays[t.Format(DateFormat)] {
+		return time.Date(year, month, day+1, 0, 0, 0, 0, t.Location()), true
+	}
+
+	if window := qh.Weekdays[t.Weekday()]; window != nil {
+		switch {
+		case window.From < window.To && minutes >= window.From && minutes < window.To:
+			return time.Date(year, month, day, 0, window.To, 0, 0, t.Location()), true
+		case window.From > window.To && minutes >= window.From:
+			return time.Date(year, month, day+1, 0, window.To, 0, 0, t.Location()), true
+		}
+	}
+
+	// the window of the previous day might last over midnight
+	if window := qh.Weekdays[(t.Weekday()+6)%7]; window != nil && window.From > window.To && minutes < window.To {
+		return time.Date(year, month, day, 0, window.To, 0, 0, t.Location()), true
+	}
+	return time.Time{}, false
+}
+
+// ServerAddress describes how to reach the server: either via TCP port on the localhost, or via Unix domain socket
+type ServerAddress struct {
+	Transport  string
+	Port       int
+	SocketPath string
+}
+
+func (sa ServerAddress) String() string {
+	if sa.Transport == UnixTransport {
+		return "unix:" + sa.SocketPath
+	}
+	return "localhost:" + strconv.Itoa(sa.Port)
 }
 
 type Healthcheck struct {
 	Status string `json:"status,omitempty"`
 }
 
+// ServerInfo describes the running server, and doesn't change during its lifetime
+type ServerInfo struct {
+	Version   string    `json:"version"`
+	StartedAt time.Time `json:"startedAt"`
+	Transport string    `json:"transport"`
+	Address   string    `json:"address"`
+	Port      int       `json:"port,omitempty"`
+	RepoType  string    `json:"repoType"`
+	// whether the requested repo failed to start, and the in-memory one is used instead
+	RepoFallback bool   `json:"repoFallback,omitempty"`
+	DbPath       string `json:"dbPath,omitempty"`
+}
+
+type NotificationResult struct {
+	ReminderID int64     `json:"reminderId"`
+	Message    string    `json:"message"`
+	SentAt     time.Time `json:"sentAt"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type SchedulerStatus struct {
+	ActiveTimers     int                 `json:"activeTimers"`
+	NextReminder     *Reminder           `json:"nextReminder,omitempty"`
+	LastNotification *NotificationResult `json:"lastNotification,omitempty"`
+	LastCleanupAt    *time.Time          `json:"lastCleanupAt,omitempty"`
+	// the end of the ad-hoc do-not-disturb period, if it's on
+	DndUntil *time.Time `json:"dndUntil,omitempty"`
+	// the end of the current quiet period: either the quiet hours or the do-not-disturb one, if any
+	QuietUntil *time.Time `json:"quietUntil,omitempty"`
+}
+
+// SchedulerState is the in-memory state of the scheduler that is persisted on the server shutdown to be restored on the next start
+type SchedulerState struct {
+	// the most recent notifications, the oldest first
+	Notifications []NotificationResult `json:"notifications,omitempty"`
+	DndUntil      *time.Time           `json:"dndUntil,omitempty"`
+	DndDeferred   map[int64]time.Time  `json:"dndDeferred,omitempty"`
+	// the most recent mutating operations, the oldest first
+	Journal []JournalEntry `json:"journal,omitempty"`
+	// the latest pomodoro session, which might be over already
+	Pomodoro *Pomodoro `json:"pomodoro,omitempty"`
+}
+
+// NextReminderState is written by the server on every schedule change,
+// so that the next reminder can be read without the request to the server: e.g. by the shell prompt
+type NextReminderState struct {
+	// nil if nothing is scheduled
+	Reminder  *Reminder `json:"reminder,omitempty"`
+	Scheduled int       `json:"scheduled"`
+}
+
+// JournalEntry records the mutating operation, so that it can be undone
+type JournalEntry struct {
+	ID        int64     `json:"id"`
+	Operation string    `json:"operation"`
+	At        time.Time `json:"at"`
+	// the reminders created by the operation: they are cancelled on undo
+	Created []Reminder `json:"created,omitempty"`
+	// the reminders changed or cancelled by the operation, as they were before it: they are restored on undo
+	Before []Reminder `json:"before,omitempty"`
+}
+
+// UndoRequest undoes the journal entry with the ID, or the latest one if the ID is not provided
+type UndoRequest struct {
+	ID int64 `json:"id,omitempty"`
+}
+
+// PomodoroRequest starts the pomodoro session: the cycles of the work interval followed by the break, except for the last one
+type PomodoroRequest struct {
+	WorkSeconds  int64 `json:"workSeconds"`
+	BreakSeconds int64 `json:"breakSeconds"`
+	Cycles       int   `json:"cycles"`
+}
+
+// Pomodoro is the chain of the work and break intervals, each one ending with the reminder.
+// The reminders are linked as the group, so that the chain is managed as a unit: the session is over once none of them is scheduled.
+type Pomodoro struct {
+	WorkSeconds  int64     `json:"workSeconds"`
+	BreakSeconds int64     `json:"breakSeconds"`
+	Cycles       int       `json:"cycles"`
+	StartedAt    time.Time `json:"startedAt"`
+	// in order: the ones still scheduled only in the API responses, the current one first
+	Intervals []PomodoroInterval `json:"intervals"`
+}
+
+type PomodoroInterval struct {
+	// work or break
+	Type       string    `json:"type"`
+	Cycle      int       `json:"cycle"`
+	ReminderID int64     `json:"reminderId"`
+	EndsAt     time.Time `json:"endsAt"`
+}
+
+// Dnd is the ad-hoc do-not-disturb period
+type Dnd struct {
+	Until time.Time `json:"until"`
+}
+
+type Status struct {
+	Status        string          `json:"status"`
+	UptimeSeconds int64           `json:"uptimeSeconds"`
+	Server        ServerInfo      `json:"server"`
+	Scheduler     SchedulerStatus `json:"scheduler"`
+}
+
 type ErrorResponse struct {
 	Code string `json:"code,omitempty"`
 }
 
-func HealthcheckOk() Healthcheck {
-	return Healthcheck{Status: "OK"}
+// HealthStatus is degraded if the server works, but not as expected:
+// e.g. fell back to the in-memory repo, so the reminders won't survive the restart, or failed to send the last notification
+func HealthStatus(serverInfo ServerInfo, schedulerStatus SchedulerStatus) string {
+	if serverInfo.RepoFallback {
+		return HealthStatusDegraded
+	}
+	if schedulerStatus.LastNotification != nil && !schedulerStatus.LastNotification.Success {
+		return HealthStatusDegraded
+	}
+	return HealthStatusOk
 }
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursEnd(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	// 2026-10-16 is Friday
+	nights := &QuietHoursWindow{From: 22 * 60, To: 7 * 60}
+	weekdays := [7]*QuietHoursWindow{}
+	for day := time.Monday; day <= time.Friday; day++ {
+		weekdays[day] = nights
+	}
+	// the lunch break on Saturday, and the late night on Sunday
+	weekdays[time.Saturday] = &QuietHoursWindow{From: 12 * 60, To: 13 * 60}
+	weekdays[time.Sunday] = &QuietHoursWindow{From: 23 * 60, To: 9 * 60}
+	quietHours := QuietHours{Weekdays: weekdays, Holidays: map[string]bool{"2026-10-20": true}}
+
+	tests := []struct {
+		name        string
+		time        string
+		expectedEnd string
+		within      bool
+	}{
+		{name: "before the window", time: "2026-10-15 21:59", within: false},
+		{name: "window start", time: "2026-10-15 22:00", expectedEnd: "2026-10-16 07:00", within: true},
+		{name: "after midnight within the previous day window", time: "2026-10-16 03:30", expectedEnd: "2026-10-16 07:00", within: true},
+		{name: "window end", time: "2026-10-16 07:00", within: false},
+		{name: "Friday night lasts into Saturday", time: "2026-10-16 23:00", expectedEnd: "2026-10-17 07:00", within: true},
+		{name: "same day window", time: "2026-10-17 12:30", expectedEnd: "2026-10-17 13:00", within: true},
+		{name: "no window over midnight on Saturday", time: "2026-10-18 03:00", within: false},
+		{name: "Sunday night", time: "2026-10-18 23:30", expectedEnd: "2026-10-19 09:00", within: true},
+		{name: "Monday day", time: "2026-10-19 12:00", within: false},
+		// Monday night is followed by the holiday on Tuesday and its night
+		{name: "night merged with the holiday", time: "2026-10-19 22:30", expectedEnd: "2026-10-21 07:00", within: true},
+		{name: "holiday", time: "2026-10-20 15:00", expectedEnd: "2026-10-21 07:00", within: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := at(test.time)
+			end, within := quietHours.End(now)
+
+			if within != test.within {
+				t.Fatalf("expected within to be %t, got %t", test.within, within)
+			}
+			if !within {
+				if !end.Equal(now) {
+					t.Errorf("expected the provided time to be returned outside the quiet hours, got %s", end)
+				}
+				return
+			}
+			if expected := at(test.expectedEnd); !end.Equal(expected) {
+				t.Errorf("expected the end at %s, got %s", expected, end)
+			}
+		})
+	}
+}
+
+func TestQuietHoursEndWithoutWindows(t *testing.T) {
+	now := time.Now()
+	end, within := QuietHours{}.End(now)
+	if within || !end.Equal(now) {
+		t.Errorf("expected no quiet hours, got %s", end)
+	}
+}
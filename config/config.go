@@ -1,17 +1,25 @@
 package config
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"gopkg.in/yaml.v3"
 	"n0rdy.foo/remindme/common"
 	"n0rdy.foo/remindme/logger"
 	"n0rdy.foo/remindme/utils"
 	"os"
-	"strconv"
+	"strings"
 )
 
+const apiTokenBytesLength = 32
+
 func FetchAdminConfigs() (*common.AdminConfigs, error) {
-	configsAsBytes, err := os.ReadFile(getAdminConfigsFilePath())
+	return fetchAdminConfigs(utils.Profile())
+}
+
+func fetchAdminConfigs(profile string) (*common.AdminConfigs, error) {
+	configsAsBytes, err := os.ReadFile(getProfileAdminConfigsFilePath(profile))
 	if err != nil {
 		return nil, err
 	}
@@ -49,25 +57,91 @@ func DeleteAdminConfigs() error {
 	return err
 }
 
-func ResolveRunningServerPort() (int, error) {
-	configs, err := FetchAdminConfigs()
+func ResolveRunningServerAddress() (common.ServerAddress, error) {
+	return ResolveProfileServerAddress(utils.Profile())
+}
+
+// ResolveProfileServerAddress resolves the address the server of the provided profile is expected to listen to
+func ResolveProfileServerAddress(profile string) (common.ServerAddress, error) {
+	address := common.ServerAddress{
+		Transport:  utils.DefaultTransport(),
+		Port:       utils.DefaultProfilePort(profile),
+		SocketPath: utils.GetProfileAppDataDir(profile) + common.ServerSocketFileName,
+	}
+
+	configs, err := fetchAdminConfigs(profile)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			// for backward compatibility with the previous versions when it was not possible to change the server port, so nothing was persisted
 			// This should catch the case when the server has been started by the old version of the remindme app, and the user tries to stop it with the new version
 			// Otherwise, the error will be thrown by the `stop` command, as it can't resolve the server port from the admin configs file
 			//
-			// Another reason: if the default port is requested for the `start` command,
+			// Another reason: if the default address is requested for the `start` command,
 			// it won't be persisted in the admin configs file to avoid OS-specific file access errors for the users that don't need the port binding feature (so, for the majority, I guess)
-			logger.Info("server port is not persisted, falling back to default: " + strconv.Itoa(common.DefaultHttpServerPort))
-			return common.DefaultHttpServerPort, nil
+			logger.Info("server address is not persisted, falling back to default: " + address.String())
+			return address, nil
 		}
-		return 0, err
+		return address, err
 	}
 
-	return configs.ServerPort, nil
+	if configs.ServerTransport == "" {
+		// the configs persisted by the previous versions of the app, which could only listen to the TCP port
+		address.Transport = common.TcpTransport
+	} else {
+		address.Transport = configs.ServerTransport
+	}
+	if configs.ServerPort != 0 {
+		address.Port = configs.ServerPort
+	}
+	return address, nil
+}
+
+// GenerateApiToken generates a new random API token and persists it into the file that only the current user can access.
+// The previous token (if any) is overwritten, so the clients of the previously started server won't be able to use it anymore.
+func GenerateApiToken() (string, error) {
+	tokenAsBytes := make([]byte, apiTokenBytesLength)
+	_, err := rand.Read(tokenAsBytes)
+	if err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(tokenAsBytes)
+
+	tokenFilePath := getApiTokenFilePath()
+	// the file is recreated rather than truncated to make sure that the permissions are not inherited from the previous file
+	err = os.Remove(tokenFilePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	tokenFile, err := os.OpenFile(tokenFilePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer tokenFile.Close()
+
+	_, err = tokenFile.WriteString(token)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func FetchApiToken() (string, error) {
+	tokenAsBytes, err := os.ReadFile(getApiTokenFilePath())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(tokenAsBytes)), nil
+}
+
+func getApiTokenFilePath() string {
+	return utils.GetOsSpecificAppDataDir() + common.ApiTokenFileName
 }
 
 func getAdminConfigsFilePath() string {
-	return utils.GetOsSpecificAppDataDir() + common.AdminConfigsFileName
+	return getProfileAdminConfigsFilePath(utils.Profile())
+}
+
+func getProfileAdminConfigsFilePath(profile string) string {
+	return utils.GetProfileAppDataDir(profile) + common.AdminConfigsFileName
 }
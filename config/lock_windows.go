@@ -0,0 +1,23 @@
+//go:build windows
+
+package config
+
+import (
+	"golang.org/x/sys/windows"
+	"os"
+)
+
+// the locked byte range lies beyond the PID written at the beginning of the file,
+// as Windows locks are mandatory, and the PID should stay readable by the other processes
+const lockOffsetHigh = 1
+
+// lockFile fails right away if the file is locked by another process
+func lockFile(file *os.File) error {
+	overlapped := &windows.Overlapped{OffsetHigh: lockOffsetHigh}
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, overlapped)
+}
+
+func unlockFile(file *os.File) error {
+	overlapped := &windows.Overlapped{OffsetHigh: lockOffsetHigh}
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, overlapped)
+}
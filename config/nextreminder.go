@@ -0,0 +1,46 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/utils"
+	"os"
+)
+
+// PersistNextReminderState replaces the file atomically, so that the shell prompt never reads the partially written one
+func PersistNextReminderState(state common.NextReminderState) error {
+	stateAsBytes, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	tmpFilePath := getNextReminderFilePath() + ".tmp"
+	err = os.WriteFile(tmpFilePath, stateAsBytes, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpFilePath, getNextReminderFilePath())
+}
+
+// FetchNextReminderState returns the state written by the server, or nil if it has never been started
+func FetchNextReminderState() (*common.NextReminderState, error) {
+	stateAsBytes, err := os.ReadFile(getNextReminderFilePath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	state := &common.NextReminderState{}
+	err = json.Unmarshal(stateAsBytes, state)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func getNextReminderFilePath() string {
+	return utils.GetOsSpecificAppDataDir() + common.NextReminderFileName
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PidFile is locked by the running server for its whole lifetime, so that the second server can't be started on the same data dir.
+// The lock is released by the OS even if the server is killed, so the leftover file doesn't prevent the next start.
+type PidFile struct {
+	file *os.File
+}
+
+// AcquirePidFile locks the PID file of the current profile and writes the PID of the current process into it.
+// Fails with common.ErrPidFileLocked if the file is locked by another process already.
+func AcquirePidFile() (*PidFile, error) {
+	file, err := os.OpenFile(getPidFilePath(), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lockFile(file)
+	if err != nil {
+		file.Close()
+		return nil, common.ErrPidFileLocked
+	}
+
+	// the file is never removed, but rather emptied on release, so that all the processes lock the same file
+	err = file.Truncate(0)
+	if err == nil {
+		_, err = file.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	}
+	if err != nil {
+		unlockFile(file)
+		file.Close()
+		return nil, err
+	}
+	return &PidFile{file: file}, nil
+}
+
+func (pf *PidFile) Release() error {
+	pf.file.Truncate(0)
+	unlockFile(pf.file)
+	return pf.file.Close()
+}
+
+// ReadRunningPid returns the PID of the server running on the current profile data dir, and reports whether there is any
+func ReadRunningPid() (int, bool, error) {
+	file, err := os.OpenFile(getPidFilePath(), os.O_RDWR, 0600)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	defer file.Close()
+
+	// if the file can be locked, no server holds it
+	if lockFile(file) == nil {
+		unlockFile(file)
+		return 0, false, nil
+	}
+
+	pidAsBytes, err := io.ReadAll(file)
+	if err != nil {
+		return 0, true, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(pidAsBytes)))
+	if err != nil {
+		return 0, true, errors.New("wrong formatted PID file: " + err.Error())
+	}
+	return pid, true, nil
+}
+
+func getPidFilePath() string {
+	return utils.GetOsSpecificAppDataDir() + common.PidFileName
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/utils"
+	"os"
+)
+
+func PersistSchedulerState(state common.SchedulerState) error {
+	stateAsBytes, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getSchedulerStateFilePath(), stateAsBytes, 0600)
+}
+
+// FetchSchedulerState returns the state persisted by the previous server on its shutdown, if any, and deletes it:
+// this way, the outdated state is not restored if the server hasn't been shut down properly the next time
+func FetchSchedulerState() (*common.SchedulerState, error) {
+	stateAsBytes, err := os.ReadFile(getSchedulerStateFilePath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	err = os.Remove(getSchedulerStateFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	state := &common.SchedulerState{}
+	err = json.Unmarshal(stateAsBytes, state)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func getSchedulerStateFilePath() string {
+	return utils.GetOsSpecificAppDataDir() + common.SchedulerStateFileName
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"n0rdy.foo/remindme/common"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var templateNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// NamedTemplate is the reminder template alongside its name
+type NamedTemplate struct {
+	Name string
+	common.ReminderTemplate
+}
+
+// ListTemplates returns the reminder templates from the configs file sorted by name
+func ListTemplates() ([]NamedTemplate, error) {
+	userConfigs, err := FetchUserConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]NamedTemplate, 0, len(userConfigs.Templates))
+	for name, template := range userConfigs.Templates {
+		templates = append(templates, NamedTemplate{Name: name, ReminderTemplate: template})
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// GetTemplate returns the template validated, as it might have been edited manually in the configs file
+func GetTemplate(name string) (*common.ReminderTemplate, error) {
+	userConfigs, err := FetchUserConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	template, found := userConfigs.Templates[name]
+	if !found {
+		return nil, common.ErrTemplateNotFound(name)
+	}
+	err = ValidateTemplate(template)
+	if err != nil {
+		return nil, common.ErrInvalidTemplate(name, err)
+	}
+	return &template, nil
+}
+
+// AddTemplate validates the template and persists it to the configs file, the existing template is not replaced
+func AddTemplate(name string, template common.ReminderTemplate) error {
+	if !IsValidTemplateName(name) {
+		return common.ErrTemplateCmdInvalidName
+	}
+	err := ValidateTemplate(template)
+	if err != nil {
+		return common.ErrInvalidTemplate(name, err)
+	}
+
+	// the file should be fixed first, otherwise its other values would be lost
+	userConfigs, err := FetchUserConfigs()
+	if err != nil {
+		return err
+	}
+	if _, found := userConfigs.Templates[name]; found {
+		return common.ErrTemplateAlreadyExists(name)
+	}
+
+	if userConfigs.Templates == nil {
+		userConfigs.Templates = make(map[string]common.ReminderTemplate)
+	}
+	userConfigs.Templates[name] = template
+	return PersistUserConfigs(userConfigs)
+}
+
+func RemoveTemplate(name string) error {
+	userConfigs, err := FetchUserConfigs()
+	if err != nil {
+		return err
+	}
+	if _, found := userConfigs.Templates[name]; !found {
+		return common.ErrTemplateNotFound(name)
+	}
+
+	delete(userConfigs.Templates, name)
+	return PersistUserConfigs(userConfigs)
+}
+
+func IsValidTemplateName(name string) bool {
+	return templateNamePattern.MatchString(name)
+}
+
+func ValidateTemplate(template common.ReminderTemplate) error {
+	if strings.TrimSpace(template.Message) == "" {
+		return errors.New("the message should be provided")
+	}
+	if template.In != "" && template.At != "" {
+		return errors.New("either the duration or the time should be set, not both")
+	}
+	if template.In != "" {
+		err := validatePositiveDuration(template.In)
+		if err != nil {
+			return err
+		}
+	}
+	if template.At != "" {
+		err := validateClockTime(template.At)
+		if err != nil {
+			return err
+		}
+	}
+	if template.Urgency != "" {
+		return validateOneOf(common.UrgencyLow, common.UrgencyNormal, common.UrgencyHigh)(template.Urgency)
+	}
+	return nil
+}
+
+// validateTemplates reports the templates that can't be used, as they are not validated on every read of the configs
+func validateTemplates(userConfigs *common.UserConfigs) error {
+	names := make([]string, 0, len(userConfigs.Templates))
+	for name := range userConfigs.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]error, 0)
+	for _, name := range names {
+		template := userConfigs.Templates[name]
+		if !IsValidTemplateName(name) {
+			errs = append(errs, common.ErrInvalidTemplate(name, common.ErrTemplateCmdInvalidName))
+			continue
+		}
+		err := ValidateTemplate(template)
+		if err != nil {
+			errs = append(errs, common.ErrInvalidTemplate(name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,581 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"gopkg.in/yaml.v3"
+	"io"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the sources the user configs are resolved from
+const (
+	DefaultSource = "default"
+	FileSource    = "file"
+	EnvSource     = "env"
+)
+
+const megabyte = 1024 * 1024
+
+// Settings are the effective user configs: the values from the configs file overridden by the env vars, and the defaults for the rest
+type Settings struct {
+	NotificationBackends []string
+	DefaultSnooze        time.Duration
+	// nil if the quiet hours are not configured
+	QuietHours      *common.QuietHours
+	CleanupInterval time.Duration
+	// the layout to display the date and time with
+	TimeFormat  string
+	Logger      logger.Options
+	RepoBackend string
+}
+
+// ConfigValue is the effective value of the user config alongside its source
+type ConfigValue struct {
+	Key         string
+	Value       string
+	Source      string
+	EnvVar      string
+	Description string
+}
+
+// setting describes the user config: how to read it from the configs file and the env var, what's the default value,
+// and how to validate it. The values are handled as strings, the same way they are provided via the env vars or the CLI.
+type setting struct {
+	key          string
+	envVar       string
+	defaultValue string
+	description  string
+	get          func(configs *common.UserConfigs) string
+	// set expects either a valid value or an empty string to reset the config to its default
+	set      func(configs *common.UserConfigs, value string)
+	validate func(value string) error
+}
+
+var loggerDefaults = logger.DefaultOptions()
+
+var settings = []setting{
+	{
+		key:          "notifications.backends",
+		envVar:       common.NotificationsBackendsEnvVar,
+		defaultValue: common.DesktopNotificationBackend,
+		description:  "Comma-separated notification backends: desktop, alert (stays on the screen until closed) and/or beep",
+		get:          func(configs *common.UserConfigs) string { return strings.Join(configs.Notifications.Backends, ",") },
+		set: func(configs *common.UserConfigs, value string) {
+			configs.Notifications.Backends = splitList(value)
+		},
+		validate: validateNotificationBackends,
+	},
+	{
+		key:          "notifications.defaultSnooze",
+		envVar:       common.NotificationsDefaultSnoozeEnvVar,
+		defaultValue: "10m",
+		description:  "Duration the reminder is postponed or snoozed for in the interactive UI if no duration is provided",
+		get:          func(configs *common.UserConfigs) string { return configs.Notifications.DefaultSnooze },
+		set:          func(configs *common.UserConfigs, value string) { configs.Notifications.DefaultSnooze = value },
+		validate:     validatePositiveDuration,
+	},
+	{
+		key:         "quietHours.from",
+		envVar:      common.QuietHoursFromEnvVar,
+		description: "Start of the daily quiet hours in 24-hours HH:MM format, applied to the weekdays without their own window: the notifications due within them are deferred, dropped or delivered silently depending on the reminder urgency",
+		get:         func(configs *common.UserConfigs) string { return configs.QuietHours.From },
+		set:         func(configs *common.UserConfigs, value string) { configs.QuietHours.From = value },
+		validate:    validateClockTime,
+	},
+	{
+		key:         "quietHours.to",
+		envVar:      common.QuietHoursToEnvVar,
+		description: "End of the daily quiet hours in 24-hours HH:MM format",
+		get:         func(configs *common.UserConfigs) string { return configs.QuietHours.To },
+		set:         func(configs *common.UserConfigs, value string) { configs.QuietHours.To = value },
+		validate:    validateClockTime,
+	},
+	quietHoursWeekdaySetting(time.Monday, common.QuietHoursMondayEnvVar),
+	quietHoursWeekdaySetting(time.Tuesday, common.QuietHoursTuesdayEnvVar),
+	quietHoursWeekdaySetting(time.Wednesday, common.QuietHoursWednesdayEnvVar),
+	quietHoursWeekdaySetting(time.Thursday, common.QuietHoursThursdayEnvVar),
+	quietHoursWeekdaySetting(time.Friday, common.QuietHoursFridayEnvVar),
+	quietHoursWeekdaySetting(time.Saturday, common.QuietHoursSaturdayEnvVar),
+	quietHoursWeekdaySetting(time.Sunday, common.QuietHoursSundayEnvVar),
+	{
+		key:         "quietHours.holidays",
+		envVar:      common.QuietHoursHolidaysEnvVar,
+		description: "Comma-separated dates in YYYY-MM-DD format, which are quiet all day long",
+		get:         func(configs *common.UserConfigs) string { return strings.Join(configs.QuietHours.Holidays, ",") },
+		set: func(configs *common.UserConfigs, value string) {
+			configs.QuietHours.Holidays = splitList(value)
+		},
+		validate: validateHolidays,
+	},
+	{
+		key:          "cleanupInterval",
+		envVar:       common.CleanupIntervalEnvVar,
+		defaultValue: "5m",
+		description:  "How often the expired reminders are deleted, if any",
+		get:          func(configs *common.UserConfigs) string { return configs.CleanupInterval },
+		set:          func(configs *common.UserConfigs, value string) { configs.CleanupInterval = value },
+		validate:     validatePositiveDuration,
+	},
+	{
+		key:          "timeFormat",
+		envVar:       common.TimeFormatEnvVar,
+		defaultValue: common.TimeDisplayFormat24Hours,
+		description:  "Format the time is displayed in: 24h or 12h",
+		get:          func(configs *common.UserConfigs) string { return configs.TimeFormat },
+		set:          func(configs *common.UserConfigs, value string) { configs.TimeFormat = value },
+		validate:     validateOneOf(common.TimeDisplayFormat24Hours, common.TimeDisplayFormat12Hours),
+	},
+	{
+		key:          "logs.level",
+		envVar:       common.LogsLevelEnvVar,
+		defaultValue: strings.ToLower(loggerDefaults.Level.String()),
+		description:  "Minimum level of the log records: debug, info, warn or error",
+		get:          func(configs *common.UserConfigs) string { return configs.Logs.Level },
+		set:          func(configs *common.UserConfigs, value string) { configs.Logs.Level = value },
+		validate: func(value string) error {
+			_, err := logger.ParseLevel(value)
+			return err
+		},
+	},
+	{
+		key:          "logs.format",
+		envVar:       common.LogsFormatEnvVar,
+		defaultValue: loggerDefaults.Format,
+		description:  "Format of the log records: text or json",
+		get:          func(configs *common.UserConfigs) string { return configs.Logs.Format },
+		set:          func(configs *common.UserConfigs, value string) { configs.Logs.Format = value },
+		validate: func(value string) error {
+			_, err := logger.ParseFormat(value)
+			return err
+		},
+	},
+	{
+		key:          "logs.maxSizeMb",
+		envVar:       common.LogsMaxSizeEnvVar,
+		defaultValue: strconv.FormatInt(loggerDefaults.MaxSize/megabyte, 10),
+		description:  "Size of the log file in megabytes it's rotated at",
+		get:          func(configs *common.UserConfigs) string { return formatOptionalInt(configs.Logs.MaxSizeMb) },
+		set:          func(configs *common.UserConfigs, value string) { configs.Logs.MaxSizeMb, _ = strconv.Atoi(value) },
+		validate:     validatePositiveInt,
+	},
+	{
+		key:          "logs.maxAgeDays",
+		envVar:       common.LogsMaxAgeEnvVar,
+		defaultValue: strconv.Itoa(int(loggerDefaults.MaxAge / (24 * time.Hour))),
+		description:  "Age of the log file in days it's rotated at",
+		get:          func(configs *common.UserConfigs) string { return formatOptionalInt(configs.Logs.MaxAgeDays) },
+		set:          func(configs *common.UserConfigs, value string) { configs.Logs.MaxAgeDays, _ = strconv.Atoi(value) },
+		validate:     validatePositiveInt,
+	},
+	{
+		key:          "logs.maxBackups",
+		envVar:       common.LogsMaxBackupsEnvVar,
+		defaultValue: strconv.Itoa(loggerDefaults.MaxBackups),
+		description:  "Number of the rotated log files to keep",
+		get:          func(configs *common.UserConfigs) string { return formatOptionalInt(configs.Logs.MaxBackups) },
+		set:          func(configs *common.UserConfigs, value string) { configs.Logs.MaxBackups, _ = strconv.Atoi(value) },
+		validate:     validatePositiveInt,
+	},
+	{
+		key:          "logs.compress",
+		envVar:       common.LogsCompressEnvVar,
+		defaultValue: strconv.FormatBool(loggerDefaults.Compress),
+		description:  "Whether to gzip the rotated log files: true or false",
+		get: func(configs *common.UserConfigs) string {
+			if configs.Logs.Compress == nil {
+				return ""
+			}
+			return strconv.FormatBool(*configs.Logs.Compress)
+		},
+		set: func(configs *common.UserConfigs, value string) {
+			if value == "" {
+				configs.Logs.Compress = nil
+				return
+			}
+			compress, _ := strconv.ParseBool(value)
+			configs.Logs.Compress = &compress
+		},
+		validate: func(value string) error {
+			_, err := strconv.ParseBool(value)
+			if err != nil {
+				return errors.New("expected either true or false")
+			}
+			return nil
+		},
+	},
+	{
+		key:          "repo.backend",
+		envVar:       common.RepoBackendEnvVar,
+		defaultValue: common.SqliteRepoType,
+		description:  "Storage of the reminders: sqlite or inmemory (the reminders don't survive the app restart), applied on the app restart",
+		get:          func(configs *common.UserConfigs) string { return configs.Repo.Backend },
+		set:          func(configs *common.UserConfigs, value string) { configs.Repo.Backend = value },
+		validate:     validateOneOf(common.SqliteRepoType, common.InMemoryRepoType),
+	},
+}
+
+// FetchUserConfigs returns empty configs if the user hasn't created the configs file.
+// The unknown fields are rejected, so that the typos don't go unnoticed.
+func FetchUserConfigs() (*common.UserConfigs, error) {
+	configsAsBytes, err := os.ReadFile(getUserConfigsFilePath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &common.UserConfigs{}, nil
+		}
+		return nil, err
+	}
+
+	userConfigs := &common.UserConfigs{}
+	decoder := yaml.NewDecoder(bytes.NewReader(configsAsBytes))
+	decoder.KnownFields(true)
+	err = decoder.Decode(userConfigs)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid configs file " + getUserConfigsFilePath() + ": " + err.Error())
+	}
+	return userConfigs, nil
+}
+
+func PersistUserConfigs(userConfigs *common.UserConfigs) error {
+	configsAsBytes, err := yaml.Marshal(userConfigs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getUserConfigsFilePath(), configsAsBytes, 0644)
+}
+
+// ResolveSettings resolves the user configs in the following order:
+// 1. From the environment variables
+// 2. From the user configs file
+// 3. The defaults
+//
+// The invalid values are replaced with the defaults, and reported via the returned error alongside the settings resolved from the valid ones.
+func ResolveSettings() (Settings, error) {
+	values, err := ListConfigs()
+
+	valuesByKey := make(map[string]string, len(values))
+	for _, value := range values {
+		valuesByKey[value.Key] = value.Value
+	}
+
+	// the values are valid at this point, so the parsing errors are not expected
+	resolved := Settings{
+		NotificationBackends: splitList(valuesByKey["notifications.backends"]),
+		TimeFormat:           common.DateTimeFormatWithoutTimeZone,
+		RepoBackend:          valuesByKey["repo.backend"],
+		Logger:               loggerDefaults,
+	}
+	resolved.DefaultSnooze, _ = time.ParseDuration(valuesByKey["notifications.defaultSnooze"])
+	resolved.CleanupInterval, _ = time.ParseDuration(valuesByKey["cleanupInterval"])
+	if valuesByKey["timeFormat"] == common.TimeDisplayFormat12Hours {
+		resolved.TimeFormat = common.DateTimeFormat12AmPmHours
+	}
+
+	resolved.QuietHours = resolveQuietHours(valuesByKey)
+
+	resolved.Logger.Level, _ = logger.ParseLevel(valuesByKey["logs.level"])
+	resolved.Logger.Format, _ = logger.ParseFormat(valuesByKey["logs.format"])
+	maxSizeMb, _ := strconv.Atoi(valuesByKey["logs.maxSizeMb"])
+	resolved.Logger.MaxSize = int64(maxSizeMb) * megabyte
+	maxAgeDays, _ := strconv.Atoi(valuesByKey["logs.maxAgeDays"])
+	resolved.Logger.MaxAge = time.Duration(maxAgeDays) * 24 * time.Hour
+	resolved.Logger.MaxBackups, _ = strconv.Atoi(valuesByKey["logs.maxBackups"])
+	resolved.Logger.Compress, _ = strconv.ParseBool(valuesByKey["logs.compress"])
+
+	return resolved, err
+}
+
+// ListConfigs returns the effective values of all the user configs.
+// The invalid values are replaced with the defaults, and reported via the returned error.
+func ListConfigs() ([]ConfigValue, error) {
+	errs := make([]error, 0)
+
+	userConfigs, err := FetchUserConfigs()
+	if err != nil {
+		errs = append(errs, err)
+		userConfigs = &common.UserConfigs{}
+	}
+
+	values := make([]ConfigValue, 0, len(settings))
+	for _, s := range settings {
+		value := ConfigValue{Key: s.key, Value: s.defaultValue, Source: DefaultSource, EnvVar: s.envVar, Description: s.description}
+
+		if fileValue := s.get(userConfigs); fileValue != "" {
+			err = s.validate(fileValue)
+			if err != nil {
+				errs = append(errs, common.ErrInvalidConfig(s.key, "the configs file", err))
+			} else {
+				value.Value, value.Source = fileValue, FileSource
+			}
+		}
+		if envValue := os.Getenv(s.envVar); envValue != "" {
+			err = s.validate(envValue)
+			if err != nil {
+				errs = append(errs, common.ErrInvalidConfig(s.key, "the env var "+s.envVar, err))
+			} else {
+				value.Value, value.Source = envValue, EnvSource
+			}
+		}
+
+		values = append(values, value)
+	}
+
+	err = validateQuietHours(values)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return values, errors.Join(errs...)
+}
+
+func GetConfig(key string) (*ConfigValue, error) {
+	values, err := ListConfigs()
+	for _, value := range values {
+		if value.Key == key {
+			return &value, err
+		}
+	}
+	return nil, common.ErrUnknownConfig(key)
+}
+
+// SetConfig validates the value and persists it to the configs file, an empty value resets the config to its default
+func SetConfig(key string, value string) error {
+	s, found := findSetting(key)
+	if !found {
+		return common.ErrUnknownConfig(key)
+	}
+
+	if value != "" {
+		err := s.validate(value)
+		if err != nil {
+			return common.ErrInvalidConfig(key, "the command", err)
+		}
+	}
+
+	// the file should be fixed first, otherwise its other values would be lost
+	userConfigs, err := FetchUserConfigs()
+	if err != nil {
+		return err
+	}
+
+	s.set(userConfigs, value)
+	return PersistUserConfigs(userConfigs)
+}
+
+// ValidateConfigs reports all the invalid values of both the configs file and the env vars, and the invalid reminder templates
+func ValidateConfigs() error {
+	_, err := ListConfigs()
+	userConfigs, fetchErr := FetchUserConfigs()
+	if fetchErr != nil {
+		// already reported by ListConfigs
+		return err
+	}
+	return errors.Join(err, validateTemplates(userConfigs))
+}
+
+func ConfigKeys() []string {
+	keys := make([]string, 0, len(settings))
+	for _, s := range settings {
+		keys = append(keys, s.key)
+	}
+	return keys
+}
+
+// quietHoursWeekdaySetting describes the quiet hours window of the weekday, which overrides the daily one
+func quietHoursWeekdaySetting(weekday time.Weekday, envVar string) setting {
+	return setting{
+		key:         quietHoursWeekdayKey(weekday),
+		envVar:      envVar,
+		description: "Quiet hours on " + weekday.String() + " in HH:MM-HH:MM format (e.g. 23:00-09:00), or \"off\" to disable them on that day: overrides the daily quiet hours",
+		get:         func(configs *common.UserConfigs) string { return *configs.QuietHours.Weekday(weekday) },
+		set:         func(configs *common.UserConfigs, value string) { *configs.QuietHours.Weekday(weekday) = value },
+		validate:    validateQuietHoursWindow,
+	}
+}
+
+func quietHoursWeekdayKey(weekday time.Weekday) string {
+	return "quietHours." + strings.ToLower(weekday.String())
+}
+
+// resolveQuietHours returns nil if neither the quiet hours windows nor the holidays are configured
+func resolveQuietHours(valuesByKey map[string]string) *common.QuietHours {
+	var daily *common.QuietHoursWindow
+	from, to := valuesByKey["quietHours.from"], valuesByKey["quietHours.to"]
+	if from != "" && to != "" {
+		daily = &common.QuietHoursWindow{From: parseClockTime(from), To: parseClockTime(to)}
+	}
+
+	quietHours := common.QuietHours{Holidays: make(map[string]bool)}
+	configured := false
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		window := daily
+		if value := valuesByKey[quietHoursWeekdayKey(weekday)]; value != "" {
+			window = parseQuietHoursWindow(value)
+		}
+		quietHours.Weekdays[weekday] = window
+		configured = configured || window != nil
+	}
+	for _, holiday := range splitList(valuesByKey["quietHours.holidays"]) {
+		quietHours.Holidays[holiday] = true
+		configured = true
+	}
+
+	if !configured {
+		return nil
+	}
+	return &quietHours
+}
+
+func findSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// validateQuietHours checks that the quiet hours are either fully configured or not at all, and disables them otherwise
+func validateQuietHours(values []ConfigValue) error {
+	var from, to *ConfigValue
+	for i := range values {
+		switch values[i].Key {
+		case "quietHours.from":
+			from = &values[i]
+		case "quietHours.to":
+			to = &values[i]
+		}
+	}
+
+	var err error
+	if (from.Value == "") != (to.Value == "") {
+		err = common.ErrInvalidConfig("quietHours", "the configs file or env vars", errors.New("both from and to should be provided"))
+	} else if from.Value != "" && parseClockTime(from.Value) == parseClockTime(to.Value) {
+		err = common.ErrInvalidConfig("quietHours", "the configs file or env vars", errors.New("from and to should be different"))
+	}
+
+	if err != nil {
+		from.Value, from.Source = "", DefaultSource
+		to.Value, to.Source = "", DefaultSource
+	}
+	return err
+}
+
+func validateQuietHoursWindow(value string) error {
+	if value == common.QuietHoursOff {
+		return nil
+	}
+
+	from, to, found := strings.Cut(value, "-")
+	if !found || validateClockTime(from) != nil || validateClockTime(to) != nil {
+		return errors.New("expected either time range in HH:MM-HH:MM format (e.g. 23:00-09:00), or \"off\"")
+	}
+	if parseClockTime(from) == parseClockTime(to) {
+		return errors.New("the start and the end of the time range should be different")
+	}
+	return nil
+}
+
+func validateHolidays(value string) error {
+	for _, holiday := range splitList(value) {
+		_, err := time.Parse(common.DateFormat, holiday)
+		if err != nil {
+			return errors.New("expected comma-separated dates in YYYY-MM-DD format: e.g. 2026-12-25,2027-01-01")
+		}
+	}
+	return nil
+}
+
+func validateNotificationBackends(value string) error {
+	backends := splitList(value)
+	if len(backends) == 0 {
+		return errors.New("at least one backend should be provided")
+	}
+
+	validateBackend := validateOneOf(common.DesktopNotificationBackend, common.AlertNotificationBackend, common.BeepNotificationBackend)
+	for _, backend := range backends {
+		err := validateBackend(backend)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePositiveDuration(value string) error {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return errors.New("expected a positive duration: e.g. 30s, 10m, 1h30m")
+	}
+	return nil
+}
+
+func validatePositiveInt(value string) error {
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return errors.New("expected a positive integer")
+	}
+	return nil
+}
+
+func validateClockTime(value string) error {
+	_, err := time.Parse(common.TimeFormat24Hours, value+":00")
+	if err != nil {
+		return errors.New("expected time in 24-hours HH:MM format: e.g. 22:00, 07:30")
+	}
+	return nil
+}
+
+func validateOneOf(allowed ...string) func(value string) error {
+	return func(value string) error {
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		return errors.New("expected one of: " + strings.Join(allowed, ", "))
+	}
+}
+
+// parseQuietHoursWindow returns nil for the valid window that disables the quiet hours
+func parseQuietHoursWindow(value string) *common.QuietHoursWindow {
+	if value == common.QuietHoursOff {
+		return nil
+	}
+
+	from, to, _ := strings.Cut(value, "-")
+	return &common.QuietHoursWindow{From: parseClockTime(from), To: parseClockTime(to)}
+}
+
+// parseClockTime returns the minutes since midnight of the valid HH:MM time
+func parseClockTime(value string) int {
+	t, _ := time.Parse(common.TimeFormat24Hours, value+":00")
+	return t.Hour()*60 + t.Minute()
+}
+
+func splitList(value string) []string {
+	list := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func formatOptionalInt(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+func getUserConfigsFilePath() string {
+	return utils.GetOsSpecificAppDataDir() + common.UserConfigsFileName
+}
@@ -2,23 +2,62 @@ package httpclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
 	"n0rdy.foo/remindme/logger"
+	"net"
 	"net/http"
 	"strconv"
+	"sync/atomic"
+	"time"
 )
 
+const healthcheckTimeout = time.Second
+
+// the ID of all the requests sent within the current CLI invocation, so that the client and server logs can be matched up
+var requestId = logger.NewRequestId()
+
+// atomic, as the requests are sent concurrently: e.g. by the interactive UI poller and its key handler
+var requestSent atomic.Bool
+
 type RemindmeHttpClient struct {
 	httpClient http.Client
 	serverUrl  string
+	apiToken   string
 }
 
-func NewHttpClient(port int) RemindmeHttpClient {
+func NewHttpClient(address common.ServerAddress) RemindmeHttpClient {
+	apiToken, err := config.FetchApiToken()
+	if err != nil {
+		// the requests will be rejected by the server, but the healthcheck will still work
+		logger.Error("NewHttpClient: error while fetching API token", err)
+	}
+
+	if address.Transport == common.UnixTransport {
+		return RemindmeHttpClient{
+			httpClient: http.Client{
+				Transport: apiTokenRefreshingTransport{
+					base: &http.Transport{
+						// the host part of the URL is ignored, as every connection is made to the socket
+						DialContext: func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+							var dialer net.Dialer
+							return dialer.DialContext(ctx, "unix", address.SocketPath)
+						},
+					},
+				},
+			},
+			serverUrl: "http://localhost",
+			apiToken:  apiToken,
+		}
+	}
+
 	return RemindmeHttpClient{
-		httpClient: http.Client{},
-		serverUrl:  "http://localhost:" + strconv.Itoa(port),
+		httpClient: http.Client{Transport: apiTokenRefreshingTransport{base: http.DefaultTransport}},
+		serverUrl:  "http://localhost:" + strconv.Itoa(address.Port),
+		apiToken:   apiToken,
 	}
 }
 
@@ -29,11 +68,23 @@ func (rhc *RemindmeHttpClient) CreateReminder(reminder common.Reminder) error {
 		return common.ErrHttpInternal
 	}
 
-	resp, err := rhc.httpClient.Post(rhc.serverUrl+"/api/v1/reminders", "application/json", bytes.NewReader(reqBody))
+	req, err := rhc.newRequest(http.MethodPost, "/api/v1/reminders", bytes.NewReader(reqBody))
+	if err != nil {
+		logger.Error("CreateReminder request: unexpected error happened on preparing POST HTTP request", err)
+		return common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
 	if err != nil {
 		logger.Error("CreateReminder request: unexpected error happened on POST HTTP call", err)
 		return common.ErrHttpOnCallingServer
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("CreateReminder request: API token rejected by the server")
+		return common.ErrHttpUnauthorized
+	}
 	if resp.StatusCode != http.StatusOK {
 		logger.Error("CreateReminder request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
 		return common.ErrHttpOnSettingUpReminder
@@ -41,14 +92,30 @@ func (rhc *RemindmeHttpClient) CreateReminder(reminder common.Reminder) error {
 	return nil
 }
 
+// SetTimeout makes the requests give up after the timeout: e.g. for the shell completion, which shouldn't hang if the server doesn't respond
+func (rhc *RemindmeHttpClient) SetTimeout(timeout time.Duration) {
+	rhc.httpClient.Timeout = timeout
+}
+
 func (rhc *RemindmeHttpClient) GetAllReminders() ([]common.Reminder, error) {
-	resp, err := rhc.httpClient.Get(rhc.serverUrl + "/api/v1/reminders")
+	req, err := rhc.newRequest(http.MethodGet, "/api/v1/reminders", nil)
+	if err != nil {
+		logger.Error("GetAllReminders request: unexpected error happened on preparing GET HTTP request", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
 	if err != nil {
 		logger.Error("GetAllReminders request: unexpected error happened on GET HTTP call", err)
 		return nil, common.ErrHttpOnCallingServer
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("GetAllReminders request: API token rejected by the server")
+		return nil, common.ErrHttpUnauthorized
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		logger.Error("GetAllReminders request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
 		return nil, common.ErrHttpOnGettingAllReminders
@@ -70,7 +137,7 @@ func (rhc *RemindmeHttpClient) GetAllReminders() ([]common.Reminder, error) {
 }
 
 func (rhc *RemindmeHttpClient) DeleteAllReminders() error {
-	req, err := http.NewRequest(http.MethodDelete, rhc.serverUrl+"/api/v1/reminders", nil)
+	req, err := rhc.newRequest(http.MethodDelete, "/api/v1/reminders", nil)
 	if err != nil {
 		logger.Error("DeleteAllReminders request: unexpected error happened on preparing DELETE HTTP request", err)
 		return common.ErrHttpInternal
@@ -81,6 +148,12 @@ func (rhc *RemindmeHttpClient) DeleteAllReminders() error {
 		logger.Error("DeleteAllReminders request: unexpected error happened on DELETE HTTP call", err)
 		return common.ErrHttpOnCallingServer
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("DeleteAllReminders request: API token rejected by the server")
+		return common.ErrHttpUnauthorized
+	}
 	if resp.StatusCode != http.StatusOK {
 		logger.Error("DeleteAllReminders request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
 		return common.ErrHttpOnDeletingAllReminders
@@ -89,13 +162,24 @@ func (rhc *RemindmeHttpClient) DeleteAllReminders() error {
 }
 
 func (rhc *RemindmeHttpClient) GetReminder(id int) (*common.Reminder, error) {
-	resp, err := rhc.httpClient.Get(rhc.serverUrl + "/api/v1/reminders/" + strconv.Itoa(id))
+	req, err := rhc.newRequest(http.MethodGet, "/api/v1/reminders/"+strconv.Itoa(id), nil)
+	if err != nil {
+		logger.Error("GetReminder request: unexpected error happened on preparing GET HTTP request", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
 	if err != nil {
 		logger.Error("GetReminder request: unexpected error happened on GET HTTP call", err)
 		return nil, common.ErrHttpOnCallingServer
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("GetReminder request: API token rejected by the server")
+		return nil, common.ErrHttpUnauthorized
+	}
+
 	if resp.StatusCode == http.StatusNotFound {
 		logger.Error("GetReminder request: reminder not found by ID: "+strconv.Itoa(id), err)
 		return nil, common.ErrHttpReminderNotFound
@@ -121,7 +205,7 @@ func (rhc *RemindmeHttpClient) GetReminder(id int) (*common.Reminder, error) {
 }
 
 func (rhc *RemindmeHttpClient) DeleteReminder(id int) error {
-	req, err := http.NewRequest(http.MethodDelete, rhc.serverUrl+"/api/v1/reminders/"+strconv.Itoa(id), nil)
+	req, err := rhc.newRequest(http.MethodDelete, "/api/v1/reminders/"+strconv.Itoa(id), nil)
 	if err != nil {
 		logger.Error("DeleteReminder request: unexpected error happened on preparing DELETE HTTP request", err)
 		return common.ErrHttpInternal
@@ -132,6 +216,12 @@ func (rhc *RemindmeHttpClient) DeleteReminder(id int) error {
 		logger.Error("DeleteReminder request: unexpected error happened on DELETE HTTP call", err)
 		return common.ErrHttpOnCallingServer
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("DeleteReminder request: API token rejected by the server")
+		return common.ErrHttpUnauthorized
+	}
 	if resp.StatusCode == http.StatusNotFound {
 		logger.Error("DeleteReminder request: reminder not found by ID: "+strconv.Itoa(id), err)
 		return common.ErrHttpReminderNotFound
@@ -150,7 +240,7 @@ func (rhc *RemindmeHttpClient) ChangeReminder(id int, reminderModifications comm
 		return common.ErrHttpInternal
 	}
 
-	req, err := http.NewRequest(http.MethodPut, rhc.serverUrl+"/api/v1/reminders/"+strconv.Itoa(id), bytes.NewReader(reqBody))
+	req, err := rhc.newRequest(http.MethodPut, "/api/v1/reminders/"+strconv.Itoa(id), bytes.NewReader(reqBody))
 	if err != nil {
 		logger.Error("ChangeReminder request: unexpected error happened on preparing PUT HTTP request", err)
 		return common.ErrHttpInternal
@@ -161,6 +251,12 @@ func (rhc *RemindmeHttpClient) ChangeReminder(id int, reminderModifications comm
 		logger.Error("ChangeReminder request: unexpected error happened on PUT HTTP call", err)
 		return common.ErrHttpOnCallingServer
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("ChangeReminder request: API token rejected by the server")
+		return common.ErrHttpUnauthorized
+	}
 	if resp.StatusCode != http.StatusOK {
 		logger.Error("ChangeReminder request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
 		return common.ErrHttpOnChangingReminder
@@ -168,8 +264,225 @@ func (rhc *RemindmeHttpClient) ChangeReminder(id int, reminderModifications comm
 	return nil
 }
 
+// ApplyBatch applies all the operations at once: if any of them fails, none is applied
+func (rhc *RemindmeHttpClient) ApplyBatch(batch common.BatchRequest) error {
+	reqBody, err := json.Marshal(batch)
+	if err != nil {
+		logger.Error("ApplyBatch request: unexpected error happened on encoding request body", err)
+		return common.ErrHttpInternal
+	}
+
+	req, err := rhc.newRequest(http.MethodPost, "/api/v1/reminders:batch", bytes.NewReader(reqBody))
+	if err != nil {
+		logger.Error("ApplyBatch request: unexpected error happened on preparing POST HTTP request", err)
+		return common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("ApplyBatch request: unexpected error happened on POST HTTP call", err)
+		return common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("ApplyBatch request: API token rejected by the server")
+		return common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		// e.g. the reminder has been notified about or cancelled in the meantime
+		logger.Error("ApplyBatch request: reminder not found")
+		return common.ErrHttpReminderNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("ApplyBatch request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return common.ErrHttpOnApplyingBatch
+	}
+	return nil
+}
+
+func (rhc *RemindmeHttpClient) GetStatus() (*common.Status, error) {
+	req, err := rhc.newRequest(http.MethodGet, "/api/v1/status", nil)
+	if err != nil {
+		logger.Error("GetStatus request: unexpected error happened on preparing GET HTTP request", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("GetStatus request: unexpected error happened on GET HTTP call", err)
+		return nil, common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("GetStatus request: API token rejected by the server")
+		return nil, common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("GetStatus request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return nil, common.ErrHttpOnGettingStatus
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Error("GetStatus request: unexpected error happened on response body reading", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	status := common.Status{}
+	err = json.Unmarshal(respBody, &status)
+	if err != nil {
+		logger.Error("GetStatus request: unexpected error happened on response body decoding", err)
+		return nil, common.ErrHttpInternal
+	}
+	return &status, nil
+}
+
+func (rhc *RemindmeHttpClient) GetNotifications() ([]common.NotificationResult, error) {
+	req, err := rhc.newRequest(http.MethodGet, "/api/v1/notifications", nil)
+	if err != nil {
+		logger.Error("GetNotifications request: unexpected error happened on preparing GET HTTP request", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("GetNotifications request: unexpected error happened on GET HTTP call", err)
+		return nil, common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("GetNotifications request: API token rejected by the server")
+		return nil, common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("GetNotifications request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return nil, common.ErrHttpOnGettingNotifications
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Error("GetNotifications request: unexpected error happened on response body reading", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	notifications := make([]common.NotificationResult, 0)
+	err = json.Unmarshal(respBody, &notifications)
+	if err != nil {
+		logger.Error("GetNotifications request: unexpected error happened on response body decoding", err)
+		return nil, common.ErrHttpInternal
+	}
+	return notifications, nil
+}
+
+func (rhc *RemindmeHttpClient) GetJournal() ([]common.JournalEntry, error) {
+	req, err := rhc.newRequest(http.MethodGet, "/api/v1/journal", nil)
+	if err != nil {
+		logger.Error("GetJournal request: unexpected error happened on preparing GET HTTP request", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("GetJournal request: unexpected error happened on GET HTTP call", err)
+		return nil, common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("GetJournal request: API token rejected by the server")
+		return nil, common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("GetJournal request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return nil, common.ErrHttpOnGettingJournal
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Error("GetJournal request: unexpected error happened on response body reading", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	journal := make([]common.JournalEntry, 0)
+	err = json.Unmarshal(respBody, &journal)
+	if err != nil {
+		logger.Error("GetJournal request: unexpected error happened on response body decoding", err)
+		return nil, common.ErrHttpInternal
+	}
+	return journal, nil
+}
+
+// Undo undoes the journal entry with the ID, or the latest one if the ID is 0, and returns the undone entry
+func (rhc *RemindmeHttpClient) Undo(entryId int64) (*common.JournalEntry, error) {
+	reqBody, err := json.Marshal(common.UndoRequest{ID: entryId})
+	if err != nil {
+		logger.Error("Undo request: unexpected error happened on encoding request body", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	req, err := rhc.newRequest(http.MethodPost, "/api/v1/journal:undo", bytes.NewReader(reqBody))
+	if err != nil {
+		logger.Error("Undo request: unexpected error happened on preparing POST HTTP request", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Undo request: unexpected error happened on POST HTTP call", err)
+		return nil, common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("Undo request: API token rejected by the server")
+		return nil, common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		logger.Error("Undo request: journal entry not found by ID " + strconv.FormatInt(entryId, 10))
+		if entryId == 0 {
+			return nil, common.ErrHttpNothingToUndo
+		}
+		return nil, common.ErrHttpJournalEntryNotFound
+	}
+	if resp.StatusCode == http.StatusConflict {
+		logger.Error("Undo request: journal entry " + strconv.FormatInt(entryId, 10) + " has been followed by the operations on the same reminders")
+		return nil, common.ErrHttpJournalEntryConflict
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Undo request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return nil, common.ErrHttpOnUndoing
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Error("Undo request: unexpected error happened on response body reading", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	var entry common.JournalEntry
+	err = json.Unmarshal(respBody, &entry)
+	if err != nil {
+		logger.Error("Undo request: unexpected error happened on response body decoding", err)
+		return nil, common.ErrHttpInternal
+	}
+	return &entry, nil
+}
+
+// Healthcheck reports whether the server responds: it gives up after a short timeout, as it's used to poll the server
 func (rhc *RemindmeHttpClient) Healthcheck() bool {
-	resp, err := rhc.httpClient.Get(rhc.serverUrl + "/healthcheck")
+	req, err := rhc.newRequest(http.MethodGet, "/healthcheck", nil)
+	if err != nil {
+		logger.Error("Healthcheck request: unexpected error happened on preparing GET HTTP request", err)
+		return false
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), healthcheckTimeout)
+	defer cancel()
+	req = req.WithContext(ctx)
+
+	resp, err := rhc.httpClient.Do(req)
 	if err != nil {
 		logger.Error("Healthcheck request: unexpected error happened on GET HTTP call", err)
 		return false
@@ -180,7 +493,7 @@ func (rhc *RemindmeHttpClient) Healthcheck() bool {
 }
 
 func (rhc *RemindmeHttpClient) StopServer() error {
-	req, err := http.NewRequest(http.MethodDelete, rhc.serverUrl+"/shutdown", nil)
+	req, err := rhc.newRequest(http.MethodDelete, "/shutdown", nil)
 	if err != nil {
 		logger.Error("StopServer request: unexpected error happened on preparing DELETE HTTP request", err)
 		return common.ErrHttpInternal
@@ -191,9 +504,301 @@ func (rhc *RemindmeHttpClient) StopServer() error {
 		logger.Error("StopServer request: unexpected error happened on DELETE HTTP call", err)
 		return common.ErrHttpOnCallingServer
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("StopServer request: API token rejected by the server")
+		return common.ErrHttpUnauthorized
+	}
 	if resp.StatusCode != http.StatusOK {
 		logger.Error("StopServer request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
 		return common.ErrHttpOnTerminatingApp
 	}
 	return nil
 }
+
+func (rhc *RemindmeHttpClient) ReloadConfigs() error {
+	req, err := rhc.newRequest(http.MethodPost, "/api/v1/configs:reload", nil)
+	if err != nil {
+		logger.Error("ReloadConfigs request: unexpected error happened on preparing POST HTTP request", err)
+		return common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("ReloadConfigs request: unexpected error happened on POST HTTP call", err)
+		return common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("ReloadConfigs request: API token rejected by the server")
+		return common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode == http.StatusBadRequest {
+		logger.Error("ReloadConfigs request: configs rejected by the server")
+		return common.ErrHttpInvalidConfigs
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("ReloadConfigs request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return common.ErrHttpOnReloadingConfigs
+	}
+	return nil
+}
+
+func (rhc *RemindmeHttpClient) EnableDnd(until time.Time) error {
+	reqBody, err := json.Marshal(common.Dnd{Until: until})
+	if err != nil {
+		logger.Error("EnableDnd request: unexpected error happened on encoding request body", err)
+		return common.ErrHttpInternal
+	}
+
+	req, err := rhc.newRequest(http.MethodPut, "/api/v1/dnd", bytes.NewReader(reqBody))
+	if err != nil {
+		logger.Error("EnableDnd request: unexpected error happened on preparing PUT HTTP request", err)
+		return common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("EnableDnd request: unexpected error happened on PUT HTTP call", err)
+		return common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("EnableDnd request: API token rejected by the server")
+		return common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("EnableDnd request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return common.ErrHttpOnEnablingDnd
+	}
+	return nil
+}
+
+func (rhc *RemindmeHttpClient) DisableDnd() error {
+	req, err := rhc.newRequest(http.MethodDelete, "/api/v1/dnd", nil)
+	if err != nil {
+		logger.Error("DisableDnd request: unexpected error happened on preparing DELETE HTTP request", err)
+		return common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("DisableDnd request: unexpected error happened on DELETE HTTP call", err)
+		return common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("DisableDnd request: API token rejected by the server")
+		return common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("DisableDnd request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return common.ErrHttpOnDisablingDnd
+	}
+	return nil
+}
+
+// StartPomodoro schedules the whole chain of the work and break reminders, and returns the started session
+func (rhc *RemindmeHttpClient) StartPomodoro(pomodoroReq common.PomodoroRequest) (*common.Pomodoro, error) {
+	reqBody, err := json.Marshal(pomodoroReq)
+	if err != nil {
+		logger.Error("StartPomodoro request: unexpected error happened on encoding request body", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	req, err := rhc.newRequest(http.MethodPut, "/api/v1/pomodoro", bytes.NewReader(reqBody))
+	if err != nil {
+		logger.Error("StartPomodoro request: unexpected error happened on preparing PUT HTTP request", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("StartPomodoro request: unexpected error happened on PUT HTTP call", err)
+		return nil, common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("StartPomodoro request: API token rejected by the server")
+		return nil, common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode == http.StatusConflict {
+		logger.Error("StartPomodoro request: the pomodoro session is running already")
+		return nil, common.ErrHttpPomodoroRunning
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("StartPomodoro request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return nil, common.ErrHttpOnStartingPomodoro
+	}
+	return decodePomodoro("StartPomodoro", resp)
+}
+
+// GetPomodoro returns the running session with the intervals left, the current one first
+func (rhc *RemindmeHttpClient) GetPomodoro() (*common.Pomodoro, error) {
+	req, err := rhc.newRequest(http.MethodGet, "/api/v1/pomodoro", nil)
+	if err != nil {
+		logger.Error("GetPomodoro request: unexpected error happened on preparing GET HTTP request", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("GetPomodoro request: unexpected error happened on GET HTTP call", err)
+		return nil, common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("GetPomodoro request: API token rejected by the server")
+		return nil, common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		logger.Error("GetPomodoro request: no pomodoro session is running")
+		return nil, common.ErrHttpPomodoroNotRunning
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("GetPomodoro request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return nil, common.ErrHttpOnGettingPomodoro
+	}
+	return decodePomodoro("GetPomodoro", resp)
+}
+
+// SkipPomodoroInterval ends the current interval, and returns the session with the intervals left: none if the last one has been skipped
+func (rhc *RemindmeHttpClient) SkipPomodoroInterval() (*common.Pomodoro, error) {
+	req, err := rhc.newRequest(http.MethodPost, "/api/v1/pomodoro:skip", nil)
+	if err != nil {
+		logger.Error("SkipPomodoroInterval request: unexpected error happened on preparing POST HTTP request", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("SkipPomodoroInterval request: unexpected error happened on POST HTTP call", err)
+		return nil, common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("SkipPomodoroInterval request: API token rejected by the server")
+		return nil, common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		logger.Error("SkipPomodoroInterval request: no pomodoro session is running")
+		return nil, common.ErrHttpPomodoroNotRunning
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("SkipPomodoroInterval request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return nil, common.ErrHttpOnSkippingPomodoro
+	}
+	return decodePomodoro("SkipPomodoroInterval", resp)
+}
+
+// StopPomodoro cancels the rest of the chain, and returns the session with the canceled intervals
+func (rhc *RemindmeHttpClient) StopPomodoro() (*common.Pomodoro, error) {
+	req, err := rhc.newRequest(http.MethodDelete, "/api/v1/pomodoro", nil)
+	if err != nil {
+		logger.Error("StopPomodoro request: unexpected error happened on preparing DELETE HTTP request", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	resp, err := rhc.httpClient.Do(req)
+	if err != nil {
+		logger.Error("StopPomodoro request: unexpected error happened on DELETE HTTP call", err)
+		return nil, common.ErrHttpOnCallingServer
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		logger.Error("StopPomodoro request: API token rejected by the server")
+		return nil, common.ErrHttpUnauthorized
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		logger.Error("StopPomodoro request: no pomodoro session is running")
+		return nil, common.ErrHttpPomodoroNotRunning
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("StopPomodoro request: unexpected status code received: " + strconv.Itoa(resp.StatusCode))
+		return nil, common.ErrHttpOnStoppingPomodoro
+	}
+	return decodePomodoro("StopPomodoro", resp)
+}
+
+func decodePomodoro(requestName string, resp *http.Response) (*common.Pomodoro, error) {
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Error(requestName+" request: unexpected error happened on response body reading", err)
+		return nil, common.ErrHttpInternal
+	}
+
+	var pomodoro common.Pomodoro
+	err = json.Unmarshal(respBody, &pomodoro)
+	if err != nil {
+		logger.Error(requestName+" request: unexpected error happened on response body decoding", err)
+		return nil, common.ErrHttpInternal
+	}
+	return &pomodoro, nil
+}
+
+// RequestId returns the ID the requests of the current CLI invocation are sent with
+func RequestId() string {
+	return requestId
+}
+
+// RequestSent reports whether any request has been sent to the server within the current CLI invocation
+func RequestSent() bool {
+	return requestSent.Load()
+}
+
+// newRequest prepares the request to the server with the API token and request ID attached
+func (rhc *RemindmeHttpClient) newRequest(method string, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, rhc.serverUrl+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if rhc.apiToken != "" {
+		req.Header.Set(common.ApiTokenHeader, common.ApiTokenHeaderPrefix+rhc.apiToken)
+	}
+	req.Header.Set(common.RequestIdHeader, requestId)
+	requestSent.Store(true)
+	return req, nil
+}
+
+// apiTokenRefreshingTransport retries the request rejected by the server with the API token that has been regenerated in the meantime:
+// e.g. if the server has been started by the request itself via systemd socket activation
+type apiTokenRefreshingTransport struct {
+	base http.RoundTripper
+}
+
+func (t apiTokenRefreshingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	apiToken, tokenErr := config.FetchApiToken()
+	if tokenErr != nil || apiToken == "" || req.Header.Get(common.ApiTokenHeader) == common.ApiTokenHeaderPrefix+apiToken {
+		return resp, nil
+	}
+
+	retryReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retryReq.Body, err = req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+	}
+	retryReq.Header.Set(common.ApiTokenHeader, common.ApiTokenHeaderPrefix+apiToken)
+
+	logger.Info("API token rejected by the server - retrying with the regenerated one")
+	resp.Body.Close()
+	return t.base.RoundTrip(retryReq)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"crypto/subtle"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/httpserver/metrics"
+	"n0rdy.foo/remindme/logger"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the route label of the requests that haven't been matched with any route, e.g. rejected by the middlewares or not found
+const unmatchedRoute = "unmatched"
+
+// the server is bound to the localhost, so any other host means that the request has been sent via DNS rebinding
+var allowedHosts = map[string]bool{
+	"localhost": true,
+	"127.0.0.1": true,
+	"::1":       true,
+}
+
+// the request IDs are generated by the client, so the ones that are too long or contain unexpected characters are replaced
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// assignRequestId attaches the request ID sent by the client to the request context, so that all the records logged
+// while processing the request are tagged with it. If the client hasn't sent a valid one, a new ID is generated.
+func (rmr *RemindMeRouter) assignRequestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestId := req.Header.Get(common.RequestIdHeader)
+		if !validRequestId.MatchString(requestId) {
+			requestId = logger.NewRequestId()
+		}
+
+		w.Header().Set(common.RequestIdHeader, requestId)
+		next.ServeHTTP(w, req.WithContext(logger.WithRequestId(req.Context(), requestId)))
+	})
+}
+
+// instrument collects the HTTP metrics per route pattern rather than per URL path to keep the metrics cardinality low
+func (rmr *RemindMeRouter) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
+
+		next.ServeHTTP(ww, req)
+
+		route := chi.RouteContext(req.Context()).RoutePattern()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := ww.Status()
+		if status == 0 {
+			// nothing has been written explicitly
+			status = http.StatusOK
+		}
+
+		metrics.HttpRequests.Inc(req.Method, route, strconv.Itoa(status))
+		metrics.HttpRequestDuration.Observe(time.Since(start).Seconds(), req.Method, route)
+	})
+}
+
+func (rmr *RemindMeRouter) verifyHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !allowedHosts[hostname(req.Host)] {
+			logger.ErrorContext(req.Context(), "verifyHost middleware: request rejected due to the forbidden host: "+req.Host)
+			rmr.sendErrorResponse(w, http.StatusForbidden, common.ErrCodeForbiddenHost)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// verifyOrigin rejects the requests sent by the browser pages that are not served from the localhost.
+// Non-browser clients (including the remindme CLI) don't send the Origin header at all.
+func (rmr *RemindMeRouter) verifyOrigin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get("Origin")
+		if origin != "" {
+			originUrl, err := url.Parse(origin)
+			if err != nil || !allowedHosts[originUrl.Hostname()] {
+				logger.ErrorContext(req.Context(), "verifyOrigin middleware: request rejected due to the forbidden origin: "+origin)
+				rmr.sendErrorResponse(w, http.StatusForbidden, common.ErrCodeForbiddenOrigin)
+				return
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (rmr *RemindMeRouter) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		token := strings.TrimPrefix(req.Header.Get(common.ApiTokenHeader), common.ApiTokenHeaderPrefix)
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(rmr.apiToken)) != 1 {
+			logger.ErrorContext(req.Context(), "authenticate middleware: request rejected due to missing or invalid API token: "+req.Method+" "+req.URL.Path)
+			rmr.sendErrorResponse(w, http.StatusUnauthorized, common.ErrCodeUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func hostname(hostWithPort string) string {
+	host, _, err := net.SplitHostPort(hostWithPort)
+	if err != nil {
+		// no port provided
+		host = hostWithPort
+	}
+	return strings.Trim(host, "[]")
+}
@@ -1,156 +1,192 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/go-chi/chi/v5"
+	"io"
 	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/httpserver/metrics"
 	"n0rdy.foo/remindme/httpserver/service"
 	"n0rdy.foo/remindme/logger"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type RemindMeRouter struct {
-	service    *service.ReminderService
-	shutdownCh chan struct{}
+	service       *service.ReminderService
+	shutdownCh    chan struct{}
+	apiToken      string
+	serverInfo    common.ServerInfo
+	reloadConfigs func(ctx context.Context) error
 }
 
-func NewRemindMeRouter(service *service.ReminderService, shutdownCh chan struct{}) RemindMeRouter {
-	return RemindMeRouter{service: service, shutdownCh: shutdownCh}
+func NewRemindMeRouter(service *service.ReminderService, shutdownCh chan struct{}, apiToken string, serverInfo common.ServerInfo, reloadConfigs func(ctx context.Context) error) RemindMeRouter {
+	return RemindMeRouter{service: service, shutdownCh: shutdownCh, apiToken: apiToken, serverInfo: serverInfo, reloadConfigs: reloadConfigs}
 }
 
 func (rmr *RemindMeRouter) NewRouter() *chi.Mux {
 	router := chi.NewRouter()
+	router.Use(rmr.assignRequestId, rmr.instrument, rmr.verifyHost, rmr.verifyOrigin)
 
-	router.Route("/api/v1", func(r chi.Router) {
-		r.Route("/reminders", func(r chi.Router) {
-			r.Get("/", rmr.getAllReminders)
-			r.Post("/", rmr.createNewReminder)
-			r.Delete("/", rmr.deleteAllReminders)
-			r.Get("/{id}", rmr.getReminder)
-			r.Delete("/{id}", rmr.deleteReminder)
-			r.Put("/{id}", rmr.changeReminder)
+	// healthcheck doesn't expose any data, so it's available without the API token:
+	// this way, the client can detect the running server even if its token is outdated
+	router.Get("/healthcheck", rmr.healthCheck)
+	// same for the metrics, which are aggregated and don't contain reminders data,
+	// so that they can be scraped by Prometheus without the token that changes on every server start
+	router.Get("/metrics", rmr.metrics)
+
+	router.Group(func(r chi.Router) {
+		r.Use(rmr.authenticate)
+
+		r.Route("/api/v1", func(r chi.Router) {
+			r.Route("/reminders", func(r chi.Router) {
+				r.Get("/", rmr.getAllReminders)
+				r.Post("/", rmr.createNewReminder)
+				r.Delete("/", rmr.deleteAllReminders)
+				r.Get("/{id}", rmr.getReminder)
+				r.Delete("/{id}", rmr.deleteReminder)
+				r.Put("/{id}", rmr.changeReminder)
+			})
+			r.Post("/reminders:batch", rmr.applyBatch)
+			r.Get("/status", rmr.getStatus)
+			r.Get("/notifications", rmr.getNotifications)
+			r.Get("/journal", rmr.getJournal)
+			r.Post("/journal:undo", rmr.undo)
+			r.Post("/configs:reload", rmr.reloadUserConfigs)
+			r.Put("/dnd", rmr.enableDnd)
+			r.Delete("/dnd", rmr.disableDnd)
+			r.Get("/pomodoro", rmr.getPomodoro)
+			r.Put("/pomodoro", rmr.startPomodoro)
+			r.Delete("/pomodoro", rmr.stopPomodoro)
+			r.Post("/pomodoro:skip", rmr.skipPomodoroInterval)
 		})
-	})
 
-	router.Get("/healthcheck", rmr.healthCheck)
-	router.Delete("/shutdown", rmr.shutdown)
+		r.Delete("/shutdown", rmr.shutdown)
+	})
 
 	return router
 }
 
 func (rmr *RemindMeRouter) getAllReminders(w http.ResponseWriter, req *http.Request) {
-	logger.Info("getAllReminders request: received")
+	logger.InfoContext(req.Context(), "getAllReminders request: received")
 
-	reminders, err := rmr.service.GetAll()
+	reminders, err := rmr.service.GetAll(req.Context())
 	if err != nil {
-		logger.Error("getAllReminders request: unexpected error happened on reminders fetching", err)
+		logger.ErrorContext(req.Context(), "getAllReminders request: unexpected error happened on reminders fetching", err)
 		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
 		return
 	}
 	rmr.sendJsonResponse(w, http.StatusOK, reminders)
 
-	logger.Info("getAllReminders request: successfully processed")
+	logger.InfoContext(req.Context(), "getAllReminders request: successfully processed")
 }
 
 func (rmr *RemindMeRouter) createNewReminder(w http.ResponseWriter, req *http.Request) {
-	logger.Info("createNewReminder request: received")
+	logger.InfoContext(req.Context(), "createNewReminder request: received")
 
 	var reminder common.Reminder
 	err := json.NewDecoder(req.Body).Decode(&reminder)
 	if err != nil {
-		logger.Error("createNewReminder request: unexpected error happened on request body decoding", err)
+		logger.ErrorContext(req.Context(), "createNewReminder request: unexpected error happened on request body decoding", err)
 		rmr.sendErrorResponse(w, http.StatusBadRequest, common.ErrCodeRequestBody)
 		return
 	}
+	if code := rmr.validateReminder(reminder); code != "" {
+		logger.ErrorContext(req.Context(), "createNewReminder request: invalid reminder provided: "+code)
+		rmr.sendErrorResponse(w, http.StatusBadRequest, code)
+		return
+	}
 
-	err = rmr.service.Set(reminder)
+	err = rmr.service.Set(req.Context(), reminder)
 	if err != nil {
-		logger.Error("createNewReminder request: unexpected error happened on reminder setting", err)
+		logger.ErrorContext(req.Context(), "createNewReminder request: unexpected error happened on reminder setting", err)
 		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
 		return
 	}
 
 	rmr.sendOkEmptyResponse(w)
 
-	logger.Info("createNewReminder request: successfully processed")
+	logger.InfoContext(req.Context(), "createNewReminder request: successfully processed")
 }
 
 func (rmr *RemindMeRouter) deleteAllReminders(w http.ResponseWriter, req *http.Request) {
-	logger.Info("deleteAllReminders request: received")
+	logger.InfoContext(req.Context(), "deleteAllReminders request: received")
 
-	err := rmr.service.CancelAll()
+	err := rmr.service.CancelAll(req.Context())
 	if err != nil {
-		logger.Error("deleteAllReminders request: unexpected error happened on reminders canceling", err)
+		logger.ErrorContext(req.Context(), "deleteAllReminders request: unexpected error happened on reminders canceling", err)
 		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
 		return
 	}
 
 	rmr.sendOkEmptyResponse(w)
 
-	logger.Info("deleteAllReminders request: successfully processed")
+	logger.InfoContext(req.Context(), "deleteAllReminders request: successfully processed")
 }
 
 func (rmr *RemindMeRouter) getReminder(w http.ResponseWriter, req *http.Request) {
-	logger.Info("getReminder request: received")
+	logger.InfoContext(req.Context(), "getReminder request: received")
 
 	id, err := rmr.getId(req)
 	if err != nil {
-		logger.Error("getReminder request: error on parsing reminder ID from the URL param", err)
+		logger.ErrorContext(req.Context(), "getReminder request: error on parsing reminder ID from the URL param", err)
 		rmr.sendErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	reminder, err := rmr.service.Get(id)
+	reminder, err := rmr.service.Get(req.Context(), id)
 	if err != nil {
-		logger.Error("getReminder request: unexpected error happened on reminder fetching", err)
+		logger.ErrorContext(req.Context(), "getReminder request: unexpected error happened on reminder fetching", err)
 		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
 		return
 	}
 	if reminder == nil {
-		logger.Error("getReminder request: reminder not found by ID " + strconv.FormatInt(id, 10))
+		logger.ErrorContext(req.Context(), "getReminder request: reminder not found by ID "+strconv.FormatInt(id, 10))
 		rmr.sendErrorResponse(w, http.StatusNotFound, common.ErrCodeReminderNotFound)
 		return
 	}
 	rmr.sendJsonResponse(w, http.StatusOK, *reminder)
 
-	logger.Info("getReminder request: successfully processed")
+	logger.InfoContext(req.Context(), "getReminder request: successfully processed")
 }
 
 func (rmr *RemindMeRouter) deleteReminder(w http.ResponseWriter, req *http.Request) {
-	logger.Info("deleteReminder request: received")
+	logger.InfoContext(req.Context(), "deleteReminder request: received")
 
 	id, err := rmr.getId(req)
 	if err != nil {
-		logger.Error("deleteReminder request: error on parsing reminder ID from the URL param", err)
+		logger.ErrorContext(req.Context(), "deleteReminder request: error on parsing reminder ID from the URL param", err)
 		rmr.sendErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	canceled, err := rmr.service.Cancel(id)
+	canceled, err := rmr.service.Cancel(req.Context(), id)
 	if err != nil {
-		logger.Error("deleteReminder request: unexpected error happened on reminder canceling", err)
+		logger.ErrorContext(req.Context(), "deleteReminder request: unexpected error happened on reminder canceling", err)
 		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
 		return
 	}
 
 	if !canceled {
-		logger.Error("deleteReminder request: reminder not found by ID " + strconv.FormatInt(id, 10))
+		logger.ErrorContext(req.Context(), "deleteReminder request: reminder not found by ID "+strconv.FormatInt(id, 10))
 		rmr.sendErrorResponse(w, http.StatusNotFound, common.ErrCodeReminderNotFound)
 		return
 	}
 	rmr.sendOkEmptyResponse(w)
 
-	logger.Info("deleteReminder request: successfully processed")
+	logger.InfoContext(req.Context(), "deleteReminder request: successfully processed")
 }
 
 func (rmr *RemindMeRouter) changeReminder(w http.ResponseWriter, req *http.Request) {
-	logger.Info("changeReminder request: received")
+	logger.InfoContext(req.Context(), "changeReminder request: received")
 
 	id, err := rmr.getId(req)
 	if err != nil {
-		logger.Error("changeReminder request: error on parsing reminder ID from the URL param", err)
+		logger.ErrorContext(req.Context(), "changeReminder request: error on parsing reminder ID from the URL param", err)
 		rmr.sendErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -158,32 +194,324 @@ func (rmr *RemindMeRouter) changeReminder(w http.ResponseWriter, req *http.Reque
 	var reminder common.Reminder
 	err = json.NewDecoder(req.Body).Decode(&reminder)
 	if err != nil {
-		logger.Error("changeReminder request: unexpected error happened on request body decoding", err)
+		logger.ErrorContext(req.Context(), "changeReminder request: unexpected error happened on request body decoding", err)
 		rmr.sendErrorResponse(w, http.StatusBadRequest, common.ErrCodeRequestBody)
 		return
 	}
+	if code := rmr.validateReminder(reminder); code != "" {
+		logger.ErrorContext(req.Context(), "changeReminder request: invalid reminder provided: "+code)
+		rmr.sendErrorResponse(w, http.StatusBadRequest, code)
+		return
+	}
 
-	rmr.service.Change(id, reminder)
+	err = rmr.service.Change(req.Context(), id, reminder)
+	if err != nil {
+		logger.ErrorContext(req.Context(), "changeReminder request: unexpected error happened on reminder changing", err)
+		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
+		return
+	}
 	rmr.sendOkEmptyResponse(w)
 
-	logger.Info("changeReminder request: successfully processed")
+	logger.InfoContext(req.Context(), "changeReminder request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) applyBatch(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "applyBatch request: received")
+
+	var batchReq common.BatchRequest
+	err := json.NewDecoder(req.Body).Decode(&batchReq)
+	if err != nil {
+		logger.ErrorContext(req.Context(), "applyBatch request: unexpected error happened on request body decoding", err)
+		rmr.sendErrorResponse(w, http.StatusBadRequest, common.ErrCodeRequestBody)
+		return
+	}
+
+	operations := batchReq.Operations
+	if len(operations) == 0 {
+		logger.ErrorContext(req.Context(), "applyBatch request: no operations provided")
+		rmr.sendErrorResponse(w, http.StatusBadRequest, common.ErrCodeBatchEmpty)
+		return
+	}
+
+	for i, operation := range operations {
+		if code := rmr.validateBatchOperation(operation); code != "" {
+			logger.ErrorContext(req.Context(), "applyBatch request: invalid operation #"+strconv.Itoa(i)+": "+code)
+			rmr.sendJsonResponse(w, http.StatusBadRequest, rmr.failedBatchResponse(operations, i, code))
+			return
+		}
+	}
+
+	ids, err := rmr.service.ApplyBatch(req.Context(), operations)
+	if err != nil {
+		var batchErr *common.BatchOperationError
+		if errors.As(err, &batchErr) {
+			logger.ErrorContext(req.Context(), "applyBatch request: batch rolled back", err)
+
+			httpCode := http.StatusBadRequest
+			if batchErr.Code == common.ErrCodeReminderNotFound {
+				httpCode = http.StatusNotFound
+			}
+			rmr.sendJsonResponse(w, httpCode, rmr.failedBatchResponse(operations, batchErr.Index, batchErr.Code))
+			return
+		}
+
+		logger.ErrorContext(req.Context(), "applyBatch request: unexpected error happened on batch applying", err)
+		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
+		return
+	}
+
+	results := make([]common.BatchOperationResult, len(operations))
+	for i, operation := range operations {
+		results[i] = common.BatchOperationResult{
+			Index:  i,
+			Type:   operation.Type,
+			ID:     ids[i],
+			Status: common.BatchOperationStatusApplied,
+		}
+	}
+	rmr.sendJsonResponse(w, http.StatusOK, common.BatchResponse{Applied: true, Results: results})
+
+	logger.InfoContext(req.Context(), "applyBatch request: successfully processed "+strconv.Itoa(len(operations))+" operations")
 }
 
 func (rmr *RemindMeRouter) shutdown(w http.ResponseWriter, req *http.Request) {
-	logger.Info("shutdown request: received")
+	logger.InfoContext(req.Context(), "shutdown request: received")
+
+	// the shutdown might have been requested already
+	select {
+	case rmr.shutdownCh <- struct{}{}:
+	default:
+	}
+	rmr.sendOkEmptyResponse(w)
+
+	logger.InfoContext(req.Context(), "shutdown request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) getStatus(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "getStatus request: received")
+
+	schedulerStatus, err := rmr.service.Status(req.Context())
+	if err != nil {
+		logger.ErrorContext(req.Context(), "getStatus request: unexpected error happened on scheduler status fetching", err)
+		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
+		return
+	}
+
+	status := common.Status{
+		Status:        common.HealthStatus(rmr.serverInfo, schedulerStatus),
+		UptimeSeconds: int64(time.Since(rmr.serverInfo.StartedAt).Seconds()),
+		Server:        rmr.serverInfo,
+		Scheduler:     schedulerStatus,
+	}
+	rmr.sendJsonResponse(w, http.StatusOK, status)
+
+	logger.InfoContext(req.Context(), "getStatus request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) getNotifications(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "getNotifications request: received")
+
+	rmr.sendJsonResponse(w, http.StatusOK, rmr.service.Notifications())
 
-	rmr.shutdownCh <- struct{}{}
+	logger.InfoContext(req.Context(), "getNotifications request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) getJournal(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "getJournal request: received")
+
+	rmr.sendJsonResponse(w, http.StatusOK, rmr.service.Journal())
+
+	logger.InfoContext(req.Context(), "getJournal request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) undo(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "undo request: received")
+
+	// the body is optional: the latest entry is undone without it
+	var undoReq common.UndoRequest
+	err := json.NewDecoder(req.Body).Decode(&undoReq)
+	if err != nil && !errors.Is(err, io.EOF) {
+		logger.ErrorContext(req.Context(), "undo request: unexpected error happened on request body decoding", err)
+		rmr.sendErrorResponse(w, http.StatusBadRequest, common.ErrCodeRequestBody)
+		return
+	}
+
+	entry, err := rmr.service.Undo(req.Context(), undoReq.ID)
+	if errors.Is(err, common.ErrJournalEntryConflict) {
+		logger.ErrorContext(req.Context(), "undo request: journal entry "+strconv.FormatInt(undoReq.ID, 10)+" has been followed by the operations on the same reminders")
+		rmr.sendErrorResponse(w, http.StatusConflict, common.ErrCodeJournalEntryConflict)
+		return
+	}
+	if err != nil {
+		logger.ErrorContext(req.Context(), "undo request: unexpected error happened on journal entry undoing", err)
+		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
+		return
+	}
+	if entry == nil {
+		logger.ErrorContext(req.Context(), "undo request: journal entry not found by ID "+strconv.FormatInt(undoReq.ID, 10))
+		rmr.sendErrorResponse(w, http.StatusNotFound, common.ErrCodeJournalEntryNotFound)
+		return
+	}
+	rmr.sendJsonResponse(w, http.StatusOK, entry)
+
+	logger.InfoContext(req.Context(), "undo request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) reloadUserConfigs(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "reloadUserConfigs request: received")
+
+	err := rmr.reloadConfigs(req.Context())
+	if err != nil {
+		logger.ErrorContext(req.Context(), "reloadUserConfigs request: configs rejected", err)
+		rmr.sendErrorResponse(w, http.StatusBadRequest, common.ErrCodeInvalidConfigs)
+		return
+	}
 	rmr.sendOkEmptyResponse(w)
 
-	logger.Info("shutdown request: successfully processed")
+	logger.InfoContext(req.Context(), "reloadUserConfigs request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) enableDnd(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "enableDnd request: received")
+
+	var dnd common.Dnd
+	err := json.NewDecoder(req.Body).Decode(&dnd)
+	if err != nil {
+		logger.ErrorContext(req.Context(), "enableDnd request: unexpected error happened on request body decoding", err)
+		rmr.sendErrorResponse(w, http.StatusBadRequest, common.ErrCodeRequestBody)
+		return
+	}
+	if !dnd.Until.After(time.Now()) {
+		logger.ErrorContext(req.Context(), "enableDnd request: the end of the do-not-disturb period is in the past: "+dnd.Until.Format(time.RFC3339))
+		rmr.sendErrorResponse(w, http.StatusBadRequest, common.ErrCodeDndUntil)
+		return
+	}
+
+	rmr.service.EnableDnd(req.Context(), dnd.Until)
+	rmr.sendOkEmptyResponse(w)
+
+	logger.InfoContext(req.Context(), "enableDnd request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) disableDnd(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "disableDnd request: received")
+
+	rmr.service.DisableDnd(req.Context())
+	rmr.sendOkEmptyResponse(w)
+
+	logger.InfoContext(req.Context(), "disableDnd request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) getPomodoro(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "getPomodoro request: received")
+
+	pomodoro := rmr.service.Pomodoro()
+	if pomodoro == nil {
+		logger.InfoContext(req.Context(), "getPomodoro request: no pomodoro session is running")
+		rmr.sendErrorResponse(w, http.StatusNotFound, common.ErrCodePomodoroNotFound)
+		return
+	}
+	rmr.sendJsonResponse(w, http.StatusOK, pomodoro)
+
+	logger.InfoContext(req.Context(), "getPomodoro request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) startPomodoro(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "startPomodoro request: received")
+
+	var pomodoroReq common.PomodoroRequest
+	err := json.NewDecoder(req.Body).Decode(&pomodoroReq)
+	if err != nil {
+		logger.ErrorContext(req.Context(), "startPomodoro request: unexpected error happened on request body decoding", err)
+		rmr.sendErrorResponse(w, http.StatusBadRequest, common.ErrCodeRequestBody)
+		return
+	}
+	if pomodoroReq.WorkSeconds <= 0 || pomodoroReq.BreakSeconds <= 0 || pomodoroReq.Cycles < 1 || pomodoroReq.Cycles > common.PomodoroMaxCycles {
+		logger.ErrorContext(req.Context(), "startPomodoro request: invalid pomodoro session requested")
+		rmr.sendErrorResponse(w, http.StatusBadRequest, common.ErrCodePomodoroRequest)
+		return
+	}
+
+	work := time.Duration(pomodoroReq.WorkSeconds) * time.Second
+	breakDuration := time.Duration(pomodoroReq.BreakSeconds) * time.Second
+	pomodoro, err := rmr.service.StartPomodoro(req.Context(), work, breakDuration, pomodoroReq.Cycles)
+	if err != nil {
+		if errors.Is(err, common.ErrPomodoroAlreadyRunning) {
+			logger.ErrorContext(req.Context(), "startPomodoro request: the pomodoro session is running already")
+			rmr.sendErrorResponse(w, http.StatusConflict, common.ErrCodePomodoroRunning)
+			return
+		}
+		logger.ErrorContext(req.Context(), "startPomodoro request: unexpected error happened on pomodoro reminders setting", err)
+		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
+		return
+	}
+	rmr.sendJsonResponse(w, http.StatusOK, pomodoro)
+
+	logger.InfoContext(req.Context(), "startPomodoro request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) stopPomodoro(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "stopPomodoro request: received")
+
+	pomodoro, err := rmr.service.StopPomodoro(req.Context())
+	if err != nil {
+		logger.ErrorContext(req.Context(), "stopPomodoro request: unexpected error happened on pomodoro reminders canceling", err)
+		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
+		return
+	}
+	if pomodoro == nil {
+		logger.ErrorContext(req.Context(), "stopPomodoro request: no pomodoro session is running")
+		rmr.sendErrorResponse(w, http.StatusNotFound, common.ErrCodePomodoroNotFound)
+		return
+	}
+	rmr.sendJsonResponse(w, http.StatusOK, pomodoro)
+
+	logger.InfoContext(req.Context(), "stopPomodoro request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) skipPomodoroInterval(w http.ResponseWriter, req *http.Request) {
+	logger.InfoContext(req.Context(), "skipPomodoroInterval request: received")
+
+	pomodoro, running, err := rmr.service.SkipPomodoroInterval(req.Context())
+	if err != nil {
+		logger.ErrorContext(req.Context(), "skipPomodoroInterval request: unexpected error happened on pomodoro reminders rescheduling", err)
+		rmr.sendErrorResponse(w, http.StatusInternalServerError, common.ErrCodeDbQuerying)
+		return
+	}
+	if !running {
+		logger.ErrorContext(req.Context(), "skipPomodoroInterval request: no pomodoro session is running")
+		rmr.sendErrorResponse(w, http.StatusNotFound, common.ErrCodePomodoroNotFound)
+		return
+	}
+	rmr.sendJsonResponse(w, http.StatusOK, pomodoro)
+
+	logger.InfoContext(req.Context(), "skipPomodoroInterval request: successfully processed")
 }
 
 func (rmr *RemindMeRouter) healthCheck(w http.ResponseWriter, req *http.Request) {
-	logger.Info("healthCheck request: received")
+	logger.InfoContext(req.Context(), "healthCheck request: received")
+
+	// the server is up, so the healthcheck is successful even if the scheduler status can't be fetched - but degraded
+	healthStatus := common.HealthStatusDegraded
+	schedulerStatus, err := rmr.service.Status(req.Context())
+	if err != nil {
+		logger.ErrorContext(req.Context(), "healthCheck request: unexpected error happened on scheduler status fetching", err)
+	} else {
+		healthStatus = common.HealthStatus(rmr.serverInfo, schedulerStatus)
+	}
+	rmr.sendJsonResponse(w, http.StatusOK, common.Healthcheck{Status: healthStatus})
 
-	rmr.sendJsonResponse(w, http.StatusOK, common.HealthcheckOk())
+	logger.InfoContext(req.Context(), "healthCheck request: successfully processed")
+}
+
+func (rmr *RemindMeRouter) metrics(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
 
-	logger.Info("healthCheck request: successfully processed")
+	err := metrics.WriteTo(w)
+	if err != nil {
+		logger.ErrorContext(req.Context(), "metrics request: unexpected error happened on metrics writing", err)
+	}
 }
 
 func (rmr *RemindMeRouter) sendOkEmptyResponse(w http.ResponseWriter) {
@@ -206,6 +534,72 @@ func (rmr *RemindMeRouter) sendErrorResponse(w http.ResponseWriter, httpCode int
 	rmr.sendJsonResponse(w, httpCode, common.ErrorResponse{Code: errCode})
 }
 
+// validateBatchOperation returns an error code if the operation is invalid, or an empty string otherwise
+func (rmr *RemindMeRouter) validateBatchOperation(operation common.BatchOperation) string {
+	switch operation.Type {
+	case common.BatchOperationCreate:
+		if operation.Reminder == nil {
+			return common.ErrCodeBatchOperationReminder
+		}
+		return rmr.validateReminder(*operation.Reminder)
+	case common.BatchOperationUpdate:
+		if operation.ID <= 0 {
+			return common.ErrCodeBatchOperationId
+		}
+		if operation.Reminder == nil {
+			return common.ErrCodeBatchOperationReminder
+		}
+		return rmr.validateReminder(*operation.Reminder)
+	case common.BatchOperationDelete:
+		if operation.ID <= 0 {
+			return common.ErrCodeBatchOperationId
+		}
+	default:
+		return common.ErrCodeBatchOperationType
+	}
+	return ""
+}
+
+// validateReminder returns an error code if the reminder can't be scheduled, or an empty string otherwise
+func (rmr *RemindMeRouter) validateReminder(reminder common.Reminder) string {
+	if strings.TrimSpace(reminder.Message) == "" {
+		return common.ErrCodeReminderMessage
+	}
+	if reminder.RemindAt.IsZero() {
+		return common.ErrCodeReminderTime
+	}
+	if !rmr.isValidUrgency(reminder.Urgency) {
+		return common.ErrCodeReminderUrgency
+	}
+	return ""
+}
+
+// isValidUrgency accepts the empty urgency, which is treated as the normal one
+func (rmr *RemindMeRouter) isValidUrgency(urgency string) bool {
+	switch urgency {
+	case "", common.UrgencyLow, common.UrgencyNormal, common.UrgencyHigh:
+		return true
+	default:
+		return false
+	}
+}
+
+// failedBatchResponse marks the failed operation with the error code, and all the others as skipped, since the batch is atomic
+func (rmr *RemindMeRouter) failedBatchResponse(operations []common.BatchOperation, failedIndex int, code string) common.BatchResponse {
+	results := make([]common.BatchOperationResult, len(operations))
+	for i, operation := range operations {
+		results[i] = common.BatchOperationResult{
+			Index:  i,
+			Type:   operation.Type,
+			ID:     operation.ID,
+			Status: common.BatchOperationStatusSkipped,
+		}
+	}
+	results[failedIndex].Status = common.BatchOperationStatusFailed
+	results[failedIndex].Code = code
+	return common.BatchResponse{Applied: false, Results: results}
+}
+
 func (rmr *RemindMeRouter) getId(req *http.Request) (int64, error) {
 	id := chi.URLParam(req, "id")
 	if id == "" {
@@ -5,77 +5,266 @@ import (
 	"errors"
 	"fmt"
 	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
 	"n0rdy.foo/remindme/httpserver/api"
+	"n0rdy.foo/remindme/httpserver/repo"
 	"n0rdy.foo/remindme/httpserver/repo/inmemory"
 	"n0rdy.foo/remindme/httpserver/repo/sqlite"
 	"n0rdy.foo/remindme/httpserver/service"
 	"n0rdy.foo/remindme/logger"
 	"n0rdy.foo/remindme/utils"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 )
 
-func Start(port int) {
-	err := logger.SetupLogger(utils.GetOsSpecificAppDataDir(), common.ServerLogsFileName)
+// the first file descriptor passed by systemd socket activation, the ones before it are stdin, stdout and stderr
+const listenFdsStart = 3
+
+// the time given to the requests and the notifications being processed to finish on shutdown:
+// it's shorter than the time the "stop" command waits for the server to exit
+const shutdownTimeout = 5 * time.Second
+
+func Start(address common.ServerAddress, apiToken string) error {
+	// the invalid configs are reported once the logger is set up, as it's configured by them too
+	settings, settingsErr := config.ResolveSettings()
+
+	err := logger.SetupLogger(utils.GetOsSpecificAppDataDir(), common.ServerLogsFileName, settings.Logger)
 	if err != nil {
-		fmt.Println("setting up logger failed", err)
+		// nowhere else to report it to
+		fmt.Fprintln(os.Stderr, "setting up logger failed", err)
 	} else {
 		defer logger.Close()
 	}
+	// the server process is started by the CLI invocation, but its records should be tagged with the IDs of the requests being processed
+	logger.SetRequestId("")
 
-	shutdownCh := make(chan struct{})
+	if settingsErr != nil {
+		logger.Warn("invalid configs, falling back to defaults for the invalid values", settingsErr)
+	}
 
-	reminderRepo, err := sqlite.NewSqliteReminderRepo()
-	if err != nil {
-		logger.Error("failed to create SQLite repo - falling back to the in-memory repo", err)
+	// buffered, so that the shutdown request doesn't wait for the shutdown to be started
+	shutdownCh := make(chan struct{}, 1)
+
+	serverInfo := common.ServerInfo{
+		Version:   common.AppVersion,
+		StartedAt: time.Now(),
+		Transport: address.Transport,
+		Address:   address.String(),
+		RepoType:  settings.RepoBackend,
+	}
+	if address.Transport == common.TcpTransport {
+		serverInfo.Port = address.Port
+	}
+
+	var reminderRepo repo.ReminderRepo
+	if settings.RepoBackend == common.InMemoryRepoType {
 		reminderRepo = inmemory.NewImMemoryReminderRepo()
+	} else {
+		reminderRepo, err = sqlite.NewSqliteReminderRepo()
+		if err != nil {
+			logger.Warn("failed to create SQLite repo - falling back to the in-memory repo", err)
+			reminderRepo = inmemory.NewImMemoryReminderRepo()
+			serverInfo.RepoType = common.InMemoryRepoType
+			serverInfo.RepoFallback = true
+		} else {
+			serverInfo.DbPath = utils.GetOsSpecificAppDataDir() + common.DbFileName
+		}
 	}
 
-	srv := service.NewReminderService(reminderRepo)
-	remindMeRouter := api.NewRemindMeRouter(&srv, shutdownCh)
-	httpRouter := remindMeRouter.NewRouter()
-	portAsString := strconv.Itoa(port)
+	// the shell prompt reads the next reminder from the file rather than requesting the server
+	publishNextReminder := func(state common.NextReminderState) {
+		err := config.PersistNextReminderState(state)
+		if err != nil {
+			logger.Error("failed to persist the next reminder state", err)
+		}
+	}
+	srv := service.NewReminderService(reminderRepo, settings.NotificationBackends, settings.QuietHours, publishNextReminder)
 
-	logger.Info("http: starting server at port " + portAsString)
+	cleanupTicker := time.NewTicker(settings.CleanupInterval)
 
-	server := &http.Server{Addr: "localhost:" + portAsString, Handler: httpRouter}
-	go func() {
-		err := server.ListenAndServe()
+	// the configs that can't be changed on the fly (e.g. the repo backend) are applied on the next start
+	reloadConfigs := func(ctx context.Context) error {
+		reloaded, err := config.ResolveSettings()
 		if err != nil {
-			close(shutdownCh)
-			if errors.Is(err, http.ErrServerClosed) {
-				logger.Info("server shutdown")
-			} else {
-				logger.Error("server failed", err)
-			}
+			logger.ErrorContext(ctx, "reloadConfigs: invalid configs - keeping the current ones", err)
+			return err
 		}
-	}()
 
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
-	// job to delete expired reminders if any
+		logger.SetLevel(reloaded.Logger.Level)
+		srv.ApplyConfigs(reloaded.NotificationBackends, reloaded.QuietHours)
+		cleanupTicker.Reset(reloaded.CleanupInterval)
+		if reloaded.RepoBackend != settings.RepoBackend {
+			logger.WarnContext(ctx, "reloadConfigs: repo backend change will be applied on the app restart")
+		}
+
+		logger.InfoContext(ctx, "reloadConfigs: configs reloaded")
+		return nil
+	}
+
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+	defer signal.Stop(reloadCh)
 	go func() {
-		for range ticker.C {
-			logger.Info("deleteExpiredReminders job: invoked")
-			srv.DeleteExpiredReminders()
+		for range reloadCh {
+			logger.Info("reloadConfigs: SIGHUP received")
+			reloadConfigs(context.Background())
 		}
 	}()
 
+	remindMeRouter := api.NewRemindMeRouter(&srv, shutdownCh, apiToken, serverInfo, reloadConfigs)
+	httpRouter := remindMeRouter.NewRouter()
+
+	logger.Info("http: starting server at " + address.String())
+
+	listener, err := listen(address)
+	if err != nil {
+		logger.Error("http: failed to listen at "+address.String(), err)
+		reminderRepo.Close()
+		return err
+	}
+
 	// restore state on start:
 	// for SQLite repo it should restore active non-expired reminders and delete expired ones,
 	// for in-memory repo it won't do anything as it's empty on start
-	reminderRepo.DeleteAllWithRemindAtBefore(time.Now())
-	srv.RestoreActiveReminders()
+	state, err := config.FetchSchedulerState()
+	if err != nil {
+		logger.Warn("failed to fetch the scheduler state persisted on the previous shutdown", err)
+	} else if state != nil {
+		if serverInfo.RepoType == common.InMemoryRepoType {
+			// the journal and the pomodoro session refer to the reminders that haven't survived the restart,
+			// and their IDs are going to be reused
+			state.Journal = nil
+			state.Pomodoro = nil
+		}
+		srv.RestoreState(context.Background(), *state)
+	}
+	// the reminders that have come due while the app was stopped, or haven't been notified about before the shutdown, are notified about right away
+	srv.RestoreActiveReminders(context.Background())
 
-	for range shutdownCh {
-		logger.Info("server shutdown requested")
-		err := server.Shutdown(context.Background())
-		if err != nil {
-			err := server.Close()
-			if err != nil {
+	server := &http.Server{Handler: httpRouter}
+	serveErrCh := make(chan error, 1)
+	go func() {
+		serveErrCh <- server.Serve(listener)
+	}()
+
+	// job to delete expired reminders if any
+	stopCleanupCh := make(chan struct{})
+	cleanupDoneCh := make(chan struct{})
+	go func() {
+		defer close(cleanupDoneCh)
+		for {
+			select {
+			case <-stopCleanupCh:
 				return
+			case <-cleanupTicker.C:
+				logger.Info("deleteExpiredReminders job: invoked")
+				srv.DeleteExpiredReminders(context.Background())
 			}
 		}
+	}()
+
+	stopSignalCh := make(chan os.Signal, 1)
+	signal.Notify(stopSignalCh, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(stopSignalCh)
+
+	var serveErr error
+	select {
+	case <-shutdownCh:
+		logger.Info("server shutdown requested")
+	case sig := <-stopSignalCh:
+		logger.Info("server shutdown requested by " + sig.String())
+	case serveErr = <-serveErrCh:
+		logger.Error("server failed", serveErr)
+	}
+
+	// the ordered shutdown: no requests are accepted, the notifications being sent are finished,
+	// the scheduler and the jobs are stopped, and its state is persisted, so the DB can be closed safely
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	err = server.Shutdown(ctx)
+	if err != nil {
+		logger.Warn("http: requests haven't been finished in time - closing the connections", err)
+		server.Close()
+	}
+
+	// the notifications that haven't been finished in time are sent again on the next start, as they are deleted from the DB only after being sent
+	srv.Shutdown(ctx)
+
+	signal.Stop(reloadCh)
+	close(stopCleanupCh)
+	cleanupTicker.Stop()
+	<-cleanupDoneCh
+
+	err = config.PersistSchedulerState(srv.State())
+	if err != nil {
+		logger.Error("failed to persist the scheduler state", err)
+	}
+
+	err = reminderRepo.Close()
+	if err != nil {
+		logger.Error("failed to close the repo", err)
 	}
+
+	logger.Info("server shutdown")
+	return serveErr
+}
+
+func listen(address common.ServerAddress) (net.Listener, error) {
+	if listener, activated, err := activatedListener(); activated {
+		logger.Info("http: using the listener passed by systemd socket activation")
+		return listener, err
+	}
+
+	if address.Transport != common.UnixTransport {
+		return net.Listen("tcp", address.String())
+	}
+
+	err := removeStaleSocket(address.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the current user is allowed to connect to the socket
+	return listenUnixSocket(address.SocketPath)
+}
+
+// activatedListener returns the listener passed by systemd if the server has been started via socket activation,
+// see https://www.freedesktop.org/software/systemd/man/latest/sd_listen_fds.html
+func activatedListener() (net.Listener, bool, error) {
+	if os.Getenv("LISTEN_PID") != strconv.Itoa(os.Getpid()) {
+		return nil, false, nil
+	}
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, false, nil
+	}
+
+	file := os.NewFile(uintptr(listenFdsStart), "systemd-socket")
+	defer file.Close()
+
+	listener, err := net.FileListener(file)
+	return listener, true, err
+}
+
+// removeStaleSocket removes the socket file left by the server that hasn't been stopped properly (e.g. killed),
+// as otherwise it's not possible to listen to it again
+func removeStaleSocket(socketPath string) error {
+	if _, err := os.Stat(socketPath); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	conn, err := net.Dial("unix", socketPath)
+	if err == nil {
+		conn.Close()
+		return errors.New("another server is listening to the socket " + socketPath)
+	}
+
+	logger.Info("http: removing stale socket " + socketPath)
+	return os.Remove(socketPath)
 }
@@ -0,0 +1,48 @@
+package metrics
+
+var (
+	ScheduledReminders = NewGauge(
+		"remindme_scheduled_reminders",
+		"Number of the reminders scheduled to be notified about",
+	)
+	NotificationsSent = NewCounterVec(
+		"remindme_notifications_sent_total",
+		"Number of the notifications successfully sent, per notification backend",
+		"backend",
+	)
+	NotificationsFailed = NewCounterVec(
+		"remindme_notifications_failed_total",
+		"Number of the notifications failed to be sent, per notification backend",
+		"backend",
+	)
+	NotificationDelay = NewHistogramVec(
+		"remindme_notification_delay_seconds",
+		"Delay between the time the reminder has been scheduled at and the time the notification has been sent, per notification backend",
+		[]float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60},
+		"backend",
+	)
+	HttpRequests = NewCounterVec(
+		"remindme_http_requests_total",
+		"Number of the HTTP requests processed, per method, route and status code",
+		"method", "route", "status",
+	)
+	HttpRequestDuration = NewHistogramVec(
+		"remindme_http_request_duration_seconds",
+		"Duration of the HTTP requests processing, per method and route",
+		[]float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+		"method", "route",
+	)
+	ExpiredCleanupRuns = NewCounterVec(
+		"remindme_expired_cleanup_runs_total",
+		"Number of the runs of the job that deletes expired reminders",
+	)
+	ExpiredRemindersDeleted = NewCounterVec(
+		"remindme_expired_reminders_deleted_total",
+		"Number of the expired reminders deleted by the cleanup job",
+	)
+	RepoErrors = NewCounterVec(
+		"remindme_repo_errors_total",
+		"Number of the errors returned by the reminders repo, per operation",
+		"operation",
+	)
+)
@@ -0,0 +1,260 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// collector is a metric that can be written in the Prometheus text exposition format:
+// https://prometheus.io/docs/instrumenting/exposition_formats/#text-based-format
+type collector interface {
+	write(w io.Writer) error
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make([]collector, 0)
+)
+
+func register(c collector) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry = append(registry, c)
+}
+
+// WriteTo writes all the registered metrics in the Prometheus text exposition format
+func WriteTo(w io.Writer) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, c := range registry {
+		if err := c.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+	mu         sync.Mutex
+	values     map[string]float64
+	labels     map[string][]string
+}
+
+func NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		values:     make(map[string]float64),
+		labels:     make(map[string][]string),
+	}
+	register(c)
+	return c
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := labelsKey(labelValues)
+	c.values[key] += value
+	c.labels[key] = labelValues
+}
+
+func (c *CounterVec) write(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := writeHeader(w, c.name, c.help, "counter"); err != nil {
+		return err
+	}
+	// a counter without labels is exposed from the start, so that the rate can be calculated from 0
+	if len(c.labelNames) == 0 && len(c.values) == 0 {
+		c.values[""] = 0
+	}
+	for _, key := range sortedKeys(c.values) {
+		_, err := fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labelNames, c.labels[key]), formatValue(c.values[key]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type Gauge struct {
+	name  string
+	help  string
+	mu    sync.Mutex
+	value float64
+}
+
+func NewGauge(name string, help string) *Gauge {
+	g := &Gauge{name: name, help: help}
+	register(g)
+	return g
+}
+
+func (g *Gauge) Set(value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.value = value
+}
+
+func (g *Gauge) write(w io.Writer) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := writeHeader(w, g.name, g.help, "gauge"); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s %s\n", g.name, formatValue(g.value))
+	return err
+}
+
+type HistogramVec struct {
+	name       string
+	help       string
+	buckets    []float64
+	labelNames []string
+	mu         sync.Mutex
+	samples    map[string]*histogramSample
+}
+
+type histogramSample struct {
+	labelValues  []string
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+// NewHistogramVec creates a histogram with the provided upper bounds of the buckets, which should be sorted in an ascending order.
+// The "+Inf" bucket is added implicitly.
+func NewHistogramVec(name string, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	h := &HistogramVec{
+		name:       name,
+		help:       help,
+		buckets:    buckets,
+		labelNames: labelNames,
+		samples:    make(map[string]*histogramSample),
+	}
+	register(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := labelsKey(labelValues)
+	sample, found := h.samples[key]
+	if !found {
+		sample = &histogramSample{
+			labelValues:  labelValues,
+			bucketCounts: make([]uint64, len(h.buckets)),
+		}
+		h.samples[key] = sample
+	}
+
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			sample.bucketCounts[i]++
+		}
+	}
+	sample.sum += value
+	sample.count++
+}
+
+func (h *HistogramVec) write(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := writeHeader(w, h.name, h.help, "histogram"); err != nil {
+		return err
+	}
+
+	bucketLabelNames := append(append([]string{}, h.labelNames...), "le")
+	for _, key := range sortedKeys(h.samples) {
+		sample := h.samples[key]
+		for i, upperBound := range h.buckets {
+			bucketLabels := formatLabels(bucketLabelNames, append(append([]string{}, sample.labelValues...), formatValue(upperBound)))
+			_, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, bucketLabels, sample.bucketCounts[i])
+			if err != nil {
+				return err
+			}
+		}
+
+		infLabels := formatLabels(bucketLabelNames, append(append([]string{}, sample.labelValues...), "+Inf"))
+		labels := formatLabels(h.labelNames, sample.labelValues)
+		_, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			h.name, infLabels, sample.count,
+			h.name, labels, formatValue(sample.sum),
+			h.name, labels, sample.count,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHeader(w io.Writer, name string, help string, metricType string) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	return err
+}
+
+func formatLabels(names []string, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs[i] = name + `="` + escapeLabelValue(value) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabelValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func labelsKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
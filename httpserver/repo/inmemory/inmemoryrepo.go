@@ -1,13 +1,18 @@
 package inmemory
 
 import (
+	"context"
 	"n0rdy.foo/remindme/common"
 	"n0rdy.foo/remindme/httpserver/repo"
 	"n0rdy.foo/remindme/httpserver/repo/inmemory/idresolver"
+	"sync"
 	"time"
 )
 
 type inMemoryReminderRepo struct {
+	// guards the reminders, as the timers delete them in their own goroutines concurrently with the requests,
+	// and makes each method, including the batch, atomic
+	mu         sync.Mutex
 	reminders  map[int64]common.Reminder
 	idResolver idresolver.IdResolver
 }
@@ -19,18 +24,28 @@ func NewImMemoryReminderRepo() repo.ReminderRepo {
 	}
 }
 
-func (repo *inMemoryReminderRepo) Add(reminder common.Reminder) (int64, error) {
+func (repo *inMemoryReminderRepo) Add(ctx context.Context, reminder common.Reminder) (int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	reminder.ID = repo.idResolver.Next()
+	reminder.CreatedAt, reminder.UpdatedAt = now(), now()
 	repo.reminders[reminder.ID] = reminder
 	return reminder.ID, nil
 }
 
-func (repo *inMemoryReminderRepo) Update(reminder common.Reminder) error {
-	repo.reminders[reminder.ID] = reminder
+func (repo *inMemoryReminderRepo) Update(ctx context.Context, reminder common.Reminder) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.reminders[reminder.ID] = updated(repo.reminders[reminder.ID], reminder)
 	return nil
 }
 
-func (repo *inMemoryReminderRepo) List() ([]common.Reminder, error) {
+func (repo *inMemoryReminderRepo) List(ctx context.Context) ([]common.Reminder, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	remindersAsList := make([]common.Reminder, len(repo.reminders))
 	i := 0
 
@@ -42,7 +57,10 @@ func (repo *inMemoryReminderRepo) List() ([]common.Reminder, error) {
 	return remindersAsList, nil
 }
 
-func (repo *inMemoryReminderRepo) Get(id int64) (*common.Reminder, error) {
+func (repo *inMemoryReminderRepo) Get(ctx context.Context, id int64) (*common.Reminder, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	if reminder, found := repo.reminders[id]; found {
 		return &reminder, nil
 	} else {
@@ -50,22 +68,34 @@ func (repo *inMemoryReminderRepo) Get(id int64) (*common.Reminder, error) {
 	}
 }
 
-func (repo *inMemoryReminderRepo) DeleteAll() error {
+func (repo *inMemoryReminderRepo) DeleteAll(ctx context.Context) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	repo.reminders = make(map[int64]common.Reminder, 0)
 	return nil
 }
 
-func (repo *inMemoryReminderRepo) Delete(id int64) error {
+func (repo *inMemoryReminderRepo) Delete(ctx context.Context, id int64) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	delete(repo.reminders, id)
 	return nil
 }
 
-func (repo *inMemoryReminderRepo) Exists(id int64) (bool, error) {
+func (repo *inMemoryReminderRepo) Exists(ctx context.Context, id int64) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	_, found := repo.reminders[id]
 	return found, nil
 }
 
-func (repo *inMemoryReminderRepo) DeleteAllWithRemindAtBefore(threshold time.Time) ([]int64, error) {
+func (repo *inMemoryReminderRepo) DeleteAllWithRemindAtBefore(ctx context.Context, threshold time.Time) ([]int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	deletedIds := make([]int64, 0)
 	for id, reminder := range repo.reminders {
 		if reminder.RemindAt.Before(threshold) {
@@ -76,7 +106,10 @@ func (repo *inMemoryReminderRepo) DeleteAllWithRemindAtBefore(threshold time.Tim
 	return deletedIds, nil
 }
 
-func (repo *inMemoryReminderRepo) GetRemindersAfter(threshold time.Time) ([]common.Reminder, error) {
+func (repo *inMemoryReminderRepo) GetRemindersAfter(ctx context.Context, threshold time.Time) ([]common.Reminder, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	remindersAfter := make([]common.Reminder, 0)
 	for _, reminder := range repo.reminders {
 		if reminder.RemindAt.After(threshold) {
@@ -86,7 +119,83 @@ func (repo *inMemoryReminderRepo) GetRemindersAfter(threshold time.Time) ([]comm
 	return remindersAfter, nil
 }
 
+func (repo *inMemoryReminderRepo) ApplyBatch(ctx context.Context, operations []common.BatchOperation) ([]int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	// operations are applied to a copy of the reminders, which replaces the original ones only if all of them succeeded
+	reminders := make(map[int64]common.Reminder, len(repo.reminders))
+	for id, reminder := range repo.reminders {
+		reminders[id] = reminder
+	}
+
+	ids := make([]int64, len(operations))
+	// the IDs are allocated only once the batch is committed, as the resolver can't give them back on rollback,
+	// and the other operations of the batch can't refer to the created reminders anyway
+	creates := make([]int, 0)
+	for i, operation := range operations {
+		switch operation.Type {
+		case common.BatchOperationCreate:
+			creates = append(creates, i)
+		case common.BatchOperationUpdate:
+			existing, found := reminders[operation.ID]
+			if !found {
+				return nil, &common.BatchOperationError{Index: i, Code: common.ErrCodeReminderNotFound}
+			}
+			reminder := *operation.Reminder
+			reminder.ID = operation.ID
+			reminders[reminder.ID] = updated(existing, reminder)
+			ids[i] = reminder.ID
+		case common.BatchOperationDelete:
+			if _, found := reminders[operation.ID]; !found {
+				return nil, &common.BatchOperationError{Index: i, Code: common.ErrCodeReminderNotFound}
+			}
+			delete(reminders, operation.ID)
+			ids[i] = operation.ID
+		default:
+			return nil, &common.BatchOperationError{Index: i, Code: common.ErrCodeBatchOperationType}
+		}
+	}
+
+	for _, i := range creates {
+		reminder := *operations[i].Reminder
+		reminder.ID = repo.idResolver.Next()
+		reminder.CreatedAt, reminder.UpdatedAt = now(), now()
+		reminders[reminder.ID] = reminder
+		ids[i] = reminder.ID
+	}
+
+	repo.reminders = reminders
+	return ids, nil
+}
+
+func (repo *inMemoryReminderRepo) Restore(ctx context.Context, deleteIds []int64, reminders []common.Reminder) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, id := range deleteIds {
+		delete(repo.reminders, id)
+	}
+	// the IDs are never reused by the ID resolver, so the deleted reminder can be re-created with its ID
+	for _, reminder := range reminders {
+		repo.reminders[reminder.ID] = reminder
+	}
+	return nil
+}
+
 func (repo *inMemoryReminderRepo) Close() error {
 	// nothing to close
 	return nil
 }
+
+// now is truncated to seconds to match the precision of the SQLite repo
+func now() time.Time {
+	return time.Now().Truncate(time.Second)
+}
+
+// updated keeps the creation time of the existing reminder
+func updated(existing common.Reminder, reminder common.Reminder) common.Reminder {
+	reminder.CreatedAt = existing.CreatedAt
+	reminder.UpdatedAt = now()
+	return reminder
+}
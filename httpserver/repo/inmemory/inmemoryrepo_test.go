@@ -0,0 +1,199 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"n0rdy.foo/remindme/common"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func addReminders(t *testing.T, repo *inMemoryReminderRepo, count int) []int64 {
+	t.Helper()
+	ids := make([]int64, 0, count)
+	for i := 0; i < count; i++ {
+		id, err := repo.Add(context.Background(), common.Reminder{Message: "reminder " + strconv.Itoa(i), RemindAt: time.Now().Add(time.Hour)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestApplyBatchConcurrentlyWithDeletes(t *testing.T) {
+	ctx := context.Background()
+	repo := NewImMemoryReminderRepo().(*inMemoryReminderRepo)
+	// the odd reminders are changed by the batches, and the even ones are deleted as if their timers have fired
+	ids := addReminders(t, repo, 200)
+
+	var wg sync.WaitGroup
+	for i := 0; i < len(ids); i += 2 {
+		wg.Add(2)
+		go func(id int64) {
+			defer wg.Done()
+			err := repo.Delete(ctx, id)
+			if err != nil {
+				t.Error(err)
+			}
+		}(ids[i])
+		go func(id int64) {
+			defer wg.Done()
+			_, err := repo.ApplyBatch(ctx, []common.BatchOperation{
+				{Type: common.BatchOperationUpdate, ID: id, Reminder: &common.Reminder{Message: "changed", RemindAt: time.Now().Add(2 * time.Hour)}},
+				{Type: common.BatchOperationCreate, Reminder: &common.Reminder{Message: "created", RemindAt: time.Now().Add(time.Hour)}},
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(ids[i+1])
+	}
+	wg.Wait()
+
+	reminders, err := repo.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the odd ones changed and the created ones
+	if len(reminders) != len(ids) {
+		t.Fatalf("expected %d reminders, got %d", len(ids), len(reminders))
+	}
+	for i, id := range ids {
+		reminder, err := repo.Get(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 0 && reminder != nil {
+			t.Errorf("expected the deleted reminder %d not to be brought back by the batch", id)
+		}
+		if i%2 == 1 && (reminder == nil || reminder.Message != "changed") {
+			t.Errorf("expected the reminder %d to be changed by the batch, got %v", id, reminder)
+		}
+	}
+}
+
+func TestApplyBatch(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+	tests := []struct {
+		name       string
+		operations func(ids []int64) []common.BatchOperation
+		// the index of the failed operation and its error code, if the batch is expected to be rolled back
+		failedIndex int
+		failedCode  string
+		expectedIds func(ids []int64) []int64
+		// the messages of the reminders after the batch by their IDs
+		expected func(ids []int64) map[int64]string
+	}{
+		{
+			name: "all the operations applied",
+			operations: func(ids []int64) []common.BatchOperation {
+				return []common.BatchOperation{
+					{Type: common.BatchOperationCreate, Reminder: &common.Reminder{Message: "created", RemindAt: later}},
+					{Type: common.BatchOperationUpdate, ID: ids[0], Reminder: &common.Reminder{Message: "changed", RemindAt: later}},
+					{Type: common.BatchOperationDelete, ID: ids[1]},
+					{Type: common.BatchOperationCreate, Reminder: &common.Reminder{Message: "created too", RemindAt: later}},
+				}
+			},
+			expectedIds: func(ids []int64) []int64 {
+				// the created reminders get the IDs following the existing ones in the order of the operations
+				return []int64{ids[1] + 1, ids[0], ids[1], ids[1] + 2}
+			},
+			expected: func(ids []int64) map[int64]string {
+				return map[int64]string{ids[0]: "changed", ids[1] + 1: "created", ids[1] + 2: "created too"}
+			},
+		},
+		{
+			name: "rolled back on the update of the missing reminder",
+			operations: func(ids []int64) []common.BatchOperation {
+				return []common.BatchOperation{
+					{Type: common.BatchOperationCreate, Reminder: &common.Reminder{Message: "created", RemindAt: later}},
+					{Type: common.BatchOperationDelete, ID: ids[0]},
+					{Type: common.BatchOperationUpdate, ID: 42, Reminder: &common.Reminder{Message: "changed", RemindAt: later}},
+				}
+			},
+			failedIndex: 2,
+			failedCode:  common.ErrCodeReminderNotFound,
+			expected: func(ids []int64) map[int64]string {
+				return map[int64]string{ids[0]: "reminder 0", ids[1]: "reminder 1"}
+			},
+		},
+		{
+			name: "rolled back on the delete of the reminder deleted by the batch already",
+			operations: func(ids []int64) []common.BatchOperation {
+				return []common.BatchOperation{
+					{Type: common.BatchOperationUpdate, ID: ids[1], Reminder: &common.Reminder{Message: "changed", RemindAt: later}},
+					{Type: common.BatchOperationDelete, ID: ids[0]},
+					{Type: common.BatchOperationDelete, ID: ids[0]},
+				}
+			},
+			failedIndex: 2,
+			failedCode:  common.ErrCodeReminderNotFound,
+			expected: func(ids []int64) map[int64]string {
+				return map[int64]string{ids[0]: "reminder 0", ids[1]: "reminder 1"}
+			},
+		},
+		{
+			name: "rolled back on the unknown operation type",
+			operations: func(ids []int64) []common.BatchOperation {
+				return []common.BatchOperation{
+					{Type: common.BatchOperationCreate, Reminder: &common.Reminder{Message: "created", RemindAt: later}},
+					{Type: "upsert", ID: ids[0]},
+				}
+			},
+			failedIndex: 1,
+			failedCode:  common.ErrCodeBatchOperationType,
+			expected: func(ids []int64) map[int64]string {
+				return map[int64]string{ids[0]: "reminder 0", ids[1]: "reminder 1"}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := NewImMemoryReminderRepo().(*inMemoryReminderRepo)
+			ids := addReminders(t, repo, 2)
+
+			batchIds, err := repo.ApplyBatch(ctx, test.operations(ids))
+
+			if test.failedCode != "" {
+				var batchErr *common.BatchOperationError
+				if !errors.As(err, &batchErr) || batchErr.Index != test.failedIndex || batchErr.Code != test.failedCode {
+					t.Fatalf("expected operation %d to fail with %s, got %v", test.failedIndex, test.failedCode, err)
+				}
+				// the rolled back batch doesn't use up the IDs
+				id, err := repo.Add(ctx, common.Reminder{Message: "next", RemindAt: later})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if id != ids[1]+1 {
+					t.Errorf("expected the next reminder to get ID %d, got %d", ids[1]+1, id)
+				}
+				repo.Delete(ctx, id)
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if expectedIds := test.expectedIds(ids); !slices.Equal(batchIds, expectedIds) {
+					t.Errorf("expected IDs %v, got %v", expectedIds, batchIds)
+				}
+			}
+
+			reminders, err := repo.List(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := make(map[int64]string, len(reminders))
+			for _, reminder := range reminders {
+				actual[reminder.ID] = reminder.Message
+			}
+			if expected := test.expected(ids); !maps.Equal(actual, expected) {
+				t.Errorf("expected reminders %v, got %v", expected, actual)
+			}
+		})
+	}
+}
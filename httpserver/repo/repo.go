@@ -1,19 +1,27 @@
 package repo
 
 import (
+	"context"
 	"n0rdy.foo/remindme/common"
 	"time"
 )
 
 type ReminderRepo interface {
-	Add(reminder common.Reminder) (int64, error)
-	Update(reminder common.Reminder) error
-	List() ([]common.Reminder, error)
-	Get(id int64) (*common.Reminder, error)
-	DeleteAll() error
-	Delete(id int64) error
-	Exists(id int64) (bool, error)
-	DeleteAllWithRemindAtBefore(threshold time.Time) ([]int64, error)
-	GetRemindersAfter(threshold time.Time) ([]common.Reminder, error)
+	Add(ctx context.Context, reminder common.Reminder) (int64, error)
+	Update(ctx context.Context, reminder common.Reminder) error
+	List(ctx context.Context) ([]common.Reminder, error)
+	Get(ctx context.Context, id int64) (*common.Reminder, error)
+	DeleteAll(ctx context.Context) error
+	Delete(ctx context.Context, id int64) error
+	Exists(ctx context.Context, id int64) (bool, error)
+	DeleteAllWithRemindAtBefore(ctx context.Context, threshold time.Time) ([]int64, error)
+	GetRemindersAfter(ctx context.Context, threshold time.Time) ([]common.Reminder, error)
+	// ApplyBatch applies all the operations within a single transaction: either all of them are applied, or none.
+	// Returns the IDs of the affected reminders in the order of the operations,
+	// or *common.BatchOperationError if one of the operations can't be applied.
+	ApplyBatch(ctx context.Context, operations []common.BatchOperation) ([]int64, error)
+	// Restore deletes the reminders by the IDs, if they exist, and puts the provided ones back with their IDs and timestamps,
+	// either replacing the existing ones or re-creating the deleted ones, within a single transaction: used to undo the operations
+	Restore(ctx context.Context, deleteIds []int64, reminders []common.Reminder) error
 	Close() error
 }
@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"n0rdy.foo/remindme/common"
@@ -17,7 +18,7 @@ type sqliteReminderRepo struct {
 }
 
 func NewSqliteReminderRepo() (repo.ReminderRepo, error) {
-	db, err := sql.Open("sqlite", utils.GetOsSpecificAppDataDir()+"remindme.db")
+	db, err := sql.Open("sqlite", utils.GetOsSpecificAppDataDir()+common.DbFileName)
 	if err != nil {
 		return nil, err
 	}
@@ -33,18 +34,34 @@ func NewSqliteReminderRepo() (repo.ReminderRepo, error) {
 	`)
 
 	if err != nil {
+		// the app falls back to the in-memory repo, so the DB shouldn't stay open
+		db.Close()
 		return nil, err
 	}
 
+	// the columns missing in the DB created by the previous app versions
+	for _, column := range []struct{ name, definition string }{
+		{"urgency", "TEXT NOT NULL DEFAULT ''"},
+		{"created_at", "INTEGER NOT NULL DEFAULT 0"},
+		{"updated_at", "INTEGER NOT NULL DEFAULT 0"},
+	} {
+		err = addColumn(db, column.name, column.definition)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
 	logger.Info("SQLite DB schema created")
 
 	return &sqliteReminderRepo{db: db}, nil
 }
 
-func (repo *sqliteReminderRepo) Add(reminder common.Reminder) (int64, error) {
-	res, err := repo.db.Exec(`
-		INSERT INTO reminders (message, remind_at) VALUES (?, ?);
-	`, reminder.Message, reminder.RemindAt.Unix())
+func (repo *sqliteReminderRepo) Add(ctx context.Context, reminder common.Reminder) (int64, error) {
+	now := time.Now().Unix()
+	res, err := repo.db.ExecContext(ctx, `
+		INSERT INTO reminders (message, remind_at, urgency, created_at, updated_at) VALUES (?, ?, ?, ?, ?);
+	`, reminder.Message, reminder.RemindAt.Unix(), reminder.Urgency, now, now)
 	if err != nil {
 		return 0, err
 	}
@@ -52,16 +69,16 @@ func (repo *sqliteReminderRepo) Add(reminder common.Reminder) (int64, error) {
 	return res.LastInsertId()
 }
 
-func (repo *sqliteReminderRepo) Update(reminder common.Reminder) error {
-	_, err := repo.db.Exec(`
-		UPDATE reminders SET message = ?, remind_at = ? WHERE id = ?;
-	`, reminder.Message, reminder.RemindAt.Unix(), reminder.ID)
+func (repo *sqliteReminderRepo) Update(ctx context.Context, reminder common.Reminder) error {
+	_, err := repo.db.ExecContext(ctx, `
+		UPDATE reminders SET message = ?, remind_at = ?, urgency = ?, updated_at = ? WHERE id = ?;
+	`, reminder.Message, reminder.RemindAt.Unix(), reminder.Urgency, time.Now().Unix(), reminder.ID)
 	return err
 }
 
-func (repo *sqliteReminderRepo) List() ([]common.Reminder, error) {
-	rows, err := repo.db.Query(`
-		SELECT id, message, remind_at FROM reminders;
+func (repo *sqliteReminderRepo) List(ctx context.Context) ([]common.Reminder, error) {
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT id, message, remind_at, urgency, created_at, updated_at FROM reminders;
 	`)
 
 	if err != nil {
@@ -74,30 +91,39 @@ func (repo *sqliteReminderRepo) List() ([]common.Reminder, error) {
 		var id int64
 		var message string
 		var remindAt int64
+		var urgency string
+		var createdAt int64
+		var updatedAt int64
 
-		err := rows.Scan(&id, &message, &remindAt)
+		err := rows.Scan(&id, &message, &remindAt, &urgency, &createdAt, &updatedAt)
 		if err != nil {
 			return nil, err
 		}
 		reminders = append(reminders, common.Reminder{
-			ID:       id,
-			Message:  message,
-			RemindAt: time.Unix(remindAt, 0),
+			ID:        id,
+			Message:   message,
+			RemindAt:  time.Unix(remindAt, 0),
+			Urgency:   urgency,
+			CreatedAt: fromUnix(createdAt),
+			UpdatedAt: fromUnix(updatedAt),
 		})
 	}
 	return reminders, nil
 }
 
-func (repo *sqliteReminderRepo) Get(id int64) (*common.Reminder, error) {
-	row := repo.db.QueryRow(`
-		SELECT id, message, remind_at FROM reminders WHERE id = ?;
+func (repo *sqliteReminderRepo) Get(ctx context.Context, id int64) (*common.Reminder, error) {
+	row := repo.db.QueryRowContext(ctx, `
+		SELECT id, message, remind_at, urgency, created_at, updated_at FROM reminders WHERE id = ?;
 	`, id)
 
 	var reminderId int64
 	var reminderMessage string
 	var remindAtUnix int64
+	var reminderUrgency string
+	var createdAtUnix int64
+	var updatedAtUnix int64
 
-	err := row.Scan(&reminderId, &reminderMessage, &remindAtUnix)
+	err := row.Scan(&reminderId, &reminderMessage, &remindAtUnix, &reminderUrgency, &createdAtUnix, &updatedAtUnix)
 	if err != nil {
 		// no rows required a special handling as it's not an error, but rather a DB state
 		if errors.Is(err, sql.ErrNoRows) {
@@ -106,28 +132,31 @@ func (repo *sqliteReminderRepo) Get(id int64) (*common.Reminder, error) {
 		return nil, err
 	}
 	return &common.Reminder{
-		ID:       reminderId,
-		Message:  reminderMessage,
-		RemindAt: time.Unix(remindAtUnix, 0),
+		ID:        reminderId,
+		Message:   reminderMessage,
+		RemindAt:  time.Unix(remindAtUnix, 0),
+		Urgency:   reminderUrgency,
+		CreatedAt: fromUnix(createdAtUnix),
+		UpdatedAt: fromUnix(updatedAtUnix),
 	}, nil
 }
 
-func (repo *sqliteReminderRepo) DeleteAll() error {
-	_, err := repo.db.Exec(`
+func (repo *sqliteReminderRepo) DeleteAll(ctx context.Context) error {
+	_, err := repo.db.ExecContext(ctx, `
 		DELETE FROM reminders;
 	`)
 	return err
 }
 
-func (repo *sqliteReminderRepo) Delete(id int64) error {
-	_, err := repo.db.Exec(`
+func (repo *sqliteReminderRepo) Delete(ctx context.Context, id int64) error {
+	_, err := repo.db.ExecContext(ctx, `
 		DELETE FROM reminders WHERE id = ?;
 	`, id)
 	return err
 }
 
-func (repo *sqliteReminderRepo) Exists(id int64) (bool, error) {
-	row := repo.db.QueryRow(`
+func (repo *sqliteReminderRepo) Exists(ctx context.Context, id int64) (bool, error) {
+	row := repo.db.QueryRowContext(ctx, `
 		SELECT id FROM reminders WHERE id = ?;
 	`, id)
 
@@ -143,8 +172,8 @@ func (repo *sqliteReminderRepo) Exists(id int64) (bool, error) {
 	return true, nil
 }
 
-func (repo *sqliteReminderRepo) DeleteAllWithRemindAtBefore(threshold time.Time) ([]int64, error) {
-	rows, err := repo.db.Query(`
+func (repo *sqliteReminderRepo) DeleteAllWithRemindAtBefore(ctx context.Context, threshold time.Time) ([]int64, error) {
+	rows, err := repo.db.QueryContext(ctx, `
 		SELECT id FROM reminders WHERE remind_at < ?;
 	`, threshold.Unix())
 
@@ -163,15 +192,15 @@ func (repo *sqliteReminderRepo) DeleteAllWithRemindAtBefore(threshold time.Time)
 		ids = append(ids, id)
 	}
 
-	_, err = repo.db.Exec(`
+	_, err = repo.db.ExecContext(ctx, `
 		DELETE FROM reminders WHERE remind_at < ?;
 	`, threshold.Unix())
 	return ids, err
 }
 
-func (repo *sqliteReminderRepo) GetRemindersAfter(threshold time.Time) ([]common.Reminder, error) {
-	rows, err := repo.db.Query(`
-		SELECT id, message, remind_at FROM reminders WHERE remind_at > ?;
+func (repo *sqliteReminderRepo) GetRemindersAfter(ctx context.Context, threshold time.Time) ([]common.Reminder, error) {
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT id, message, remind_at, urgency, created_at, updated_at FROM reminders WHERE remind_at > ?;
 	`, threshold.Unix())
 
 	if err != nil {
@@ -184,20 +213,166 @@ func (repo *sqliteReminderRepo) GetRemindersAfter(threshold time.Time) ([]common
 		var id int64
 		var message string
 		var remindAt int64
+		var urgency string
+		var createdAt int64
+		var updatedAt int64
 
-		err := rows.Scan(&id, &message, &remindAt)
+		err := rows.Scan(&id, &message, &remindAt, &urgency, &createdAt, &updatedAt)
 		if err != nil {
 			return nil, err
 		}
 		reminders = append(reminders, common.Reminder{
-			ID:       id,
-			Message:  message,
-			RemindAt: time.Unix(remindAt, 0),
+			ID:        id,
+			Message:   message,
+			RemindAt:  time.Unix(remindAt, 0),
+			Urgency:   urgency,
+			CreatedAt: fromUnix(createdAt),
+			UpdatedAt: fromUnix(updatedAt),
 		})
 	}
 	return reminders, nil
 }
 
+func (repo *sqliteReminderRepo) ApplyBatch(ctx context.Context, operations []common.BatchOperation) ([]int64, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// no-op if the transaction has been committed already
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	ids := make([]int64, len(operations))
+	for i, operation := range operations {
+		switch operation.Type {
+		case common.BatchOperationCreate:
+			res, err := tx.ExecContext(ctx, `
+				INSERT INTO reminders (message, remind_at, urgency, created_at, updated_at) VALUES (?, ?, ?, ?, ?);
+			`, operation.Reminder.Message, operation.Reminder.RemindAt.Unix(), operation.Reminder.Urgency, now, now)
+			if err != nil {
+				return nil, err
+			}
+
+			id, err := res.LastInsertId()
+			if err != nil {
+				return nil, err
+			}
+			ids[i] = id
+		case common.BatchOperationUpdate:
+			res, err := tx.ExecContext(ctx, `
+				UPDATE reminders SET message = ?, remind_at = ?, urgency = ?, updated_at = ? WHERE id = ?;
+			`, operation.Reminder.Message, operation.Reminder.RemindAt.Unix(), operation.Reminder.Urgency, now, operation.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			err = requireAffectedRow(res, i)
+			if err != nil {
+				return nil, err
+			}
+			ids[i] = operation.ID
+		case common.BatchOperationDelete:
+			res, err := tx.ExecContext(ctx, `
+				DELETE FROM reminders WHERE id = ?;
+			`, operation.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			err = requireAffectedRow(res, i)
+			if err != nil {
+				return nil, err
+			}
+			ids[i] = operation.ID
+		default:
+			return nil, &common.BatchOperationError{Index: i, Code: common.ErrCodeBatchOperationType}
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (repo *sqliteReminderRepo) Restore(ctx context.Context, deleteIds []int64, reminders []common.Reminder) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// no-op if the transaction has been committed already
+	defer tx.Rollback()
+
+	for _, id := range deleteIds {
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM reminders WHERE id = ?;
+		`, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, reminder := range reminders {
+		// the IDs are never reused due to AUTOINCREMENT, so the deleted reminder can be re-created with its ID
+		_, err = tx.ExecContext(ctx, `
+			INSERT OR REPLACE INTO reminders (id, message, remind_at, urgency, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?);
+		`, reminder.ID, reminder.Message, reminder.RemindAt.Unix(), reminder.Urgency, toUnix(reminder.CreatedAt), toUnix(reminder.UpdatedAt))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (repo *sqliteReminderRepo) Close() error {
 	return repo.db.Close()
 }
+
+// addColumn migrates the DB created by the previous app versions, which didn't have the column
+func addColumn(db *sql.DB, name string, definition string) error {
+	row := db.QueryRow(`
+		SELECT COUNT(*) FROM pragma_table_info('reminders') WHERE name = ?;
+	`, name)
+
+	var count int
+	err := row.Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	// the column name and definition are constants, so they are safe to be concatenated
+	_, err = db.Exec(`
+		ALTER TABLE reminders ADD COLUMN ` + name + ` ` + definition + `;
+	`)
+	return err
+}
+
+// fromUnix returns the zero time for the timestamps missing in the rows created by the previous app versions
+func fromUnix(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+// toUnix keeps the zero timestamps of the rows created by the previous app versions, as fromUnix expects
+func toUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// requireAffectedRow fails the batch operation if it hasn't found the reminder to update/delete
+func requireAffectedRow(res sql.Result, operationIndex int) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return &common.BatchOperationError{Index: operationIndex, Code: common.ErrCodeReminderNotFound}
+	}
+	return nil
+}
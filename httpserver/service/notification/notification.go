@@ -1,17 +1,55 @@
 package notification
 
 import (
+	"errors"
 	"github.com/gen2brain/beeep"
 	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/httpserver/metrics"
+	"time"
 )
 
 type Notifier struct {
+	backends []string
 }
 
-func NewNotifier() Notifier {
-	return Notifier{}
+func NewNotifier(backends []string) Notifier {
+	return Notifier{backends: backends}
 }
 
+// Notify sends the notification via all the backends, even if some of them fail
 func (receiver Notifier) Notify(reminder common.Reminder) error {
-	return beeep.Notify("Reminder", reminder.Message, "")
+	errs := make([]error, 0)
+	for _, backend := range receiver.backends {
+		var err error
+		switch backend {
+		case common.DesktopNotificationBackend:
+			err = beeep.Notify("Reminder", reminder.Message, "")
+		case common.AlertNotificationBackend:
+			err = beeep.Alert("Reminder", reminder.Message, "")
+		case common.BeepNotificationBackend:
+			err = beeep.Beep(beeep.DefaultFreq, beeep.DefaultDuration)
+		default:
+			err = errors.New("unknown notification backend")
+		}
+
+		receiver.observe(backend, reminder, err)
+		if err != nil {
+			errs = append(errs, errors.New(backend+": "+err.Error()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifySilently sends the desktop notification only, without the alert and beep ones that demand attention
+func (receiver Notifier) NotifySilently(reminder common.Reminder) error {
+	return NewNotifier([]string{common.DesktopNotificationBackend}).Notify(reminder)
+}
+
+func (receiver Notifier) observe(backend string, reminder common.Reminder, err error) {
+	if err != nil {
+		metrics.NotificationsFailed.Inc(backend)
+		return
+	}
+	metrics.NotificationsSent.Inc(backend)
+	metrics.NotificationDelay.Observe(time.Since(reminder.RemindAt).Seconds(), backend)
 }
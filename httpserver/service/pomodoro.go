@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"strconv"
+	"time"
+)
+
+// StartPomodoro schedules the whole chain of the work and break reminders, and records it to the journal as a single operation,
+// so that the undo cancels the whole chain: returns common.ErrPomodoroAlreadyRunning if the previous session is still running
+func (rs *ReminderService) StartPomodoro(ctx context.Context, work time.Duration, breakDuration time.Duration, cycles int) (*common.Pomodoro, error) {
+	rs.pomodoroMu.Lock()
+	defer rs.pomodoroMu.Unlock()
+
+	if rs.Pomodoro() != nil {
+		return nil, common.ErrPomodoroAlreadyRunning
+	}
+
+	// the reminders are persisted with the seconds precision
+	startedAt := time.Now().Truncate(time.Second)
+	pomodoro := common.Pomodoro{
+		WorkSeconds:  int64(work.Seconds()),
+		BreakSeconds: int64(breakDuration.Seconds()),
+		Cycles:       cycles,
+		StartedAt:    startedAt,
+		Intervals:    make([]common.PomodoroInterval, 0, 2*cycles-1),
+	}
+	endsAt := startedAt
+	for cycle := 1; cycle <= cycles; cycle++ {
+		endsAt = endsAt.Add(work)
+		pomodoro.Intervals = append(pomodoro.Intervals, common.PomodoroInterval{Type: common.PomodoroIntervalWork, Cycle: cycle, EndsAt: endsAt})
+		// the last work interval ends the session
+		if cycle < cycles {
+			endsAt = endsAt.Add(breakDuration)
+			pomodoro.Intervals = append(pomodoro.Intervals, common.PomodoroInterval{Type: common.PomodoroIntervalBreak, Cycle: cycle, EndsAt: endsAt})
+		}
+	}
+
+	created := make([]common.Reminder, 0, len(pomodoro.Intervals))
+	for i, interval := range pomodoro.Intervals {
+		reminder, err := rs.set(ctx, common.Reminder{Message: pomodoroMessage(pomodoro, interval), RemindAt: interval.EndsAt})
+		if err != nil {
+			// the chain is scheduled either as a whole or not at all
+			for _, c := range created {
+				_, _, cancelErr := rs.cancel(ctx, c.ID)
+				if cancelErr != nil {
+					logger.ErrorContext(ctx, "error happened on trying to cancel the pomodoro reminder "+strconv.FormatInt(c.ID, 10)+" of the chain that has failed to be scheduled", cancelErr)
+				}
+			}
+			return nil, err
+		}
+		pomodoro.Intervals[i].ReminderID = reminder.ID
+		created = append(created, reminder)
+	}
+	rs.record(common.JournalOperationCreate, created, nil)
+
+	rs.mu.Lock()
+	rs.pomodoro = &pomodoro
+	rs.mu.Unlock()
+
+	logger.InfoContext(ctx, "pomodoro started: "+strconv.Itoa(cycles)+" cycles till "+endsAt.Format(time.RFC3339))
+	return rs.Pomodoro(), nil
+}
+
+// Pomodoro returns the running session with the intervals that are still scheduled, or nil if no session is running
+func (rs *ReminderService) Pomodoro() *common.Pomodoro {
+	pomodoro := rs.scheduledPomodoro()
+	if pomodoro == nil || len(pomodoro.Intervals) == 0 {
+		return nil
+	}
+	return pomodoro
+}
+
+// SkipPomodoroInterval ends the current interval right away without notifying about it, and moves the rest of the chain earlier by the time left.
+// Returns the session with the intervals left, which are none if the last one has been skipped, and whether the session has been running.
+func (rs *ReminderService) SkipPomodoroInterval(ctx context.Context) (*common.Pomodoro, bool, error) {
+	rs.pomodoroMu.Lock()
+	defer rs.pomodoroMu.Unlock()
+
+	running := rs.Pomodoro()
+	if running == nil {
+		return nil, false, nil
+	}
+
+	current := running.Intervals[0]
+	timeLeft := time.Until(current.EndsAt)
+	before := make([]common.Reminder, 0, len(running.Intervals))
+	defer func() {
+		// recorded even if the chain has been moved partially, so that it can be restored
+		if len(before) > 0 {
+			rs.record(common.JournalOperationBatch, nil, before)
+		}
+	}()
+
+	canceled, _, err := rs.cancel(ctx, current.ReminderID)
+	if err != nil {
+		return nil, true, err
+	}
+	if canceled != nil {
+		before = append(before, *canceled)
+	}
+
+	if timeLeft > 0 {
+		for _, interval := range running.Intervals[1:] {
+			reminder, err := rs.repo.Get(ctx, interval.ReminderID)
+			if err != nil {
+				return nil, true, countRepoError("get", err)
+			}
+			// canceled in the meantime
+			if reminder == nil {
+				continue
+			}
+
+			reminder.RemindAt = reminder.RemindAt.Add(-timeLeft)
+			changed, err := rs.change(ctx, reminder.ID, *reminder)
+			if err != nil {
+				return nil, true, err
+			}
+			if changed != nil {
+				before = append(before, *changed)
+			}
+		}
+	}
+
+	logger.InfoContext(ctx, "pomodoro "+current.Type+" interval of cycle "+strconv.Itoa(current.Cycle)+" skipped")
+	// the session can't be replaced while the pomodoro lock is held, so it's never nil here
+	return rs.scheduledPomodoro(), true, nil
+}
+
+// StopPomodoro cancels the rest of the chain, keeping the other reminders as is, and records it to the journal as a single operation.
+// Returns the session with the canceled intervals, or nil if no session is running.
+func (rs *ReminderService) StopPomodoro(ctx context.Context) (*common.Pomodoro, error) {
+	rs.pomodoroMu.Lock()
+	defer rs.pomodoroMu.Unlock()
+
+	running := rs.Pomodoro()
+	if running == nil {
+		return nil, nil
+	}
+
+	before := make([]common.Reminder, 0, len(running.Intervals))
+	var err error
+	for _, interval := range running.Intervals {
+		var canceled *common.Reminder
+		canceled, _, err = rs.cancel(ctx, interval.ReminderID)
+		if err != nil {
+			break
+		}
+		if canceled != nil {
+			before = append(before, *canceled)
+		}
+	}
+	// recorded even if the chain has been canceled partially, so that it can be restored
+	if len(before) > 0 {
+		rs.record(common.JournalOperationCancel, nil, before)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "pomodoro stopped: "+strconv.Itoa(len(before))+" reminders canceled")
+	return running, nil
+}
+
+// scheduledPomodoro returns the copy of the latest session with the intervals that are still scheduled, and their current times,
+// as they might have been changed or deferred: returns nil if no session has been started
+func (rs *ReminderService) scheduledPomodoro() *common.Pomodoro {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.pomodoro == nil {
+		return nil
+	}
+
+	pomodoro := *rs.pomodoro
+	pomodoro.Intervals = make([]common.PomodoroInterval, 0, len(rs.pomodoro.Intervals))
+	for _, interval := range rs.pomodoro.Intervals {
+		if reminder, scheduled := rs.rmdIdToReminder[interval.ReminderID]; scheduled {
+			interval.EndsAt = reminder.RemindAt
+			pomodoro.Intervals = append(pomodoro.Intervals, interval)
+		}
+	}
+	return &pomodoro
+}
+
+// pomodoroMessage describes what's next once the interval is over
+func pomodoroMessage(pomodoro common.Pomodoro, interval common.PomodoroInterval) string {
+	if interval.Type == common.PomodoroIntervalBreak {
+		return fmt.Sprintf("Break is over: back to work for pomodoro %d/%d", interval.Cycle+1, pomodoro.Cycles)
+	}
+	if interval.Cycle == pomodoro.Cycles {
+		return fmt.Sprintf("Pomodoro %d/%d done: the session is over", interval.Cycle, pomodoro.Cycles)
+	}
+	breakDuration := time.Duration(pomodoro.BreakSeconds) * time.Second
+	return fmt.Sprintf("Pomodoro %d/%d done: take a %s break", interval.Cycle, pomodoro.Cycles, utils.HumanizeDuration(breakDuration))
+}
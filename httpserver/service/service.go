@@ -1,143 +1,830 @@
 package service
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/httpserver/metrics"
 	"n0rdy.foo/remindme/httpserver/repo"
 	"n0rdy.foo/remindme/httpserver/service/notification"
 	"n0rdy.foo/remindme/logger"
+	"slices"
 	"strconv"
+	"sync"
 	"time"
 )
 
+const (
+	// the number of the most recent notifications kept in the history
+	notificationHistoryLimit = 100
+	// the number of the most recent mutating operations that can be undone
+	journalLimit = 20
+)
+
 type ReminderService struct {
 	repo         repo.ReminderRepo
 	notifier     notification.Notifier
 	rmdIdToTimer map[int64]*time.Timer
+	// the reminders the timers are scheduled for, to find the next one
+	rmdIdToReminder  map[int64]common.Reminder
+	lastNextReminder *common.NextReminderState
+	// holds the latest next reminder state to be published by the publisher goroutine outside the lock, e.g. to the file the shell prompt reads:
+	// nil if nothing is published, or once the service is shut down
+	nextReminderCh chan common.NextReminderState
+	// closed once the publisher goroutine has published the last state
+	publisherDone chan struct{}
+	// guards the timers map, the scheduler stats and the configs below, as timers fire in their own goroutines
+	mu               sync.Mutex
+	lastNotification *common.NotificationResult
+	// the most recent notifications, the oldest first
+	notifications []common.NotificationResult
+	lastCleanupAt *time.Time
+	quietHours    *common.QuietHours
+	dndUntil      *time.Time
+	// the times the reminders have been deferred to during the do-not-disturb period, to notify them right away if it's turned off earlier
+	dndDeferred map[int64]time.Time
+	// the most recent mutating operations to be undone, the oldest first
+	journal       []common.JournalEntry
+	lastJournalId int64
+	// the latest pomodoro session: it's running while any of its reminders is scheduled
+	pomodoro *common.Pomodoro
+	// serializes the pomodoro operations, as each of them spans several reminders
+	pomodoroMu sync.Mutex
+	// set on shutdown: no timers are scheduled or fired after that
+	stopped bool
+	// the notifications being sent, so that the shutdown can wait for them
+	inFlight sync.WaitGroup
 }
 
-func (rs *ReminderService) GetAll() ([]common.Reminder, error) {
-	return rs.repo.List()
+func (rs *ReminderService) GetAll(ctx context.Context) ([]common.Reminder, error) {
+	reminders, err := rs.repo.List(ctx)
+	return reminders, countRepoError("list", err)
 }
 
-func (rs *ReminderService) Get(id int64) (*common.Reminder, error) {
-	return rs.repo.Get(id)
+func (rs *ReminderService) Get(ctx context.Context, id int64) (*common.Reminder, error) {
+	reminder, err := rs.repo.Get(ctx, id)
+	return reminder, countRepoError("get", err)
 }
 
-func (rs *ReminderService) Set(reminder common.Reminder) error {
-	id, err := rs.repo.Add(reminder)
+func (rs *ReminderService) Set(ctx context.Context, reminder common.Reminder) error {
+	created, err := rs.set(ctx, reminder)
 	if err != nil {
 		return err
 	}
+	rs.record(common.JournalOperationCreate, []common.Reminder{created}, nil)
+	return nil
+}
+
+// set adds and schedules the reminder without recording it to the journal, so that the group operations are recorded as a single one
+func (rs *ReminderService) set(ctx context.Context, reminder common.Reminder) (common.Reminder, error) {
+	id, err := rs.repo.Add(ctx, reminder)
+	if err != nil {
+		return reminder, countRepoError("add", err)
+	}
 
 	reminder.ID = id
-	rs.setTimer(reminder)
-	return nil
+	rs.setTimer(ctx, reminder)
+	logger.DebugContext(ctx, "reminder "+strconv.FormatInt(id, 10)+" scheduled at "+reminder.RemindAt.Format(time.RFC3339))
+	return reminder, nil
 }
 
-func (rs *ReminderService) CancelAll() error {
-	err := rs.repo.DeleteAll()
+func (rs *ReminderService) CancelAll(ctx context.Context) error {
+	before, err := rs.repo.List(ctx)
 	if err != nil {
-		return err
+		return countRepoError("list", err)
 	}
 
+	err = rs.repo.DeleteAll(ctx)
+	if err != nil {
+		return countRepoError("delete_all", err)
+	}
+	if len(before) > 0 {
+		rs.record(common.JournalOperationCancelAll, nil, before)
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
 	for _, timer := range rs.rmdIdToTimer {
 		timer.Stop()
 	}
 	rs.rmdIdToTimer = make(map[int64]*time.Timer, 0)
+	rs.rmdIdToReminder = make(map[int64]common.Reminder, 0)
+	rs.scheduleChanged()
+	logger.DebugContext(ctx, "all reminders canceled")
 	return nil
 }
 
-func (rs *ReminderService) Cancel(reminderId int64) (bool, error) {
-	exists, err := rs.repo.Exists(reminderId)
-	if err != nil {
+func (rs *ReminderService) Cancel(ctx context.Context, reminderId int64) (bool, error) {
+	before, canceled, err := rs.cancel(ctx, reminderId)
+	if err != nil || before == nil {
 		return false, err
 	}
-	if !exists {
-		return false, nil
-	}
+	rs.record(common.JournalOperationCancel, nil, []common.Reminder{*before})
+	return canceled, nil
+}
 
-	err = rs.repo.Delete(reminderId)
+// cancel is the same as Cancel, but doesn't record the operation to the journal: returns the canceled reminder, or nil if it doesn't exist
+func (rs *ReminderService) cancel(ctx context.Context, reminderId int64) (*common.Reminder, bool, error) {
+	before, err := rs.repo.Get(ctx, reminderId)
 	if err != nil {
-		return false, err
+		return nil, false, countRepoError("get", err)
+	}
+	if before == nil {
+		return nil, false, nil
 	}
 
-	var stopped = false
-	if timer, found := rs.rmdIdToTimer[reminderId]; found {
-		stopped = timer.Stop()
+	err = rs.repo.Delete(ctx, reminderId)
+	if err != nil {
+		return nil, false, countRepoError("delete", err)
 	}
-	delete(rs.rmdIdToTimer, reminderId)
 
-	return stopped, nil
+	logger.DebugContext(ctx, "reminder "+strconv.FormatInt(reminderId, 10)+" canceled")
+	return before, rs.stopTimer(reminderId), nil
 }
 
-func (rs *ReminderService) Change(reminderId int64, reminder common.Reminder) error {
-	reminder.ID = reminderId
-	err := rs.repo.Update(reminder)
+func (rs *ReminderService) Change(ctx context.Context, reminderId int64, reminder common.Reminder) error {
+	before, err := rs.change(ctx, reminderId, reminder)
 	if err != nil {
 		return err
 	}
-
-	if timer, found := rs.rmdIdToTimer[reminderId]; found {
-		timer.Stop()
+	// nothing has been changed if the reminder doesn't exist
+	if before != nil {
+		rs.record(common.JournalOperationChange, nil, []common.Reminder{*before})
 	}
-	rs.setTimer(reminder)
 	return nil
 }
 
+// change is the same as Change, but doesn't record the operation to the journal: returns the reminder as it was before the change
+func (rs *ReminderService) change(ctx context.Context, reminderId int64, reminder common.Reminder) (*common.Reminder, error) {
+	reminder.ID = reminderId
+	before, err := rs.repo.Get(ctx, reminderId)
+	if err != nil {
+		return nil, countRepoError("get", err)
+	}
+
+	err = rs.repo.Update(ctx, reminder)
+	if err != nil {
+		return nil, countRepoError("update", err)
+	}
+
+	rs.stopTimer(reminderId)
+	rs.setTimer(ctx, reminder)
+	logger.DebugContext(ctx, "reminder "+strconv.FormatInt(reminderId, 10)+" rescheduled at "+reminder.RemindAt.Format(time.RFC3339))
+	return before, nil
+}
+
+// ApplyBatch applies all the operations atomically, and (re)schedules the timers only if the whole batch succeeded
+func (rs *ReminderService) ApplyBatch(ctx context.Context, operations []common.BatchOperation) ([]int64, error) {
+	before := make([]common.Reminder, 0)
+	for _, operation := range operations {
+		if operation.Type != common.BatchOperationUpdate && operation.Type != common.BatchOperationDelete {
+			continue
+		}
+		// the missing reminder fails the whole batch anyway
+		reminder, err := rs.repo.Get(ctx, operation.ID)
+		if err != nil {
+			return nil, countRepoError("get", err)
+		}
+		if reminder != nil {
+			before = append(before, *reminder)
+		}
+	}
+
+	ids, err := rs.repo.ApplyBatch(ctx, operations)
+	if err != nil {
+		var batchErr *common.BatchOperationError
+		if errors.As(err, &batchErr) {
+			// the batch is invalid, rather than the repo failed
+			return nil, err
+		}
+		return nil, countRepoError("apply_batch", err)
+	}
+
+	created := make([]common.Reminder, 0)
+	for i, operation := range operations {
+		switch operation.Type {
+		case common.BatchOperationCreate:
+			reminder := *operation.Reminder
+			reminder.ID = ids[i]
+			rs.setTimer(ctx, reminder)
+			created = append(created, reminder)
+		case common.BatchOperationUpdate:
+			reminder := *operation.Reminder
+			reminder.ID = ids[i]
+			rs.stopTimer(reminder.ID)
+			rs.setTimer(ctx, reminder)
+		case common.BatchOperationDelete:
+			rs.stopTimer(ids[i])
+		}
+	}
+	rs.record(batchJournalOperation(operations), created, before)
+	return ids, nil
+}
+
+// Journal returns the most recent mutating operations that can be undone, the latest first
+func (rs *ReminderService) Journal() []common.JournalEntry {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	journal := make([]common.JournalEntry, 0, len(rs.journal))
+	for i := len(rs.journal) - 1; i >= 0; i-- {
+		journal = append(journal, rs.journal[i])
+	}
+	return journal
+}
+
+// Undo reverts the journal entry with the ID, or the latest one if the ID is 0, and reschedules the restored reminders:
+// returns nil if there is no such entry.
+// The older entry is rejected with common.ErrJournalEntryConflict if any of the newer ones has touched the same reminders,
+// as restoring them would silently discard the newer changes: those should be undone first.
+// The reminders due in the meantime are notified about right away.
+func (rs *ReminderService) Undo(ctx context.Context, entryId int64) (*common.JournalEntry, error) {
+	// the entry is taken out of the journal first, so that it can't be undone twice by the concurrent requests
+	entry, err := rs.takeJournalEntry(entryId)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	createdIds := make([]int64, 0, len(entry.Created))
+	for _, reminder := range entry.Created {
+		createdIds = append(createdIds, reminder.ID)
+	}
+	err = rs.repo.Restore(ctx, createdIds, entry.Before)
+	if err != nil {
+		rs.putJournalEntry(*entry)
+		return nil, countRepoError("restore", err)
+	}
+
+	for _, id := range createdIds {
+		rs.stopTimer(id)
+	}
+	for _, reminder := range entry.Before {
+		rs.stopTimer(reminder.ID)
+		rs.setTimer(ctx, reminder)
+	}
+	logger.InfoContext(ctx, "journal entry "+strconv.FormatInt(entry.ID, 10)+" undone: "+entry.Operation)
+	return entry, nil
+}
+
+// record adds the operation to the journal, evicting the oldest entry if the limit is reached
+func (rs *ReminderService) record(operation string, created []common.Reminder, before []common.Reminder) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.lastJournalId++
+	rs.journal = append(rs.journal, common.JournalEntry{
+		ID:        rs.lastJournalId,
+		Operation: operation,
+		At:        time.Now(),
+		Created:   created,
+		Before:    before,
+	})
+	if len(rs.journal) > journalLimit {
+		rs.journal = rs.journal[len(rs.journal)-journalLimit:]
+	}
+}
+
+// takeJournalEntry removes the entry from the journal, unless any of the newer entries has touched the same reminders
+func (rs *ReminderService) takeJournalEntry(entryId int64) (*common.JournalEntry, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for i := len(rs.journal) - 1; i >= 0; i-- {
+		if entryId == 0 || rs.journal[i].ID == entryId {
+			entry := rs.journal[i]
+			if touchesSameReminders(entry, rs.journal[i+1:]) {
+				return nil, common.ErrJournalEntryConflict
+			}
+			rs.journal = slices.Delete(rs.journal, i, i+1)
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func touchesSameReminders(entry common.JournalEntry, newer []common.JournalEntry) bool {
+	ids := make(map[int64]struct{}, len(entry.Created)+len(entry.Before))
+	for _, reminder := range slices.Concat(entry.Created, entry.Before) {
+		ids[reminder.ID] = struct{}{}
+	}
+	for _, newerEntry := range newer {
+		for _, reminder := range slices.Concat(newerEntry.Created, newerEntry.Before) {
+			if _, found := ids[reminder.ID]; found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// putJournalEntry puts the entry back, if it hasn't been undone due to the error
+func (rs *ReminderService) putJournalEntry(entry common.JournalEntry) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	i, _ := slices.BinarySearchFunc(rs.journal, entry.ID, func(e common.JournalEntry, id int64) int {
+		return cmp.Compare(e.ID, id)
+	})
+	rs.journal = slices.Insert(rs.journal, i, entry)
+}
+
+// batchJournalOperation names the batch after its operations if all of them are of the same type
+func batchJournalOperation(operations []common.BatchOperation) string {
+	operation := ""
+	for _, batchOperation := range operations {
+		var journalOperation string
+		switch batchOperation.Type {
+		case common.BatchOperationCreate:
+			journalOperation = common.JournalOperationCreate
+		case common.BatchOperationUpdate:
+			journalOperation = common.JournalOperationChange
+		default:
+			journalOperation = common.JournalOperationCancel
+		}
+
+		if operation != "" && operation != journalOperation {
+			return common.JournalOperationBatch
+		}
+		operation = journalOperation
+	}
+	return operation
+}
+
 // in case if the the reminder wasn't deleted (e.g. due to the error or app being offline)
-func (rs *ReminderService) DeleteExpiredReminders() error {
+func (rs *ReminderService) DeleteExpiredReminders(ctx context.Context) error {
 	now := time.Now()
+	metrics.ExpiredCleanupRuns.Inc()
 
-	deletedIds, err := rs.repo.DeleteAllWithRemindAtBefore(now)
+	deletedIds, err := rs.repo.DeleteAllWithRemindAtBefore(ctx, now)
 	if err != nil {
-		return err
+		return countRepoError("delete_all_with_remind_at_before", err)
 	}
 
 	for _, id := range deletedIds {
-		if timer, found := rs.rmdIdToTimer[id]; found {
-			timer.Stop()
-		}
-		delete(rs.rmdIdToTimer, id)
+		rs.stopTimer(id)
 	}
+	metrics.ExpiredRemindersDeleted.Add(float64(len(deletedIds)))
+
+	rs.mu.Lock()
+	rs.lastCleanupAt = &now
+	rs.mu.Unlock()
 
-	logger.Info("deleteExpiredReminders job: finished")
+	logger.InfoContext(ctx, "deleteExpiredReminders job: finished")
 	return nil
 }
 
-func (rs *ReminderService) RestoreActiveReminders() error {
-	reminders, err := rs.repo.GetRemindersAfter(time.Now())
+func (rs *ReminderService) RestoreActiveReminders(ctx context.Context) error {
+	// the past due ones are kept, as they haven't been notified about: their timers fire right away
+	reminders, err := rs.repo.List(ctx)
 	if err != nil {
-		return err
+		return countRepoError("list", err)
 	}
 
 	for _, reminder := range reminders {
-		rs.setTimer(reminder)
+		rs.setTimer(ctx, reminder)
 	}
 
-	logger.Info("restoreActiveReminders: finished")
+	// published even if nothing is scheduled, so that the state left by the previous server is replaced
+	rs.mu.Lock()
+	rs.scheduleChanged()
+	rs.mu.Unlock()
+
+	logger.InfoContext(ctx, "restoreActiveReminders: finished")
 	return nil
 }
 
-func (rs *ReminderService) setTimer(reminder common.Reminder) {
-	reminderTimer := time.AfterFunc(reminder.RemindAt.Sub(time.Now()), func() {
-		err := rs.notifier.Notify(reminder)
+// ApplyConfigs replaces the notification backends and quiet hours: the already scheduled reminders are notified according to the new ones
+func (rs *ReminderService) ApplyConfigs(notificationBackends []string, quietHours *common.QuietHours) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.notifier = notification.NewNotifier(notificationBackends)
+	rs.quietHours = quietHours
+}
+
+// EnableDnd turns the do-not-disturb period on till the provided time, or changes its end if it's on already
+func (rs *ReminderService) EnableDnd(ctx context.Context, until time.Time) {
+	// the reminders are persisted with the seconds precision, so the deferred ones can be matched by their time later
+	until = until.Truncate(time.Second)
+
+	rs.mu.Lock()
+	deferred := rs.dndDeferred
+	rs.dndUntil = &until
+	rs.dndDeferred = make(map[int64]time.Time)
+	rs.mu.Unlock()
+
+	logger.InfoContext(ctx, "do-not-disturb turned on till "+until.Format(time.RFC3339))
+	// the reminders deferred by the previous period are rescheduled according to the new one
+	rs.releaseDndDeferred(ctx, deferred)
+}
+
+// DisableDnd turns the do-not-disturb period off: the reminders deferred by it are notified right away, unless it's the quiet hours
+func (rs *ReminderService) DisableDnd(ctx context.Context) {
+	rs.mu.Lock()
+	deferred := rs.dndDeferred
+	rs.dndUntil = nil
+	rs.dndDeferred = make(map[int64]time.Time)
+	rs.mu.Unlock()
+
+	logger.InfoContext(ctx, "do-not-disturb turned off")
+	rs.releaseDndDeferred(ctx, deferred)
+}
+
+// Shutdown stops all the timers, and waits for the notifications being sent till the context is done.
+// The reminders stay in the repo, so the persisted ones are scheduled again on the next start.
+func (rs *ReminderService) Shutdown(ctx context.Context) error {
+	rs.mu.Lock()
+	rs.stopped = true
+	for _, timer := range rs.rmdIdToTimer {
+		timer.Stop()
+	}
+	rs.rmdIdToTimer = make(map[int64]*time.Timer)
+	// nothing is going to be notified about till the next start
+	rs.rmdIdToReminder = make(map[int64]common.Reminder)
+	rs.scheduleChanged()
+	// the publisher goroutine exits once the last state is published
+	if rs.nextReminderCh != nil {
+		close(rs.nextReminderCh)
+		rs.nextReminderCh = nil
+	}
+	rs.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		rs.inFlight.Wait()
+		<-rs.publisherDone
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		logger.InfoContext(ctx, "scheduler stopped")
+		return nil
+	case <-ctx.Done():
+		logger.WarnContext(ctx, "scheduler stopped without waiting for the notifications being sent", ctx.Err())
+		return ctx.Err()
+	}
+}
+
+// Notifications returns the history of the most recent notifications, the latest first
+func (rs *ReminderService) Notifications() []common.NotificationResult {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	notifications := make([]common.NotificationResult, 0, len(rs.notifications))
+	for i := len(rs.notifications) - 1; i >= 0; i-- {
+		notifications = append(notifications, rs.notifications[i])
+	}
+	return notifications
+}
+
+// State returns the in-memory state of the scheduler to be persisted on shutdown
+func (rs *ReminderService) State() common.SchedulerState {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	state := common.SchedulerState{Notifications: rs.notifications, Journal: rs.journal, Pomodoro: rs.pomodoro}
+	if rs.dndUntil != nil && rs.dndUntil.After(time.Now()) {
+		state.DndUntil = rs.dndUntil
+		state.DndDeferred = rs.dndDeferred
+	}
+	return state
+}
+
+// RestoreState restores the state persisted by the previous server on its shutdown: should be called before restoring the reminders
+func (rs *ReminderService) RestoreState(ctx context.Context, state common.SchedulerState) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.notifications = state.Notifications
+	if len(rs.notifications) > 0 {
+		rs.lastNotification = &rs.notifications[len(rs.notifications)-1]
+	}
+	rs.journal = state.Journal
+	if len(rs.journal) > 0 {
+		rs.lastJournalId = rs.journal[len(rs.journal)-1].ID
+	}
+	rs.pomodoro = state.Pomodoro
+	if state.DndUntil != nil && state.DndUntil.After(time.Now()) {
+		rs.dndUntil = state.DndUntil
+		if state.DndDeferred != nil {
+			rs.dndDeferred = state.DndDeferred
+		}
+		logger.InfoContext(ctx, "do-not-disturb restored till "+state.DndUntil.Format(time.RFC3339))
+	}
+}
+
+// Status reports the current state of the scheduler
+func (rs *ReminderService) Status(ctx context.Context) (common.SchedulerStatus, error) {
+	upcoming, err := rs.repo.GetRemindersAfter(ctx, time.Now())
+	if err != nil {
+		return common.SchedulerStatus{}, countRepoError("get_reminders_after", err)
+	}
+
+	var nextReminder *common.Reminder
+	for i := range upcoming {
+		if nextReminder == nil || upcoming[i].RemindAt.Before(nextReminder.RemindAt) {
+			nextReminder = &upcoming[i]
+		}
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	status := common.SchedulerStatus{
+		ActiveTimers:     len(rs.rmdIdToTimer),
+		NextReminder:     nextReminder,
+		LastNotification: rs.lastNotification,
+		LastCleanupAt:    rs.lastCleanupAt,
+	}
+	if rs.dndUntil != nil && rs.dndUntil.After(time.Now()) {
+		status.DndUntil = rs.dndUntil
+	}
+	if quietUntil, within := rs.quietUntil(time.Now()); within {
+		status.QuietUntil = &quietUntil
+	}
+	return status, nil
+}
+
+// setTimer schedules the notification: its records are tagged with the ID of the request that has scheduled it, if any
+func (rs *ReminderService) setTimer(ctx context.Context, reminder common.Reminder) {
+	// the timer outlives the request, so its cancellation shouldn't affect the notification
+	notificationCtx := context.WithoutCancel(ctx)
+
+	// the timer is registered under the lock, so that its callback can't be run before that even if the time is in the past
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.stopped {
+		return
+	}
+
+	var reminderTimer *time.Timer
+	reminderTimer = time.AfterFunc(reminder.RemindAt.Sub(time.Now()), func() {
+		rs.mu.Lock()
+		if rs.stopped {
+			// the reminder is kept in the DB to be restored on the next start
+			rs.mu.Unlock()
+			return
+		}
+		rs.inFlight.Add(1)
+		defer rs.inFlight.Done()
+
+		notifier := rs.notifier
+		now := time.Now()
+		quietUntil, within := rs.quietUntil(now)
+		dndDeferred := within && rs.dndUntil != nil && rs.dndUntil.After(now)
+		rs.mu.Unlock()
+
+		silently := false
+		if within {
+			switch reminder.Urgency {
+			case common.UrgencyHigh:
+				silently = true
+			case common.UrgencyLow:
+				rs.dropReminder(notificationCtx, reminder, reminderTimer)
+				return
+			default:
+				if rs.deferReminder(notificationCtx, reminder, quietUntil, dndDeferred) {
+					return
+				}
+			}
+		}
+
+		var err error
+		if silently {
+			err = notifier.NotifySilently(reminder)
+		} else {
+			err = notifier.Notify(reminder)
+		}
 		if err != nil {
-			logger.Error("error happened on trying to send a notification for the reminder "+strconv.FormatInt(reminder.ID, 10), err)
+			logger.ErrorContext(notificationCtx, "error happened on trying to send a notification for the reminder "+strconv.FormatInt(reminder.ID, 10), err)
+		} else {
+			logger.InfoContext(notificationCtx, "notification sent for the reminder "+strconv.FormatInt(reminder.ID, 10))
 		}
-		err = rs.repo.Delete(reminder.ID)
+		rs.recordNotification(reminder, err)
+
+		err = rs.repo.Delete(notificationCtx, reminder.ID)
 		if err != nil {
-			logger.Error("error happened on trying to delete the reminder from the DB: "+strconv.FormatInt(reminder.ID, 10), err)
+			countRepoError("delete", err)
+			logger.ErrorContext(notificationCtx, "error happened on trying to delete the reminder from the DB: "+strconv.FormatInt(reminder.ID, 10), err)
 		}
-		delete(rs.rmdIdToTimer, reminder.ID)
+
+		rs.mu.Lock()
+		defer rs.mu.Unlock()
+
+		// the reminder might have been rescheduled with another timer in the meantime
+		if rs.rmdIdToTimer[reminder.ID] == reminderTimer {
+			delete(rs.rmdIdToTimer, reminder.ID)
+			delete(rs.rmdIdToReminder, reminder.ID)
+		}
+		rs.scheduleChanged()
 	})
 
 	rs.rmdIdToTimer[reminder.ID] = reminderTimer
+	rs.rmdIdToReminder[reminder.ID] = reminder
+	rs.scheduleChanged()
+}
+
+// deferReminder reschedules the reminder to the provided time, and reports whether it succeeded
+func (rs *ReminderService) deferReminder(ctx context.Context, reminder common.Reminder, deferTo time.Time, dndDeferred bool) bool {
+	reminder.RemindAt = deferTo
+	// the reminder is persisted with the new time, so that it's not deleted as expired by the cleanup job
+	err := rs.repo.Update(ctx, reminder)
+	if err != nil {
+		countRepoError("update", err)
+		logger.ErrorContext(ctx, "error happened on trying to defer the reminder "+strconv.FormatInt(reminder.ID, 10)+" - notifying right away", err)
+		return false
+	}
+
+	rs.setTimer(ctx, reminder)
+	if dndDeferred {
+		rs.mu.Lock()
+		rs.dndDeferred[reminder.ID] = deferTo
+		rs.mu.Unlock()
+	}
+	logger.InfoContext(ctx, "reminder "+strconv.FormatInt(reminder.ID, 10)+" deferred till the end of the quiet hours: "+deferTo.Format(time.RFC3339))
+	return true
 }
 
-func NewReminderService(repo repo.ReminderRepo) ReminderService {
+// dropReminder deletes the low urgency reminder that has come due within the quiet hours without notifying about it
+func (rs *ReminderService) dropReminder(ctx context.Context, reminder common.Reminder, reminderTimer *time.Timer) {
+	err := rs.repo.Delete(ctx, reminder.ID)
+	if err != nil {
+		countRepoError("delete", err)
+		logger.ErrorContext(ctx, "error happened on trying to delete the reminder from the DB: "+strconv.FormatInt(reminder.ID, 10), err)
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.rmdIdToTimer[reminder.ID] == reminderTimer {
+		delete(rs.rmdIdToTimer, reminder.ID)
+		delete(rs.rmdIdToReminder, reminder.ID)
+	}
+	rs.scheduleChanged()
+	logger.InfoContext(ctx, "low urgency reminder "+strconv.FormatInt(reminder.ID, 10)+" dropped within the quiet hours")
+}
+
+// releaseDndDeferred reschedules the reminders deferred during the previous do-not-disturb period to be notified right away:
+// the timer callback defers them again if it's still quiet
+func (rs *ReminderService) releaseDndDeferred(ctx context.Context, deferred map[int64]time.Time) {
+	for id, deferredTo := range deferred {
+		reminder, err := rs.repo.Get(ctx, id)
+		if err != nil {
+			countRepoError("get", err)
+			logger.ErrorContext(ctx, "error happened on trying to get the deferred reminder "+strconv.FormatInt(id, 10)+" - keeping it as is", err)
+			continue
+		}
+		// canceled or changed in the meantime
+		if reminder == nil || !reminder.RemindAt.Equal(deferredTo) {
+			continue
+		}
+
+		rs.stopTimer(id)
+		reminder.RemindAt = time.Now()
+		rs.setTimer(ctx, *reminder)
+	}
+}
+
+// quietUntil returns the end of the current quiet period, merging the quiet hours and the do-not-disturb period if they are adjacent.
+// Should be called under the lock.
+func (rs *ReminderService) quietUntil(now time.Time) (time.Time, bool) {
+	end := now
+	for {
+		extended := false
+		if rs.dndUntil != nil && end.Before(*rs.dndUntil) {
+			end, extended = *rs.dndUntil, true
+		}
+		if rs.quietHours != nil {
+			if quietHoursEnd, within := rs.quietHours.End(end); within {
+				end, extended = quietHoursEnd, true
+			}
+		}
+		if !extended {
+			return end, end.After(now)
+		}
+	}
+}
+
+func (rs *ReminderService) recordNotification(reminder common.Reminder, err error) {
+	result := common.NotificationResult{
+		ReminderID: reminder.ID,
+		Message:    reminder.Message,
+		SentAt:     time.Now(),
+		Success:    err == nil,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.lastNotification = &result
+	rs.notifications = append(rs.notifications, result)
+	if len(rs.notifications) > notificationHistoryLimit {
+		rs.notifications = rs.notifications[len(rs.notifications)-notificationHistoryLimit:]
+	}
+}
+
+// stopTimer reports whether the timer has been stopped before firing
+func (rs *ReminderService) stopTimer(reminderId int64) bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	var stopped = false
+	if timer, found := rs.rmdIdToTimer[reminderId]; found {
+		stopped = timer.Stop()
+	}
+	delete(rs.rmdIdToTimer, reminderId)
+	delete(rs.rmdIdToReminder, reminderId)
+	rs.scheduleChanged()
+	return stopped
+}
+
+// scheduleChanged should be called under the lock on every change of the timers:
+// it updates the metrics, and hands the next reminder over to the publisher goroutine if it has changed.
+// The state is resolved under the lock, so that the concurrent changes are handed over in order,
+// but it's published outside of it, as it might be slow, e.g. the file I/O.
+func (rs *ReminderService) scheduleChanged() {
+	metrics.ScheduledReminders.Set(float64(len(rs.rmdIdToTimer)))
+	if rs.nextReminderCh == nil {
+		return
+	}
+
+	state := common.NextReminderState{Scheduled: len(rs.rmdIdToReminder)}
+	for _, reminder := range rs.rmdIdToReminder {
+		if state.Reminder == nil || reminder.RemindAt.Before(state.Reminder.RemindAt) ||
+			(reminder.RemindAt.Equal(state.Reminder.RemindAt) && reminder.ID < state.Reminder.ID) {
+			next := reminder
+			state.Reminder = &next
+		}
+	}
+
+	if rs.lastNextReminder != nil && sameNextReminderState(*rs.lastNextReminder, state) {
+		return
+	}
+	rs.lastNextReminder = &state
+	// the latest state wins: the one that hasn't been published yet is outdated,
+	// and there is room for the new one then, as the publisher goroutine only takes the states out
+	select {
+	case <-rs.nextReminderCh:
+	default:
+	}
+	rs.nextReminderCh <- state
+}
+
+// runPublisher publishes the states handed over by scheduleChanged one by one, till the channel is closed on shutdown
+func runPublisher(publishNextReminder func(state common.NextReminderState), nextReminderCh <-chan common.NextReminderState, publisherDone chan<- struct{}) {
+	defer close(publisherDone)
+	for state := range nextReminderCh {
+		publishNextReminder(state)
+	}
+}
+
+func sameNextReminderState(a common.NextReminderState, b common.NextReminderState) bool {
+	if a.Scheduled != b.Scheduled || (a.Reminder == nil) != (b.Reminder == nil) {
+		return false
+	}
+	if a.Reminder == nil {
+		return true
+	}
+	return a.Reminder.ID == b.Reminder.ID && a.Reminder.Message == b.Reminder.Message &&
+		a.Reminder.RemindAt.Equal(b.Reminder.RemindAt) && a.Reminder.Urgency == b.Reminder.Urgency
+}
+
+func NewReminderService(repo repo.ReminderRepo, notificationBackends []string, quietHours *common.QuietHours, publishNextReminder func(state common.NextReminderState)) ReminderService {
+	var nextReminderCh chan common.NextReminderState
+	publisherDone := make(chan struct{})
+	if publishNextReminder != nil {
+		nextReminderCh = make(chan common.NextReminderState, 1)
+		go runPublisher(publishNextReminder, nextReminderCh, publisherDone)
+	} else {
+		close(publisherDone)
+	}
+
 	return ReminderService{
-		repo:         repo,
-		notifier:     notification.NewNotifier(),
-		rmdIdToTimer: make(map[int64]*time.Timer),
+		repo:            repo,
+		notifier:        notification.NewNotifier(notificationBackends),
+		rmdIdToTimer:    make(map[int64]*time.Timer),
+		rmdIdToReminder: make(map[int64]common.Reminder),
+		nextReminderCh:  nextReminderCh,
+		publisherDone:   publisherDone,
+		quietHours:      quietHours,
+		dndDeferred:     make(map[int64]time.Time),
+	}
+}
+
+// countRepoError counts the repo errors to be exposed as metrics, and returns the error as is
+func countRepoError(operation string, err error) error {
+	if err != nil {
+		metrics.RepoErrors.Inc(operation)
 	}
+	return err
 }
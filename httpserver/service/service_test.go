@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/httpserver/repo"
+	"n0rdy.foo/remindme/httpserver/repo/inmemory"
+	"testing"
+	"time"
+)
+
+// newTestService returns the service without the notification backends, so nothing is shown while every notification succeeds
+func newTestService(t *testing.T, quietHours *common.QuietHours) (*ReminderService, repo.ReminderRepo) {
+	t.Helper()
+	reminderRepo := inmemory.NewImMemoryReminderRepo()
+	srv := NewReminderService(reminderRepo, nil, quietHours, nil)
+	t.Cleanup(func() {
+		srv.Shutdown(context.Background())
+	})
+	return &srv, reminderRepo
+}
+
+func addReminder(t *testing.T, reminderRepo repo.ReminderRepo, reminder common.Reminder) common.Reminder {
+	t.Helper()
+	id, err := reminderRepo.Add(context.Background(), reminder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reminder.ID = id
+	return reminder
+}
+
+// waitFor polls the condition, as the timers fire in their own goroutines
+func waitFor(t *testing.T, description string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for " + description)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func notifiedIds(srv *ReminderService) map[int64]bool {
+	ids := make(map[int64]bool)
+	for _, notification := range srv.Notifications() {
+		ids[notification.ReminderID] = true
+	}
+	return ids
+}
+
+func TestRestoreActiveRemindersNotifiesAboutPastDueOnes(t *testing.T) {
+	ctx := context.Background()
+	srv, reminderRepo := newTestService(t, nil)
+	// came due while the app was stopped
+	pastDue := addReminder(t, reminderRepo, common.Reminder{Message: "past due", RemindAt: time.Now().Add(-time.Hour)})
+	upcoming := addReminder(t, reminderRepo, common.Reminder{Message: "upcoming", RemindAt: time.Now().Add(time.Hour)})
+
+	err := srv.RestoreActiveReminders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, "the past due reminder to be notified about", func() bool {
+		return notifiedIds(srv)[pastDue.ID]
+	})
+	waitFor(t, "the notified reminder to be deleted", func() bool {
+		reminder, _ := reminderRepo.Get(ctx, pastDue.ID)
+		return reminder == nil
+	})
+
+	if notifiedIds(srv)[upcoming.ID] {
+		t.Error("expected the upcoming reminder not to be notified about yet")
+	}
+	if reminder, _ := reminderRepo.Get(ctx, upcoming.ID); reminder == nil {
+		t.Error("expected the upcoming reminder to be kept")
+	}
+	status, err := srv.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ActiveTimers != 1 {
+		t.Errorf("expected the upcoming reminder only to be scheduled, got %d timers", status.ActiveTimers)
+	}
+}
+
+func TestShutdownKeepsRemindersToBeNotifiedAboutOnNextStart(t *testing.T) {
+	ctx := context.Background()
+	srv, reminderRepo := newTestService(t, nil)
+	reminder := addReminder(t, reminderRepo, common.Reminder{Message: "soon", RemindAt: time.Now().Add(100 * time.Millisecond)})
+
+	err := srv.RestoreActiveReminders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = srv.Shutdown(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the timer would have fired by now if it hadn't been stopped
+	time.Sleep(200 * time.Millisecond)
+
+	if notifiedIds(srv)[reminder.ID] {
+		t.Fatal("expected nothing to be notified about after the shutdown")
+	}
+	if kept, _ := reminderRepo.Get(ctx, reminder.ID); kept == nil {
+		t.Fatal("expected the reminder to be kept in the repo")
+	}
+
+	// the next start with the same repo
+	next := NewReminderService(reminderRepo, nil, nil, nil)
+	defer next.Shutdown(ctx)
+	err = next.RestoreActiveReminders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the reminder due while the app was stopped to be notified about", func() bool {
+		return notifiedIds(&next)[reminder.ID]
+	})
+}
+
+func TestTouchesSameReminders(t *testing.T) {
+	reminders := func(ids ...int64) []common.Reminder {
+		result := make([]common.Reminder, 0, len(ids))
+		for _, id := range ids {
+			result = append(result, common.Reminder{ID: id})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		entry    common.JournalEntry
+		newer    []common.JournalEntry
+		expected bool
+	}{
+		{
+			name:     "no newer entries",
+			entry:    common.JournalEntry{Created: reminders(1)},
+			expected: false,
+		},
+		{
+			name:     "newer entries touch other reminders",
+			entry:    common.JournalEntry{Created: reminders(1), Before: reminders(2)},
+			newer:    []common.JournalEntry{{Created: reminders(3)}, {Before: reminders(4, 5)}},
+			expected: false,
+		},
+		{
+			name:     "created reminder changed later",
+			entry:    common.JournalEntry{Created: reminders(1)},
+			newer:    []common.JournalEntry{{Before: reminders(1)}},
+			expected: true,
+		},
+		{
+			name:     "cancelled reminder restored by another entry later",
+			entry:    common.JournalEntry{Before: reminders(2, 3)},
+			newer:    []common.JournalEntry{{Created: reminders(4)}, {Created: reminders(3)}},
+			expected: true,
+		},
+		{
+			name:     "changed reminder cancelled later within the batch",
+			entry:    common.JournalEntry{Before: reminders(7)},
+			newer:    []common.JournalEntry{{Created: reminders(8), Before: reminders(6, 7)}},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := touchesSameReminders(test.entry, test.newer); actual != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUndoOlderEntryConflictsWithNewerOnes(t *testing.T) {
+	ctx := context.Background()
+	srv, reminderRepo := newTestService(t, nil)
+	later := time.Now().Add(time.Hour)
+
+	for _, message := range []string{"first", "second"} {
+		err := srv.Set(ctx, common.Reminder{Message: message, RemindAt: later})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	reminders, err := reminderRepo.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var first common.Reminder
+	for _, reminder := range reminders {
+		if reminder.Message == "first" {
+			first = reminder
+		}
+	}
+	first.Message = "first changed"
+	err = srv.Change(ctx, first.ID, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the latest first: the change, and the creations of the second and the first reminders
+	journal := srv.Journal()
+	if len(journal) != 3 {
+		t.Fatalf("expected 3 journal entries, got %d", len(journal))
+	}
+	changeEntry, secondEntry, firstEntry := journal[0], journal[1], journal[2]
+
+	_, err = srv.Undo(ctx, firstEntry.ID)
+	if !errors.Is(err, common.ErrJournalEntryConflict) {
+		t.Fatalf("expected the conflict, as the first reminder has been changed later, got %v", err)
+	}
+	if len(srv.Journal()) != 3 {
+		t.Fatal("expected the rejected entry to be kept in the journal")
+	}
+
+	// the entry touching the other reminder is undone regardless of the newer ones
+	undone, err := srv.Undo(ctx, secondEntry.ID)
+	if err != nil || undone == nil {
+		t.Fatalf("expected the second reminder creation to be undone, got %v", err)
+	}
+
+	// once the newer change is undone, the older entry can be undone too
+	for _, entryId := range []int64{changeEntry.ID, firstEntry.ID} {
+		undone, err = srv.Undo(ctx, entryId)
+		if err != nil || undone == nil {
+			t.Fatalf("expected the entry %d to be undone, got %v", entryId, err)
+		}
+	}
+	reminders, err = reminderRepo.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reminders) != 0 {
+		t.Errorf("expected no reminders left, got %v", reminders)
+	}
+}
+
+func TestSetTimerWithinQuietHoursByUrgency(t *testing.T) {
+	// quiet all day long for a few days, so that the test doesn't depend on the time it's run at
+	now := time.Now()
+	holidays := make(map[string]bool)
+	for day := 0; day < 3; day++ {
+		holidays[now.AddDate(0, 0, day).Format(common.DateFormat)] = true
+	}
+	year, month, day := now.Date()
+	quietUntil := time.Date(year, month, day+3, 0, 0, 0, 0, now.Location())
+
+	tests := []struct {
+		name    string
+		urgency string
+		// whether the reminder is expected to be notified about (silently for the high urgency), deferred till the end of the quiet hours, or dropped
+		notified bool
+		deferred bool
+	}{
+		{name: "low urgency dropped", urgency: common.UrgencyLow},
+		{name: "normal urgency deferred", urgency: common.UrgencyNormal, deferred: true},
+		{name: "empty urgency deferred as the normal one", urgency: "", deferred: true},
+		{name: "high urgency notified silently", urgency: common.UrgencyHigh, notified: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			srv, reminderRepo := newTestService(t, &common.QuietHours{Holidays: holidays})
+			reminder := addReminder(t, reminderRepo, common.Reminder{Message: test.name, RemindAt: time.Now(), Urgency: test.urgency})
+
+			srv.setTimer(ctx, reminder)
+
+			if test.deferred {
+				waitFor(t, "the reminder to be deferred", func() bool {
+					stored, _ := reminderRepo.Get(ctx, reminder.ID)
+					return stored != nil && stored.RemindAt.Equal(quietUntil)
+				})
+				status, err := srv.Status(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if status.ActiveTimers != 1 {
+					t.Errorf("expected the deferred reminder to be scheduled, got %d timers", status.ActiveTimers)
+				}
+				if status.QuietUntil == nil || !status.QuietUntil.Equal(quietUntil) {
+					t.Errorf("expected the quiet hours till %s, got %v", quietUntil, status.QuietUntil)
+				}
+			} else {
+				waitFor(t, "the reminder to be deleted", func() bool {
+					stored, _ := reminderRepo.Get(ctx, reminder.ID)
+					return stored == nil
+				})
+			}
+
+			// the notification is recorded even if the desktop one can't be shown where the tests are run
+			if notified := notifiedIds(srv)[reminder.ID]; notified != test.notified {
+				t.Errorf("expected notified to be %t, got %t", test.notified, notified)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package httpserver
+
+import (
+	"net"
+	"sync"
+	"syscall"
+)
+
+// socketListenMu serializes the umask changes, as the umask is process-wide
+var socketListenMu sync.Mutex
+
+// listenUnixSocket creates the socket accessible by the current user only: the umask is set before the socket file is created,
+// so that it's never reachable with the default permissions, even for a moment
+func listenUnixSocket(socketPath string) (net.Listener, error) {
+	socketListenMu.Lock()
+	defer socketListenMu.Unlock()
+
+	previousUmask := syscall.Umask(0177)
+	defer syscall.Umask(previousUmask)
+
+	return net.Listen("unix", socketPath)
+}
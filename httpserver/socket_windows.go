@@ -0,0 +1,23 @@
+//go:build windows
+
+package httpserver
+
+import (
+	"net"
+	"os"
+)
+
+// listenUnixSocket creates the socket, and restricts its permissions: Windows has no umask,
+// and the socket is used there only if requested explicitly, as the TCP transport is the default one
+func listenUnixSocket(socketPath string) (net.Listener, error) {
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	err = os.Chmod(socketPath, 0600)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
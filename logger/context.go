@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// RequestIdAttr is the name of the attribute the request ID is written to every record with
+const RequestIdAttr = "request_id"
+
+type requestIdKey struct{}
+
+// the request ID of the whole process: the client sends a single one per CLI invocation,
+// so it's attached to every record logged by the process, rather than passed around within the context
+var processRequestId string
+
+// NewRequestId generates a random ID to correlate the client and server logs of the same request
+func NewRequestId() string {
+	b := make([]byte, 8)
+	// never returns an error
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithRequestId returns a copy of the context, so that the records logged with it are tagged with the request ID
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+// RequestId returns the request ID attached to the context, or an empty string if there is none
+func RequestId(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey{}).(string)
+	return requestId
+}
+
+// SetRequestId tags all the records logged by the process without the request ID in the context with the provided one,
+// an empty string removes it
+func SetRequestId(requestId string) {
+	processRequestId = requestId
+}
+
+func DebugContext(ctx context.Context, message string) {
+	if isLoggerSetUp {
+		slogger.DebugContext(ctx, message)
+	}
+}
+
+func InfoContext(ctx context.Context, message string) {
+	if isLoggerSetUp {
+		slogger.InfoContext(ctx, message)
+	}
+}
+
+func WarnContext(ctx context.Context, message string, err ...error) {
+	if isLoggerSetUp {
+		slogger.WarnContext(ctx, message, errorAttrs(err)...)
+	}
+}
+
+func ErrorContext(ctx context.Context, message string, err ...error) {
+	if isLoggerSetUp {
+		slogger.ErrorContext(ctx, message, errorAttrs(err)...)
+	}
+}
+
+// requestIdHandler adds the request ID attribute to the records
+type requestIdHandler struct {
+	slog.Handler
+}
+
+func (h requestIdHandler) Handle(ctx context.Context, record slog.Record) error {
+	requestId := RequestId(ctx)
+	if requestId == "" {
+		requestId = processRequestId
+	}
+	if requestId != "" {
+		record.AddAttrs(slog.String(RequestIdAttr, requestId))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h requestIdHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestIdHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h requestIdHandler) WithGroup(name string) slog.Handler {
+	return requestIdHandler{h.Handler.WithGroup(name)}
+}
@@ -1,47 +1,118 @@
 package logger
 
 import (
-	"log"
-	"os"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
 )
 
 const (
-	infoLogLevelPrefix  = "[INFO] "
-	errorLogLevelPrefix = "[ERROR] "
+	TextFormat = "text"
+	JsonFormat = "json"
 )
 
-var isLoggerSetUp bool
-var fileWithLogs *os.File
+type Options struct {
+	Level  slog.Level
+	Format string
+	// the logs file is rotated once it exceeds MaxSize bytes or becomes older than MaxAge, whichever comes first;
+	// zero values disable the corresponding rotation
+	MaxSize int64
+	MaxAge  time.Duration
+	// the number of the rotated files to keep, zero to keep all of them
+	MaxBackups int
+	// whether to gzip the rotated files
+	Compress bool
+}
 
-func SetupLogger(logsDir string, logsFile string) error {
-	if logsDir != "" {
-		err := os.MkdirAll(logsDir, os.ModePerm)
-		if err != nil {
-			return err
-		}
+func DefaultOptions() Options {
+	return Options{
+		Level:      slog.LevelInfo,
+		Format:     TextFormat,
+		MaxSize:    10 * 1024 * 1024,
+		MaxAge:     7 * 24 * time.Hour,
+		MaxBackups: 5,
+		Compress:   true,
 	}
+}
+
+var isLoggerSetUp bool
+var fileWithLogs *rotatingFile
+var slogger *slog.Logger
+var level = new(slog.LevelVar)
 
-	f, err := os.OpenFile(logsDir+logsFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+// SetupLogger can be called again to switch the logs to another file: e.g. the server started by the CLI command,
+// which has set up the client logs already. The previous file is closed then.
+func SetupLogger(logsDir string, logsFile string, options Options) error {
+	f, err := openRotatingFile(logsDir, logsFile, options)
 	if err != nil {
 		return err
 	}
 
-	log.SetOutput(f)
+	level.Set(options.Level)
+	slogger = slog.New(newHandler(f, options.Format))
+	// the libraries that use the standard logger will log into the same file
+	slog.SetDefault(slogger)
+
 	isLoggerSetUp = true
+	previous := fileWithLogs
 	fileWithLogs = f
 
+	// closed once nothing logs into it anymore: its error doesn't affect the new file
+	if previous != nil {
+		previous.Close()
+	}
 	return nil
 }
 
+// SetLevel changes the level of the already set up logger
+func SetLevel(newLevel slog.Level) {
+	level.Set(newLevel)
+}
+
+func ParseLevel(levelAsString string) (slog.Level, error) {
+	var parsedLevel slog.Level
+	err := parsedLevel.UnmarshalText([]byte(levelAsString))
+	if err != nil {
+		return slog.LevelInfo, errors.New("unknown log level [" + levelAsString + "]: expected one of debug, info, warn or error")
+	}
+	return parsedLevel, nil
+}
+
+func ParseFormat(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case TextFormat:
+		return TextFormat, nil
+	case JsonFormat:
+		return JsonFormat, nil
+	default:
+		return "", errors.New("unknown log format [" + format + "]: expected either text or json")
+	}
+}
+
+func Debug(message string) {
+	if isLoggerSetUp {
+		slogger.Debug(message)
+	}
+}
+
 func Info(message string) {
 	if isLoggerSetUp {
-		log.Println(infoLogLevelPrefix + message)
+		slogger.Info(message)
+	}
+}
+
+func Warn(message string, err ...error) {
+	if isLoggerSetUp {
+		slogger.Warn(message, errorAttrs(err)...)
 	}
 }
 
 func Error(message string, err ...error) {
 	if isLoggerSetUp {
-		log.Println(errorLogLevelPrefix+message, err)
+		slogger.Error(message, errorAttrs(err)...)
 	}
 }
 
@@ -51,3 +122,19 @@ func Close() error {
 	}
 	return nil
 }
+
+func newHandler(w io.Writer, format string) slog.Handler {
+	handlerOptions := &slog.HandlerOptions{Level: level}
+	if format == JsonFormat {
+		return requestIdHandler{slog.NewJSONHandler(w, handlerOptions)}
+	}
+	return requestIdHandler{slog.NewTextHandler(w, handlerOptions)}
+}
+
+func errorAttrs(errs []error) []any {
+	err := errors.Join(errs...)
+	if err == nil {
+		return nil
+	}
+	return []any{slog.String("error", fmt.Sprint(err))}
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the format of the records written by the previous versions of the app: "2023/08/13 10:15:00 [INFO] message"
+const legacyRecordTimeFormat = "2006/01/02 15:04:05"
+
+// Record is a parsed line of the logs file
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]string
+	Raw     string
+}
+
+// ParseRecord parses the line written in either text, JSON or legacy format, and reports whether it succeeded
+func ParseRecord(line string) (Record, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, "{") {
+		return parseJsonRecord(line)
+	}
+	if strings.HasPrefix(line, "time=") {
+		return parseTextRecord(line)
+	}
+	return parseLegacyRecord(line)
+}
+
+func parseJsonRecord(line string) (Record, bool) {
+	fields := make(map[string]any)
+	err := json.Unmarshal([]byte(line), &fields)
+	if err != nil {
+		return Record{Raw: line}, false
+	}
+
+	attrs := make(map[string]string, len(fields))
+	for key, value := range fields {
+		if s, isString := value.(string); isString {
+			attrs[key] = s
+		} else {
+			attrs[key] = fmt.Sprint(value)
+		}
+	}
+	return newRecord(line, attrs)
+}
+
+func parseTextRecord(line string) (Record, bool) {
+	attrs := make(map[string]string)
+
+	rest := line
+	for rest != "" {
+		eqIndex := strings.IndexByte(rest, '=')
+		if eqIndex <= 0 {
+			return Record{Raw: line}, false
+		}
+		key := rest[:eqIndex]
+		rest = rest[eqIndex+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return Record{Raw: line}, false
+			}
+			value, _ = strconv.Unquote(quoted)
+			rest = rest[len(quoted):]
+		} else {
+			spaceIndex := strings.IndexByte(rest, ' ')
+			if spaceIndex < 0 {
+				spaceIndex = len(rest)
+			}
+			value = rest[:spaceIndex]
+			rest = rest[spaceIndex:]
+		}
+
+		attrs[key] = value
+		rest = strings.TrimLeft(rest, " ")
+	}
+	return newRecord(line, attrs)
+}
+
+func parseLegacyRecord(line string) (Record, bool) {
+	if len(line) < len(legacyRecordTimeFormat) {
+		return Record{Raw: line}, false
+	}
+
+	t, err := time.ParseInLocation(legacyRecordTimeFormat, line[:len(legacyRecordTimeFormat)], time.Local)
+	if err != nil {
+		return Record{Raw: line}, false
+	}
+
+	rest := strings.TrimSpace(line[len(legacyRecordTimeFormat):])
+	record := Record{Time: t, Level: slog.LevelInfo, Message: rest, Attrs: map[string]string{}, Raw: line}
+	if strings.HasPrefix(rest, "[") {
+		if closingIndex := strings.IndexByte(rest, ']'); closingIndex > 0 {
+			if level, err := ParseLevel(rest[1:closingIndex]); err == nil {
+				record.Level = level
+			}
+			record.Message = strings.TrimSpace(rest[closingIndex+1:])
+		}
+	}
+	return record, true
+}
+
+func newRecord(line string, attrs map[string]string) (Record, bool) {
+	record := Record{Raw: line, Message: attrs[slog.MessageKey], Attrs: attrs}
+
+	t, err := time.Parse(time.RFC3339Nano, attrs[slog.TimeKey])
+	if err != nil {
+		return record, false
+	}
+	record.Time = t
+
+	level, err := ParseLevel(attrs[slog.LevelKey])
+	if err != nil {
+		return record, false
+	}
+	record.Level = level
+
+	delete(attrs, slog.TimeKey)
+	delete(attrs, slog.LevelKey)
+	delete(attrs, slog.MessageKey)
+	return record, true
+}
@@ -0,0 +1,265 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	rotatedFileTimeFormat = "20060102T150405.000"
+	compressedFileSuffix  = ".gz"
+)
+
+// rotatingFile is a logs file that is rotated once it gets too big or too old:
+// the current file is renamed to "<name>-<rotation time><ext>" (e.g. "remindme_server_logs-20231019T101500.000.log"),
+// optionally gzipped, and the oldest rotated files are deleted to keep the requested number of them
+type rotatingFile struct {
+	mu       sync.Mutex
+	dir      string
+	name     string
+	options  Options
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func openRotatingFile(dir string, name string, options Options) (*rotatingFile, error) {
+	if dir != "" {
+		err := os.MkdirAll(dir, os.ModePerm)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rf := &rotatingFile{dir: dir, name: name, options: options}
+	err := rf.open()
+	if err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.shouldRotate(int64(len(p))) {
+		// the logs should be written anyway, so the rotation failure is not propagated
+		_ = rf.rotate()
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *rotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	return rf.file.Close()
+}
+
+func (rf *rotatingFile) open() error {
+	path := filepath.Join(rf.dir, rf.name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	rf.file = f
+	rf.size = info.Size()
+	rf.openedAt = time.Now()
+	if rf.size > 0 {
+		// the file has been created by one of the previous runs, so its age is defined by the first record
+		if firstRecordTime, found := readFirstRecordTime(path); found {
+			rf.openedAt = firstRecordTime
+		}
+	}
+	return nil
+}
+
+func (rf *rotatingFile) shouldRotate(bytesToWrite int64) bool {
+	if rf.size == 0 {
+		return false
+	}
+	if rf.options.MaxSize > 0 && rf.size+bytesToWrite > rf.options.MaxSize {
+		return true
+	}
+	return rf.options.MaxAge > 0 && time.Since(rf.openedAt) > rf.options.MaxAge
+}
+
+func (rf *rotatingFile) rotate() error {
+	err := rf.file.Close()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(rf.dir, rf.name)
+	ext := filepath.Ext(rf.name)
+	rotatedPath := filepath.Join(rf.dir, strings.TrimSuffix(rf.name, ext)+"-"+time.Now().Format(rotatedFileTimeFormat)+ext)
+
+	renameErr := os.Rename(path, rotatedPath)
+	// the file should be reopened even if the renaming failed, otherwise no logs will be written at all
+	err = rf.open()
+	if err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return renameErr
+	}
+
+	if rf.options.Compress {
+		err = compress(rotatedPath)
+		if err != nil {
+			return err
+		}
+	}
+	return rf.removeOldBackups()
+}
+
+func (rf *rotatingFile) removeOldBackups() error {
+	if rf.options.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := listBackups(rf.dir, rf.name)
+	if err != nil {
+		return err
+	}
+
+	for len(backups) > rf.options.MaxBackups {
+		err = os.Remove(backups[0])
+		if err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// ListSegments lists the logs files with the rotated ones (including the compressed ones) from the oldest to the newest,
+// the current logs file is the last one if it exists
+func ListSegments(dir string, name string) ([]string, error) {
+	segments, err := listBackups(dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	current := filepath.Join(dir, name)
+	if _, err := os.Stat(current); err == nil {
+		segments = append(segments, current)
+	}
+	return segments, nil
+}
+
+// OpenSegment opens the logs file for reading, decompressing it if needed
+func OpenSegment(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, compressedFileSuffix) {
+		return f, nil
+	}
+
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &gzipSegment{Reader: gzipReader, file: f}, nil
+}
+
+type gzipSegment struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (gs *gzipSegment) Close() error {
+	gs.Reader.Close()
+	return gs.file.Close()
+}
+
+// listBackups lists the rotated files sorted from the oldest to the newest:
+// the rotation time in their names is sortable lexicographically
+func listBackups(dir string, name string) ([]string, error) {
+	ext := filepath.Ext(name)
+	pattern := filepath.Join(dir, strings.TrimSuffix(name, ext)+"-*"+ext)
+
+	rotated, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := filepath.Glob(pattern + compressedFileSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := append(rotated, compressed...)
+	sort.Slice(backups, func(i, j int) bool {
+		return strings.TrimSuffix(backups[i], compressedFileSuffix) < strings.TrimSuffix(backups[j], compressedFileSuffix)
+	})
+	return backups, nil
+}
+
+func compress(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressedFileSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gzipWriter := gzip.NewWriter(dst)
+	_, err = io.Copy(gzipWriter, src)
+	if err == nil {
+		err = gzipWriter.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + compressedFileSuffix)
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
+
+func readFirstRecordTime(path string) (time.Time, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && line == "" {
+		return time.Time{}, false
+	}
+
+	record, parsed := ParseRecord(line)
+	if !parsed || record.Time.IsZero() {
+		return time.Time{}, false
+	}
+	return record.Time, true
+}
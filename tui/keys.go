@@ -0,0 +1,93 @@
+package tui
+
+import "unicode/utf8"
+
+type keyKind int
+
+const (
+	keyRune keyKind = iota
+	keyEnter
+	keyEscape
+	keyBackspace
+	keyDelete
+	keyInterrupt
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyUnknown
+)
+
+type key struct {
+	kind keyKind
+	// set for the printable keys only
+	r rune
+}
+
+// escapeSequences are the sequences the terminals send for the special keys in the raw mode, both the xterm and the VT ones
+var escapeSequences = map[string]keyKind{
+	"[A":  keyUp,
+	"[B":  keyDown,
+	"OA":  keyUp,
+	"OB":  keyDown,
+	"[5~": keyPageUp,
+	"[6~": keyPageDown,
+	"[H":  keyHome,
+	"[F":  keyEnd,
+	"OH":  keyHome,
+	"OF":  keyEnd,
+	"[1~": keyHome,
+	"[4~": keyEnd,
+	"[3~": keyDelete,
+}
+
+// decodeKeys decodes the bytes read from the terminal in the raw mode.
+// The escape sequence is expected to be read at once, so the standalone ESC byte is treated as the Esc key.
+func decodeKeys(buf []byte) []key {
+	keys := make([]key, 0, len(buf))
+	for len(buf) > 0 {
+		switch b := buf[0]; {
+		case b == 0x1b:
+			k, size := decodeEscapeSequence(buf)
+			keys = append(keys, k)
+			buf = buf[size:]
+			continue
+		case b == '\r' || b == '\n':
+			keys = append(keys, key{kind: keyEnter})
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, key{kind: keyBackspace})
+		case b == 0x03 || b == 0x04:
+			// Ctrl-C and Ctrl-D, as the raw mode doesn't turn them into signals
+			keys = append(keys, key{kind: keyInterrupt})
+		case b < 0x20:
+			keys = append(keys, key{kind: keyUnknown})
+		default:
+			r, size := utf8.DecodeRune(buf)
+			keys = append(keys, key{kind: keyRune, r: r})
+			buf = buf[size:]
+			continue
+		}
+		buf = buf[1:]
+	}
+	return keys
+}
+
+func decodeEscapeSequence(buf []byte) (key, int) {
+	if len(buf) == 1 || (buf[1] != '[' && buf[1] != 'O') {
+		return key{kind: keyEscape}, 1
+	}
+
+	// the sequence ends with the first letter or "~" after the prefix
+	for i := 2; i < len(buf); i++ {
+		b := buf[i]
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || b == '~' {
+			if kind, ok := escapeSequences[string(buf[1:i+1])]; ok {
+				return key{kind: kind}, i + 1
+			}
+			return key{kind: keyUnknown}, i + 1
+		}
+	}
+	return key{kind: keyUnknown}, len(buf)
+}
@@ -0,0 +1,271 @@
+package tui
+
+import (
+	"fmt"
+	"golang.org/x/term"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	enterAltScreen = "\033[?1049h"
+	exitAltScreen  = "\033[?1049l"
+	hideCursor     = "\033[?25l"
+	showCursor     = "\033[?25h"
+	cursorHome     = "\033[H"
+	clearToLineEnd = "\033[K"
+	clearToEnd     = "\033[J"
+
+	styleNone    = ""
+	styleReset   = "\033[0m"
+	styleBold    = "\033[1m"
+	styleDim     = "\033[2m"
+	styleReverse = "\033[7m"
+	styleError   = "\033[1;31m"
+
+	// used if the terminal size can't be detected
+	defaultWidth  = 80
+	defaultHeight = 24
+
+	// the lines around the reminders list: the header, the filter, the column titles, the last notification, the notice and the help
+	chromeHeight    = 6
+	columnGap       = "  "
+	minMessageWidth = 10
+
+	listHelp    = "↑/↓ move  n new  e edit  p postpone  c cancel  s snooze  / filter  r refresh  q quit"
+	promptHelp  = "Enter submit  Esc dismiss"
+	confirmHelp = "y confirm  any other key dismiss"
+	errorHelp   = "r retry  q quit"
+)
+
+// render redraws the whole screen in place rather than clearing it first, so that it doesn't flicker
+func (u *ui) render(fd int) {
+	width, height, err := term.GetSize(fd)
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = defaultWidth, defaultHeight
+	}
+	u.height = height
+
+	var lines []string
+	switch {
+	case !u.loaded:
+		lines = u.loadingLines(width)
+	case u.err != nil:
+		lines = u.errorLines(width)
+	default:
+		lines = u.listLines(width)
+	}
+
+	// the help is always at the bottom
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines[:height-1], u.helpLine(width))
+
+	var sb strings.Builder
+	sb.WriteString(cursorHome)
+	for i, line := range lines {
+		sb.WriteString(line + styleReset + clearToLineEnd)
+		if i < len(lines)-1 {
+			sb.WriteString("\r\n")
+		}
+	}
+	sb.WriteString(clearToEnd)
+	os.Stdout.WriteString(sb.String())
+}
+
+func (u *ui) rowsHeight() int {
+	return u.height - chromeHeight
+}
+
+func (u *ui) loadingLines(width int) []string {
+	return []string{styled(styleReverse, u.header(), width), "", styled(styleNone, "Loading...", width)}
+}
+
+func (u *ui) errorLines(width int) []string {
+	return []string{
+		styled(styleReverse, u.header(), width),
+		"",
+		styled(styleError, "The app can't be reached", width),
+		styled(styleNone, u.err.Error(), width),
+		"",
+		styled(styleDim, "Retrying every "+utils.HumanizeDuration(refreshInterval)+"...", width),
+	}
+}
+
+func (u *ui) listLines(width int) []string {
+	visible := u.visible()
+	lines := []string{styled(styleReverse, u.header(), width)}
+
+	if u.filter != "" {
+		lines = append(lines, styled(styleBold, fmt.Sprintf("Filter: %s (%d of %d)", u.filter, len(visible), len(u.reminders)), width))
+	} else {
+		lines = append(lines, "")
+	}
+
+	rowsHeight := max(u.rowsHeight(), 1)
+	if len(visible) == 0 {
+		if u.filter != "" {
+			lines = append(lines, "", styled(styleDim, "No reminders match the filter: press Esc to clear it", width))
+		} else {
+			lines = append(lines, "", styled(styleDim, "No upcoming reminders: press n to set one", width))
+		}
+		for len(lines) < rowsHeight+3 {
+			lines = append(lines, "")
+		}
+	} else {
+		lines = append(lines, u.rowLines(visible, width, rowsHeight)...)
+	}
+
+	lines = append(lines, styled(styleDim, u.lastNotificationLine(), width), u.bottomLine(width))
+	return lines
+}
+
+// rowLines returns the column titles and the visible rows, scrolled to the selected one
+func (u *ui) rowLines(visible []common.Reminder, width int, rowsHeight int) []string {
+	now := time.Now()
+	selectedIndex := u.selectedIndex(visible)
+	if selectedIndex < u.offset {
+		u.offset = selectedIndex
+	} else if selectedIndex >= u.offset+rowsHeight {
+		u.offset = selectedIndex - rowsHeight + 1
+	}
+	u.offset = min(u.offset, max(len(visible)-rowsHeight, 0))
+
+	titles := [4]string{"ID", "Message", "Remind at", "In"}
+	cells := make([][4]string, 0, len(visible))
+	idWidth, timeWidth, inWidth := len(titles[0]), len(titles[2]), len(titles[3])
+	for _, reminder := range visible {
+		in := "due"
+		if d := reminder.RemindAt.Sub(now); d > 0 {
+			in = utils.FormatCountdown(d)
+		}
+		row := [4]string{strconv.FormatInt(reminder.ID, 10), sanitize(reminder.Message), reminder.RemindAt.Format(u.options.TimeFormat), in}
+		idWidth = max(idWidth, len(row[0]))
+		timeWidth = max(timeWidth, len(row[2]))
+		inWidth = max(inWidth, len(row[3]))
+		cells = append(cells, row)
+	}
+	// the selection marker and the gaps between the columns are included
+	messageWidth := max(width-2-idWidth-timeWidth-inWidth-3*len(columnGap), minMessageWidth)
+
+	format := func(marker string, row [4]string) string {
+		return fmt.Sprintf("%s%-*s%s%-*s%s%-*s%s%s",
+			marker, idWidth, row[0], columnGap, messageWidth, truncate(row[1], messageWidth), columnGap, timeWidth, row[2], columnGap, row[3])
+	}
+
+	lines := []string{styled(styleBold, format("  ", titles), width)}
+	for i := u.offset; i < len(cells) && i < u.offset+rowsHeight; i++ {
+		if i == selectedIndex {
+			lines = append(lines, styled(styleReverse, format("> ", cells[i]), width))
+		} else {
+			lines = append(lines, styled(styleNone, format("  ", cells[i]), width))
+		}
+	}
+	for len(lines) < rowsHeight+1 {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+func (u *ui) header() string {
+	parts := []string{"remindme"}
+	if u.options.Profile != "" {
+		parts[0] += " [" + u.options.Profile + "]"
+	}
+
+	switch {
+	case u.err != nil:
+		parts = append(parts, common.HealthStatusDown)
+	case u.status != nil:
+		parts = append(parts, u.status.Status, strconv.Itoa(len(u.reminders))+" upcoming")
+		if dndUntil := u.status.Scheduler.DndUntil; dndUntil != nil {
+			parts = append(parts, "do not disturb till "+dndUntil.Format(u.options.TimeFormat))
+		} else if quietUntil := u.status.Scheduler.QuietUntil; quietUntil != nil {
+			parts = append(parts, "quiet hours till "+quietUntil.Format(u.options.TimeFormat))
+		}
+	}
+	return " " + strings.Join(parts, " | ")
+}
+
+func (u *ui) lastNotificationLine() string {
+	if u.status == nil || u.status.Scheduler.LastNotification == nil {
+		return "Nothing has been notified about since the app start"
+	}
+	notification := u.status.Scheduler.LastNotification
+	return fmt.Sprintf("Last notification: %q at %s", sanitize(notification.Message), notification.SentAt.Format(u.options.TimeFormat))
+}
+
+// bottomLine is either the prompt, or the notice about the last action, which disappears after a while
+func (u *ui) bottomLine(width int) string {
+	if p := u.prompt; p != nil {
+		if p.confirm {
+			return styled(styleBold, p.label, width)
+		}
+		// the input is scrolled, so that the cursor is always visible
+		line := []rune(p.label + ": " + string(p.input))
+		if len(line) >= width {
+			line = line[len(line)-width+1:]
+		}
+		return styleBold + string(line) + styleReverse + " "
+	}
+
+	if u.notice != "" && time.Since(u.noticeAt) < noticeTimeout {
+		if u.noticeErr {
+			return styled(styleError, u.notice, width)
+		}
+		return styled(styleNone, u.notice, width)
+	}
+	return ""
+}
+
+// helpLine lists the available keys, unless the prompt input has been rejected: the prompt takes the notice line then
+func (u *ui) helpLine(width int) string {
+	switch {
+	case u.prompt != nil && u.noticeErr && time.Since(u.noticeAt) < noticeTimeout:
+		return styled(styleError, u.notice, width)
+	case u.prompt != nil && u.prompt.confirm:
+		return styled(styleDim, confirmHelp, width)
+	case u.prompt != nil:
+		return styled(styleDim, promptHelp, width)
+	case !u.loaded || u.err != nil:
+		return styled(styleDim, errorHelp, width)
+	default:
+		return styled(styleDim, listHelp, width)
+	}
+}
+
+// styled truncates the text to fit the line, and pads it, so that the background of the reversed style fills the whole line
+func styled(style string, text string, width int) string {
+	text = truncate(text, width)
+	if style == styleReverse {
+		text += strings.Repeat(" ", width-len([]rune(text)))
+	}
+	return style + text
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// sanitize replaces the line breaks and the other control characters, so that the message fits the single line
+func sanitize(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, text)
+}
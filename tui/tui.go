@@ -0,0 +1,603 @@
+package tui
+
+import (
+	"fmt"
+	"golang.org/x/term"
+	"io"
+	"n0rdy.foo/remindme/common"
+	"n0rdy.foo/remindme/config"
+	"n0rdy.foo/remindme/httpclient"
+	"n0rdy.foo/remindme/logger"
+	"n0rdy.foo/remindme/utils"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// there is no event stream exposed by the server, so it's polled instead
+	refreshInterval = 2 * time.Second
+	// the countdowns are updated every second
+	renderInterval = time.Second
+	noticeTimeout  = 5 * time.Second
+
+	// the seconds are rarely needed, so they are omitted on input
+	clockInputFormat    = "15:04"
+	dateTimeInputFormat = "2006-01-02 15:04"
+)
+
+// Options are resolved from the flags and the user configs by the command
+type Options struct {
+	// empty for the default profile
+	Profile       string
+	TimeFormat    string
+	DefaultSnooze time.Duration
+}
+
+// snapshot is the state of the server fetched by the poller
+type snapshot struct {
+	client    httpclient.RemindmeHttpClient
+	reminders []common.Reminder
+	status    *common.Status
+	err       error
+}
+
+// prompt is the single line input at the bottom of the screen
+type prompt struct {
+	label string
+	input []rune
+	// the confirmation is answered with a single key: "y" submits it, any other key dismisses it
+	confirm bool
+	submit  func(input string) error
+	// called on every input change: e.g. to filter the list while typing
+	change func(input string)
+	// called if the prompt is dismissed with Esc
+	dismiss func()
+}
+
+type ui struct {
+	options Options
+	// the client the last snapshot has been fetched with
+	client    httpclient.RemindmeHttpClient
+	loaded    bool
+	err       error
+	reminders []common.Reminder
+	status    *common.Status
+	filter    string
+	// the selection follows the reminder rather than the row, as the rows move on refresh
+	selectedId int64
+	offset     int
+	height     int
+	prompt     *prompt
+	notice     string
+	noticeErr  bool
+	noticeAt   time.Time
+	refreshCh  chan struct{}
+	quit       bool
+}
+
+// Run shows the full-screen UI until the user quits it: the terminal is restored on return
+func Run(options Options) error {
+	inFd, outFd := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		logger.Error("ui: stdin or stdout is not a terminal")
+		return common.ErrTuiNotTerminal
+	}
+
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		logger.Error("ui: error while switching the terminal to the raw mode", err)
+		return common.ErrTuiCannotSetUpTerminal
+	}
+	defer term.Restore(inFd, state)
+
+	os.Stdout.WriteString(enterAltScreen + hideCursor)
+	defer os.Stdout.WriteString(showCursor + exitAltScreen)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	u := &ui{options: options, refreshCh: make(chan struct{}, 1)}
+	keysCh := make(chan []key)
+	go readKeys(os.Stdin, keysCh, done)
+	snapshotsCh := make(chan snapshot)
+	go poll(u.refreshCh, snapshotsCh, done)
+
+	// the raw mode doesn't turn Ctrl-C into the signal, but the process might still be terminated
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+
+	ticker := time.NewTicker(renderInterval)
+	defer ticker.Stop()
+
+	for !u.quit {
+		u.render(outFd)
+
+		select {
+		case keys := <-keysCh:
+			for _, k := range keys {
+				u.handleKey(k)
+			}
+		case s := <-snapshotsCh:
+			u.apply(s)
+		case <-ticker.C:
+		case <-signalCh:
+			logger.Info("ui: terminated by signal")
+			return nil
+		}
+	}
+	return nil
+}
+
+func readKeys(r io.Reader, keysCh chan<- []key, done <-chan struct{}) {
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		keys := decodeKeys(buf[:n])
+		if err != nil {
+			logger.Error("ui: error while reading the input", err)
+			keys = append(keys, key{kind: keyInterrupt})
+		}
+
+		select {
+		case keysCh <- keys:
+		case <-done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// poll fetches the snapshot every refreshInterval, or right away if requested via refreshCh: e.g. after the reminder is changed
+func poll(refreshCh <-chan struct{}, snapshotsCh chan<- snapshot, done <-chan struct{}) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	var client *httpclient.RemindmeHttpClient
+	for {
+		if client == nil {
+			// resolved again after every failure, as the app might have been restarted with the different address
+			address, err := config.ResolveRunningServerAddress()
+			if err == nil {
+				newClient := httpclient.NewHttpClient(address)
+				client = &newClient
+			} else {
+				logger.Error("ui: error while resolving running server address", err)
+			}
+		}
+
+		s := snapshot{err: common.ErrCmdCannotResolveServerAddress}
+		if client != nil {
+			s = fetch(*client)
+			if s.err != nil {
+				client = nil
+			}
+		}
+
+		select {
+		case snapshotsCh <- s:
+		case <-done:
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-refreshCh:
+		case <-done:
+			return
+		}
+	}
+}
+
+func fetch(client httpclient.RemindmeHttpClient) snapshot {
+	reminders, err := client.GetAllReminders()
+	if err != nil {
+		return snapshot{err: err}
+	}
+	status, err := client.GetStatus()
+	if err != nil {
+		return snapshot{err: err}
+	}
+
+	sort.Slice(reminders, func(i, j int) bool {
+		if reminders[i].RemindAt.Equal(reminders[j].RemindAt) {
+			return reminders[i].ID < reminders[j].ID
+		}
+		return reminders[i].RemindAt.Before(reminders[j].RemindAt)
+	})
+	return snapshot{client: client, reminders: reminders, status: status}
+}
+
+func (u *ui) apply(s snapshot) {
+	u.loaded = true
+	u.err = s.err
+	if s.err != nil {
+		// the input can't be submitted anyway, and the error screen hides it
+		u.prompt = nil
+		return
+	}
+	u.client = s.client
+	u.reminders = s.reminders
+	u.status = s.status
+}
+
+func (u *ui) refresh() {
+	select {
+	case u.refreshCh <- struct{}{}:
+	default:
+		// the refresh has been requested already
+	}
+}
+
+func (u *ui) handleKey(k key) {
+	if k.kind == keyInterrupt {
+		u.quit = true
+		return
+	}
+	if u.prompt != nil {
+		u.handlePromptKey(k)
+		return
+	}
+
+	if !u.loaded || u.err != nil {
+		if k.kind == keyRune && k.r == 'q' {
+			u.quit = true
+		} else if k.kind == keyRune && k.r == 'r' {
+			u.refresh()
+		}
+		return
+	}
+
+	switch k.kind {
+	case keyUp:
+		u.move(-1)
+	case keyDown:
+		u.move(1)
+	case keyPageUp:
+		u.move(-u.pageSize())
+	case keyPageDown:
+		u.move(u.pageSize())
+	case keyHome:
+		u.move(-len(u.reminders))
+	case keyEnd:
+		u.move(len(u.reminders))
+	case keyEnter:
+		u.edit()
+	case keyDelete:
+		u.cancel()
+	case keyEscape:
+		u.setFilter("")
+	case keyRune:
+		switch k.r {
+		case 'q':
+			u.quit = true
+		case 'k':
+			u.move(-1)
+		case 'j':
+			u.move(1)
+		case 'g':
+			u.move(-len(u.reminders))
+		case 'G':
+			u.move(len(u.reminders))
+		case 'n':
+			u.create()
+		case 'e':
+			u.edit()
+		case 'p':
+			u.postpone()
+		case 'c':
+			u.cancel()
+		case 's':
+			u.snooze()
+		case '/':
+			u.search()
+		case 'r':
+			u.refresh()
+		}
+	}
+}
+
+func (u *ui) handlePromptKey(k key) {
+	p := u.prompt
+	if p.confirm {
+		u.prompt = nil
+		if k.kind == keyRune && (k.r == 'y' || k.r == 'Y') {
+			u.submit(p)
+		}
+		return
+	}
+
+	switch k.kind {
+	case keyEnter:
+		u.prompt = nil
+		u.submit(p)
+		return
+	case keyEscape:
+		u.prompt = nil
+		if p.dismiss != nil {
+			p.dismiss()
+		}
+		return
+	case keyBackspace:
+		if len(p.input) == 0 {
+			return
+		}
+		p.input = p.input[:len(p.input)-1]
+	case keyRune:
+		p.input = append(p.input, k.r)
+	default:
+		return
+	}
+
+	if p.change != nil {
+		p.change(string(p.input))
+	}
+}
+
+// submit reopens the prompt if the input is rejected, so that it can be fixed rather than typed again
+func (u *ui) submit(p *prompt) {
+	err := p.submit(strings.TrimSpace(string(p.input)))
+	if err != nil {
+		u.setNotice(err.Error(), true)
+		if !p.confirm {
+			u.prompt = p
+		}
+	}
+}
+
+func (u *ui) create() {
+	u.prompt = &prompt{
+		label: "New reminder message",
+		submit: func(message string) error {
+			if message == "" {
+				return common.ErrTuiMessageNotProvided
+			}
+
+			u.prompt = &prompt{
+				label: "Remind in (e.g. 10m) or at (e.g. 16:30)",
+				submit: func(value string) error {
+					remindAt, err := parseRemindAt(value, time.Now())
+					if err != nil {
+						return err
+					}
+					err = u.client.CreateReminder(common.Reminder{Message: message, RemindAt: remindAt})
+					if err != nil {
+						return err
+					}
+					logger.Info("ui: reminder created")
+					u.setNotice("Reminder set for "+remindAt.Format(u.options.TimeFormat), false)
+					u.refresh()
+					return nil
+				},
+			}
+			return nil
+		},
+	}
+}
+
+func (u *ui) edit() {
+	selected := u.selected()
+	if selected == nil {
+		return
+	}
+	reminder := *selected
+
+	u.prompt = &prompt{
+		label: "Reminder #" + strconv.FormatInt(reminder.ID, 10) + " message",
+		input: []rune(reminder.Message),
+		submit: func(message string) error {
+			if message == "" {
+				return common.ErrTuiMessageNotProvided
+			}
+
+			current := reminder.RemindAt.Format(dateTimeInputFormat)
+			u.prompt = &prompt{
+				label: "Remind in (e.g. 10m) or at (e.g. 16:30)",
+				input: []rune(current),
+				submit: func(value string) error {
+					remindAt := reminder.RemindAt
+					// the seconds are omitted on input, so the unchanged time is kept as it is
+					if value != current {
+						var err error
+						remindAt, err = parseRemindAt(value, time.Now())
+						if err != nil {
+							return err
+						}
+					}
+					if message == reminder.Message && remindAt.Equal(reminder.RemindAt) {
+						return nil
+					}
+
+					modified := reminder
+					modified.Message = message
+					modified.RemindAt = remindAt
+					err := u.client.ChangeReminder(int(reminder.ID), modified)
+					if err != nil {
+						return err
+					}
+					logger.Info("ui: reminder changed: " + strconv.FormatInt(reminder.ID, 10))
+					u.setNotice("Reminder #"+strconv.FormatInt(reminder.ID, 10)+" changed", false)
+					u.refresh()
+					return nil
+				},
+			}
+			return nil
+		},
+	}
+}
+
+func (u *ui) postpone() {
+	selected := u.selected()
+	if selected == nil {
+		return
+	}
+	reminder := *selected
+
+	u.prompt = &prompt{
+		label: fmt.Sprintf("Postpone #%d by (%s if empty)", reminder.ID, utils.HumanizeDuration(u.options.DefaultSnooze)),
+		submit: func(value string) error {
+			duration := u.options.DefaultSnooze
+			if value != "" {
+				var err error
+				duration, err = time.ParseDuration(value)
+				if err != nil || duration <= 0 {
+					return common.ErrTuiInvalidDuration
+				}
+			}
+
+			reminder.RemindAt = reminder.RemindAt.Add(duration)
+			err := u.client.ChangeReminder(int(reminder.ID), reminder)
+			if err != nil {
+				return err
+			}
+			logger.Info("ui: reminder postponed: " + strconv.FormatInt(reminder.ID, 10))
+			u.setNotice(fmt.Sprintf("Reminder #%d postponed till %s", reminder.ID, reminder.RemindAt.Format(u.options.TimeFormat)), false)
+			u.refresh()
+			return nil
+		},
+	}
+}
+
+func (u *ui) cancel() {
+	selected := u.selected()
+	if selected == nil {
+		return
+	}
+	reminder := *selected
+
+	u.prompt = &prompt{
+		label:   fmt.Sprintf("Cancel #%d %q? (y/n)", reminder.ID, reminder.Message),
+		confirm: true,
+		submit: func(string) error {
+			err := u.client.DeleteReminder(int(reminder.ID))
+			if err != nil {
+				return err
+			}
+			logger.Info("ui: reminder cancelled: " + strconv.FormatInt(reminder.ID, 10))
+			u.setNotice(fmt.Sprintf("Reminder #%d cancelled", reminder.ID), false)
+			u.refresh()
+			return nil
+		},
+	}
+}
+
+// snooze sets up the reminder with the same message as the last notification had
+func (u *ui) snooze() {
+	lastNotification := u.status.Scheduler.LastNotification
+	if lastNotification == nil {
+		u.setNotice(common.ErrTuiNothingToSnooze.Error(), true)
+		return
+	}
+
+	err := u.client.CreateReminder(common.Reminder{
+		Message:  lastNotification.Message,
+		RemindAt: time.Now().Add(u.options.DefaultSnooze),
+	})
+	if err != nil {
+		u.setNotice(err.Error(), true)
+		return
+	}
+	logger.Info("ui: last notification snoozed")
+	u.setNotice(fmt.Sprintf("%q snoozed for %s", lastNotification.Message, utils.HumanizeDuration(u.options.DefaultSnooze)), false)
+	u.refresh()
+}
+
+// search filters the list while typing: Enter keeps the filter, and Esc clears it
+func (u *ui) search() {
+	u.prompt = &prompt{
+		label:   "Filter",
+		input:   []rune(u.filter),
+		submit:  func(string) error { return nil },
+		change:  u.setFilter,
+		dismiss: func() { u.setFilter("") },
+	}
+}
+
+func (u *ui) setFilter(filter string) {
+	u.filter = strings.TrimSpace(filter)
+	u.offset = 0
+}
+
+func (u *ui) setNotice(notice string, isErr bool) {
+	u.notice = notice
+	u.noticeErr = isErr
+	u.noticeAt = time.Now()
+}
+
+// visible returns the reminders matching the filter: either by the message, case-insensitively, or by the ID
+func (u *ui) visible() []common.Reminder {
+	if u.filter == "" {
+		return u.reminders
+	}
+
+	filter := strings.ToLower(u.filter)
+	visible := make([]common.Reminder, 0, len(u.reminders))
+	for _, reminder := range u.reminders {
+		if strings.Contains(strings.ToLower(reminder.Message), filter) || strconv.FormatInt(reminder.ID, 10) == filter {
+			visible = append(visible, reminder)
+		}
+	}
+	return visible
+}
+
+// selectedIndex falls back to the first row if the selected reminder is gone: e.g. notified about or filtered out
+func (u *ui) selectedIndex(visible []common.Reminder) int {
+	for i, reminder := range visible {
+		if reminder.ID == u.selectedId {
+			return i
+		}
+	}
+	return 0
+}
+
+func (u *ui) selected() *common.Reminder {
+	visible := u.visible()
+	if len(visible) == 0 {
+		return nil
+	}
+	return &visible[u.selectedIndex(visible)]
+}
+
+func (u *ui) move(delta int) {
+	visible := u.visible()
+	if len(visible) == 0 {
+		return
+	}
+	index := min(max(u.selectedIndex(visible)+delta, 0), len(visible)-1)
+	u.selectedId = visible[index].ID
+}
+
+func (u *ui) pageSize() int {
+	return max(u.rowsHeight(), 1)
+}
+
+// parseRemindAt parses either the duration relative to now (e.g. "1h30m"), or the time today ("16:30"), or the date and time ("2006-01-02 16:30")
+func parseRemindAt(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d <= 0 {
+			return now, common.ErrTuiInvalidDuration
+		}
+		return now.Add(d), nil
+	}
+
+	var remindAt time.Time
+	if t, err := time.ParseInLocation(clockInputFormat, value, time.Local); err == nil {
+		remindAt = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+	} else if t, err := time.ParseInLocation(dateTimeInputFormat, value, time.Local); err == nil {
+		remindAt = t
+	} else {
+		return now, common.ErrTuiInvalidTime
+	}
+
+	if !remindAt.After(now) {
+		return now, common.ErrCmdTimeShouldBeInFuture
+	}
+	return remindAt, nil
+}
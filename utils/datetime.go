@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"fmt"
 	"n0rdy.foo/remindme/common"
 	"n0rdy.foo/remindme/logger"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -54,3 +57,101 @@ func AddDuration(t time.Time, seconds int, minutes int, hours int) time.Time {
 			time.Hour*time.Duration(hours),
 	)
 }
+
+// HumanizeDuration formats the duration with its 2 most significant units: e.g. "2h 14m", "3d 4h" or "45s"
+func HumanizeDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	d = d.Round(time.Second)
+
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+
+	// rounding to the least significant unit displayed, so that "2h 13m 58s" is "2h 14m" rather than "2h 13m"
+	for i, unit := range units {
+		if d >= unit.size {
+			if i+1 < len(units) {
+				d = d.Round(units[i+1].size)
+			}
+			break
+		}
+	}
+
+	parts := make([]string, 0, 2)
+	for _, unit := range units {
+		if len(parts) == 2 {
+			break
+		}
+		value := d / unit.size
+		if value > 0 {
+			parts = append(parts, strconv.FormatInt(int64(value), 10)+unit.suffix)
+			d -= value * unit.size
+		} else if len(parts) > 0 {
+			// the units should be adjacent: "1h 5s" is rather confusing, so it's "1h" instead
+			break
+		}
+	}
+
+	if len(parts) == 0 {
+		return "0s"
+	}
+	return strings.Join(parts, " ")
+}
+
+// RelativeTime describes the time relative to now: e.g. "in 2h 14m" or "5m 10s ago"
+func RelativeTime(t time.Time, now time.Time) string {
+	d := t.Sub(now)
+	if d.Round(time.Second) == 0 {
+		return "now"
+	}
+	if d > 0 {
+		return "in " + HumanizeDuration(d)
+	}
+	return HumanizeDuration(d) + " ago"
+}
+
+// FormatCountdown formats the duration as a clock: e.g. "24:59" or "1:05:00".
+// It's rounded up, so that the countdown starts with the requested duration, and shows "00:00" only when the time has come.
+func FormatCountdown(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = (d + time.Second - 1).Truncate(time.Second)
+
+	hours := d / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	seconds := (d % time.Minute) / time.Second
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+}
+
+// ParsePointInTime parses either the absolute time (RFC 3339, "2006-01-02 15:04:05", "2006-01-02" or "15:04" for today),
+// or the duration relative to now (e.g. "1h30m" stands for 1.5 hours ago)
+func ParsePointInTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(common.DateTimeFormatWithoutTimeZone, value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("15:04", value, time.Local); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.Local), nil
+	}
+	return now, common.ErrCmdWrongFormattedPointInTime
+}
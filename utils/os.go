@@ -1,12 +1,20 @@
 package utils
 
 import (
+	"errors"
+	"golang.org/x/term"
 	"n0rdy.foo/remindme/common"
 	"os"
 	"runtime"
+	"sort"
 	"strings"
 )
 
+const profilesDirName = "profiles"
+
+// the profile the current invocation works with, empty for the default one
+var profile string
+
 func DetectOsType() string {
 	return runtime.GOOS
 }
@@ -24,8 +32,57 @@ func DetectShellType() string {
 	return shellPaths[len(shellPaths)-1]
 }
 
-// based on this answer: https://stackoverflow.com/a/68740581
+// IsTerminal reports whether the file is a terminal rather than a pipe, a regular file or a device like /dev/null: e.g. the output is not redirected
+func IsTerminal(file *os.File) bool {
+	return term.IsTerminal(int(file.Fd()))
+}
+
+func SetProfile(name string) {
+	profile = name
+}
+
+// Profile returns the profile the current invocation works with, empty for the default one
+func Profile() string {
+	return profile
+}
+
+// GetOsSpecificAppDataDir returns the data dir of the current profile
 func GetOsSpecificAppDataDir() string {
+	return GetProfileAppDataDir(profile)
+}
+
+// GetProfileAppDataDir returns the data dir of the provided profile:
+// the default profile uses the root app data dir as the previous versions of the app did, while the named ones are nested within it
+func GetProfileAppDataDir(name string) string {
+	rootDir := getRootAppDataDir()
+	if rootDir == "" || name == "" {
+		return rootDir
+	}
+	return rootDir + profilesDirName + string(os.PathSeparator) + name + string(os.PathSeparator)
+}
+
+// ListProfiles returns the names of the profiles that have been used so far, except for the default one
+func ListProfiles() ([]string, error) {
+	entries, err := os.ReadDir(getRootAppDataDir() + profilesDirName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	profiles := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			profiles = append(profiles, entry.Name())
+		}
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// based on this answer: https://stackoverflow.com/a/68740581
+func getRootAppDataDir() string {
 	osType := DetectOsType()
 	switch osType {
 	case common.MacOS:
@@ -62,6 +119,21 @@ func GetOsSpecificAppDataDir() string {
 	}
 }
 
+// DefaultTransport returns the transport the server is started with if nothing else is requested:
+// Unix domain socket where file permissions can be relied on for access control, TCP otherwise
+func DefaultTransport() string {
+	switch DetectOsType() {
+	case common.LinuxOS, common.MacOS:
+		return common.UnixTransport
+	default:
+		return common.TcpTransport
+	}
+}
+
+func GetServerSocketPath() string {
+	return GetOsSpecificAppDataDir() + common.ServerSocketFileName
+}
+
 func sanitize(path string) string {
 	if strings.HasSuffix(path, string(os.PathSeparator)) {
 		return path
@@ -1,5 +1,25 @@
 package utils
 
+import (
+	"hash/fnv"
+	"n0rdy.foo/remindme/common"
+)
+
+// the number of the ports reserved for the named profiles right after the default one
+const profilePortsRange = 1000
+
 func IsPortValid(port int) bool {
 	return port >= 1 && port <= 65535
 }
+
+// DefaultProfilePort returns the TCP port the server of the profile listens to if nothing else is requested:
+// the named profiles get their own ports derived from their names, so that several servers can run side by side
+func DefaultProfilePort(name string) int {
+	if name == "" {
+		return common.DefaultHttpServerPort
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	return common.DefaultHttpServerPort + 1 + int(hash.Sum32()%profilePortsRange)
+}